@@ -17,6 +17,10 @@ const apiPort = "5042"
 // @description This is the API for the Better Mem project.
 // @contact.name Mateus Lacerda
 // @contact.email mateuslacerda253@gmail.com
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Api key as "Bearer <key>", only required when API_AUTH_ENABLED is set
 func main() {
 	if err := setup(); err != nil {
 		return
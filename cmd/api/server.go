@@ -8,7 +8,7 @@ import (
 	"log/slog"
 )
 
-func setup() {
+func setup() error {
 	slog.Info("api", "message", "connecting to mongo")
 	if err := mongo.TestMongo(); err != nil {
 		slog.Error("failed to connect to mongo", "error", err)
@@ -19,4 +19,5 @@ func setup() {
 		slog.Error("failed to connect to qdrant", "error", err)
 		return err
 	}
+	return nil
 }
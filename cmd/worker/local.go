@@ -42,6 +42,8 @@ func startScheduler() {}
 func startConsumer(
 	messageHandler *handler.MessageTaskHandler,
 	manageShortTermMemoryHandler *handler.MemoryManagementHandler,
+	webhookHandler *handler.WebhookTaskHandler,
+	retentionHandler *handler.RetentionTaskHandler,
 ) {
 
 	db, err := sqlite.GetDb().DB()
@@ -49,6 +51,12 @@ func startConsumer(
 		log.Fatal(err)
 	}
 	jqueue := liteq.New(db)
+	retentionScheduler := scheduler{
+		jqueue,
+		config.Retention.PurgeTaskPeriodInt,
+		task.PurgeInactiveMemoriesTaskName,
+		retentionHandler.HandlePurgeInactiveMemories,
+	}
 	scheduler := scheduler{
 		jqueue,
 		config.MemoryManagement.ManageSTMemoryTaskPeriodInt,
@@ -63,6 +71,14 @@ func startConsumer(
 			Worker:            messageHandler.HandleClassifyMemoryTask,
 		},
 	)
+	go jqueue.Consume(
+		context.Background(),
+		liteq.ConsumeParams{
+			Queue:             task.ClassifyMessageBatchTaskName,
+			VisibilityTimeout: 20,
+			Worker:            messageHandler.HandleClassifyMessageBatchTask,
+		},
+	)
 	go jqueue.Consume(
 		context.Background(),
 		liteq.ConsumeParams{
@@ -79,6 +95,22 @@ func startConsumer(
 			Worker:            messageHandler.HandleStoreShortTermMemoryTask,
 		},
 	)
+	go jqueue.Consume(
+		context.Background(),
+		liteq.ConsumeParams{
+			Queue:             task.DispatchWebhookTaskName,
+			VisibilityTimeout: 20,
+			Worker:            webhookHandler.HandleDispatchWebhookTask,
+		},
+	)
+	go jqueue.Consume(
+		context.Background(),
+		liteq.ConsumeParams{
+			Queue:             task.DeliverWebhookTaskName,
+			VisibilityTimeout: 20,
+			Worker:            webhookHandler.HandleDeliverWebhookTask,
+		},
+	)
 	go jqueue.Consume(
 		context.Background(),
 		liteq.ConsumeParams{
@@ -87,15 +119,25 @@ func startConsumer(
 			Worker:            scheduler.run,
 		},
 	)
-	err = jqueue.QueueJob(
+	go jqueue.Consume(
 		context.Background(),
-		liteq.QueueJobParams{
-			Queue: task.ManageMemoryTaskName,
-			Job:   `{}`,
+		liteq.ConsumeParams{
+			Queue:             task.PurgeInactiveMemoriesTaskName,
+			VisibilityTimeout: 20,
+			Worker:            retentionScheduler.run,
 		},
 	)
-	if err != nil {
-		log.Fatal(err)
+	for _, queue := range []string{task.ManageMemoryTaskName, task.PurgeInactiveMemoriesTaskName} {
+		err = jqueue.QueueJob(
+			context.Background(),
+			liteq.QueueJobParams{
+				Queue: queue,
+				Job:   `{}`,
+			},
+		)
+		if err != nil {
+			log.Fatal(err)
+		}
 	}
 }
 
@@ -105,13 +147,27 @@ func getRepositories() (
 	contracts.ShortTermMemoryRepository,
 	vectorContracts.MemoryVectorRepository,
 	uowContracts.UnitOfWork[int, any],
+	contracts.TenantRepository,
+	contracts.MessageRepository,
+	contracts.WebhookRepository,
+	contracts.WebhookDeliveryRepository,
+	contracts.MemoryEventRepository,
+	contracts.MemoryRetentionRepository,
+	contracts.RetentionRunRepository,
 ) {
 	chatRepository := repository.NewChatRepository()
 	longTermMemoryRepository := repository.NewLongTermMemoryRepository()
 	shortTermMemoryRepository := repository.NewShortTermMemoryRepository()
 	memoryVectorRepository := vectorRepo.NewMemoryRepository()
+	tenantRepository := repository.NewTenantRepository()
+	messageRepository := repository.NewMessageRepository()
+	webhookRepository := repository.NewWebhookRepository()
+	webhookDeliveryRepository := repository.NewWebhookDeliveryRepository()
+	memoryEventRepository := repository.NewMemoryEventRepository()
+	memoryRetentionRepository := repository.NewMemoryRetentionRepository()
+	retentionRunRepository := repository.NewRetentionRunRepository()
 	sqliteIntUow := uow.NewUnitOfWork[int, any](sqlite.GetDb())
 	sqlite.InitDb()
 	sqlite.Migrate(sqlite.GetDb())
-	return chatRepository, longTermMemoryRepository, shortTermMemoryRepository, memoryVectorRepository, sqliteIntUow
+	return chatRepository, longTermMemoryRepository, shortTermMemoryRepository, memoryVectorRepository, sqliteIntUow, tenantRepository, messageRepository, webhookRepository, webhookDeliveryRepository, memoryEventRepository, memoryRetentionRepository, retentionRunRepository
 }
@@ -28,15 +28,32 @@ func startServer() {
 	chatRepository,
 		longTermMemoryRepository,
 		shortTermMemoryRepository,
-		memoryVectorRepository, uow := getRepositories()
+		memoryVectorRepository, uow,
+		tenantRepository,
+		messageRepository,
+		webhookRepository,
+		webhookDeliveryRepository,
+		memoryEventRepository,
+		memoryRetentionRepository,
+		retentionRunRepository := getRepositories()
 
 	// Services
+	tenantService := service.NewTenantService(
+		tenantRepository,
+		chatRepository,
+		shortTermMemoryRepository,
+		longTermMemoryRepository,
+	)
 	longTermMemoryService := service.NewLongTermMemoryService(longTermMemoryRepository, chatRepository)
-	shortTermMemoryService := service.NewShortTermMemoryService(shortTermMemoryRepository, chatRepository)
-	chatService := service.NewChatService(chatRepository)
+	shortTermMemoryService := service.NewShortTermMemoryService(shortTermMemoryRepository, chatRepository, uow)
+	chatService := service.NewChatService(chatRepository, tenantService)
 	memoryVectorService := service.NewMemoryVectorService(memoryVectorRepository)
-	memoryManagementService := service.NewMemoryManagementService(uow)
+	memoryEventService := service.NewMemoryEventService(memoryEventRepository)
+	memoryManagementService := service.NewMemoryManagementService(uow, memoryEventService)
 	memoryEnhancementService := service.NewMemoryEnhancementService(llmProvider)
+	messageService := service.NewMessageService(messageRepository, chatRepository)
+	webhookService := service.NewWebhookService(webhookRepository, webhookDeliveryRepository, chatRepository)
+	retentionService := service.NewRetentionService(memoryRetentionRepository, retentionRunRepository, memoryVectorRepository)
 
 	// Handlers
 	messageHandler := handler.NewMessageTaskHandler(
@@ -44,12 +61,18 @@ func startServer() {
 		shortTermMemoryService,
 		memoryVectorService,
 		memoryEnhancementService,
+		tenantService,
+		messageService,
+		memoryEventService,
 	)
 	manageShortTermMemoryHandler := handler.NewMemoryManagementHandler(
 		chatService,
+		tenantService,
 		memoryManagementService,
 	)
-	startConsumer(messageHandler, manageShortTermMemoryHandler)
+	webhookHandler := handler.NewWebhookTaskHandler(webhookService, chatService)
+	retentionHandler := handler.NewRetentionTaskHandler(tenantService, retentionService)
+	startConsumer(messageHandler, manageShortTermMemoryHandler, webhookHandler, retentionHandler)
 }
 
 func waitForever() {
@@ -29,6 +29,10 @@ func startScheduler() {
 		config.MemoryManagement.ManageSTMemoryTaskPeriod,
 		task.NewManageShortTermMemoryTask(),
 	)
+	scheduler.Register(
+		config.Retention.PurgeTaskPeriod,
+		task.NewPurgeInactiveMemoriesTask(),
+	)
 	if err := scheduler.Run(); err != nil {
 		slog.Error("failed to run scheduler", "err", err)
 		return
@@ -38,6 +42,8 @@ func startScheduler() {
 func startConsumer(
 	messageHandler *handler.MessageTaskHandler,
 	manageShortTermMemoryHandler *handler.MemoryManagementHandler,
+	webhookHandler *handler.WebhookTaskHandler,
+	retentionHandler *handler.RetentionTaskHandler,
 ) {
 
 	server := asynq.NewServer(
@@ -54,6 +60,10 @@ func startConsumer(
 		task.ClassifyMessageTaskName,
 		messageHandler.HandleClassifyMemoryTask,
 	)
+	mux.HandleFunc(
+		task.ClassifyMessageBatchTaskName,
+		messageHandler.HandleClassifyMessageBatchTask,
+	)
 	mux.HandleFunc(
 		task.StoreLongTermMemoryTaskName,
 		messageHandler.HandleStoreLongTermMemoryTask,
@@ -62,10 +72,22 @@ func startConsumer(
 		task.StoreShortTermMemoryTaskName,
 		messageHandler.HandleStoreShortTermMemoryTask,
 	)
+	mux.HandleFunc(
+		task.DispatchWebhookTaskName,
+		webhookHandler.HandleDispatchWebhookTask,
+	)
+	mux.HandleFunc(
+		task.DeliverWebhookTaskName,
+		webhookHandler.HandleDeliverWebhookTask,
+	)
 	mux.HandleFunc(
 		task.ManageMemoryTaskName,
 		manageShortTermMemoryHandler.HandleManageMemory,
 	)
+	mux.HandleFunc(
+		task.PurgeInactiveMemoriesTaskName,
+		retentionHandler.HandlePurgeInactiveMemories,
+	)
 
 	if err := server.Run(mux); err != nil {
 		slog.Error("failed to run server", "err", err)
@@ -79,11 +101,25 @@ func getRepositories() (
 	contracts.ShortTermMemoryRepository,
 	vectorContracts.MemoryVectorRepository,
 	uowContracts.UnitOfWork[int, any],
+	contracts.TenantRepository,
+	contracts.MessageRepository,
+	contracts.WebhookRepository,
+	contracts.WebhookDeliveryRepository,
+	contracts.MemoryEventRepository,
+	contracts.MemoryRetentionRepository,
+	contracts.RetentionRunRepository,
 ) {
 	chatRepository := repository.NewChatRepository()
 	longTermMemoryRepository := repository.NewLongTermMemoryRepository()
 	shortTermMemoryRepository := repository.NewShortTermMemoryRepository()
 	memoryVectorRepository := vectorRepo.NewMemoryRepository()
+	tenantRepository := repository.NewTenantRepository()
+	messageRepository := repository.NewMessageRepository()
+	webhookRepository := repository.NewWebhookRepository()
+	webhookDeliveryRepository := repository.NewWebhookDeliveryRepository()
+	memoryEventRepository := repository.NewMemoryEventRepository()
+	memoryRetentionRepository := repository.NewMemoryRetentionRepository()
+	retentionRunRepository := repository.NewRetentionRunRepository()
 	mongoIntUow := uow.NewUnitOfWork[int](mongo.GetMongoClient())
-	return chatRepository, longTermMemoryRepository, shortTermMemoryRepository, memoryVectorRepository, mongoIntUow
+	return chatRepository, longTermMemoryRepository, shortTermMemoryRepository, memoryVectorRepository, mongoIntUow, tenantRepository, messageRepository, webhookRepository, webhookDeliveryRepository, memoryEventRepository, memoryRetentionRepository, retentionRunRepository
 }
@@ -39,6 +39,17 @@ type MemoryFetchRequest struct {
 	LongTermThreshold     float32 `json:"long_term_threshold"`
 }
 
+type MemoryContextRequest struct {
+	MemoryFetchRequest
+	Template string `json:"template"`
+	Subject  string `json:"subject"`
+}
+
+type MemoryContext struct {
+	Context  string         `json:"context"`
+	Memories []ScoredMemory `json:"memories"`
+}
+
 type MessageRelatedContext struct {
 	Context string `json:"context"`
 	User    string `json:"user"`
@@ -50,6 +61,15 @@ type ScoredMemory struct {
 	MemoryType     int                     `json:"memory_type"`
 	CreatedAt      time.Time               `json:"created_at"`
 	RelatedContext []MessageRelatedContext `json:"related_context"`
+	Tokens         int                     `json:"tokens"`
+}
+
+type MemoryFetchResult struct {
+	Memories           []ScoredMemory `json:"memories"`
+	Rejected           []ScoredMemory `json:"rejected"`
+	TokensUsed         int            `json:"tokens_used"`
+	Query              string         `json:"query"`
+	HypotheticalMemory string         `json:"hypothetical_memory"`
 }
 
 func (c *APIClient) CreateChat(externalID string) error {
@@ -143,3 +163,61 @@ func (c *APIClient) FetchMemories(chatID string, req MemoryFetchRequest) ([]Scor
 
 	return memories, nil
 }
+
+func (c *APIClient) FetchMemoriesDetailed(chatID string, req MemoryFetchRequest) (*MemoryFetchResult, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Post(
+		fmt.Sprintf("%s/memory/chat/%s/fetch/detailed", c.baseURL, chatID),
+		"application/json",
+		bytes.NewBuffer(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("falha ao buscar memórias: %s - %s", resp.Status, string(bodyBytes))
+	}
+
+	var result MemoryFetchResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *APIClient) FetchContext(chatID string, req MemoryContextRequest) (*MemoryContext, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Post(
+		fmt.Sprintf("%s/memory/chat/%s/context", c.baseURL, chatID),
+		"application/json",
+		bytes.NewBuffer(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("falha ao buscar contexto: %s - %s", resp.Status, string(bodyBytes))
+	}
+
+	var memoryContext MemoryContext
+	if err := json.NewDecoder(resp.Body).Decode(&memoryContext); err != nil {
+		return nil, err
+	}
+
+	return &memoryContext, nil
+}
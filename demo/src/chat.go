@@ -77,18 +77,24 @@ func (s *ChatSession) Start() error {
 		}
 
 		var memories []ScoredMemory
+		var memoryContext string
 		err = s.withBetterMemLoading(func() error {
-			var errFetch error
-			memories, errFetch = s.apiClient.FetchMemories(s.config.ChatID, MemoryFetchRequest{
-				Text:                  userInput,
-				Limit:                 s.config.Limit,
-				VectorSearchLimit:     s.config.VectorSearchLimit,
-				VectorSearchThreshold: s.config.VectorSearchThreshold,
-				LongTermThreshold:     s.config.LongTermThreshold,
+			fetched, errFetch := s.apiClient.FetchContext(s.config.ChatID, MemoryContextRequest{
+				MemoryFetchRequest: MemoryFetchRequest{
+					Text:                  userInput,
+					Limit:                 s.config.Limit,
+					VectorSearchLimit:     s.config.VectorSearchLimit,
+					VectorSearchThreshold: s.config.VectorSearchThreshold,
+					LongTermThreshold:     s.config.LongTermThreshold,
+				},
+				Template: "bullet",
+				Subject:  s.config.Name,
 			})
 			if errFetch != nil {
 				return errFetch
 			}
+			memories = fetched.Memories
+			memoryContext = fetched.Context
 
 			var relatedContext []MessageRelatedContext
 			historyMessages := s.history.GetMessages(s.config.ChatID, 2)
@@ -134,7 +140,7 @@ func (s *ChatSession) Start() error {
 		}
 
 		aiResponse, err := s.withLLMLoading(func(yield func(string)) (string, error) {
-			return s.generateResponseStreaming(userInput, memories, yield)
+			return s.generateResponseStreaming(userInput, memoryContext, yield)
 		})
 		if err != nil {
 			fmt.Printf("Erro ao gerar resposta: %v\n", err)
@@ -252,23 +258,14 @@ func (s *ChatSession) withLLMLoading(action func(func(string)) (string, error))
 	return res, nil
 }
 
-func (s *ChatSession) generateResponseStreaming(userInput string, memories []ScoredMemory, yield func(string)) (string, error) {
+func (s *ChatSession) generateResponseStreaming(userInput string, memoryContext string, yield func(string)) (string, error) {
 	var systemPrompt strings.Builder;
 
 	systemPrompt.WriteString("You are a helpful assistant")
 	
-	if len(memories) > 0 {
-		fmt.Fprintf(&systemPrompt, "\n\nThese are memories you have from past conversations with %s:\n", s.config.Name)
-		sort.Slice(memories, func(i, j int) bool {
-			return memories[i].Score > memories[j].Score
-		})
-		for _, mem := range memories {
-			fmt.Fprintf(&systemPrompt, "- %s (relevance: %.2f, created at: %s)\n", mem.Text, mem.Score, mem.CreatedAt)
-			systemPrompt .WriteString("  Related context:\n")
-			for _, context := range mem.RelatedContext {
-				fmt.Fprintf(&systemPrompt, "  - From: %s\n    %s\n", context.User, context.Context)
-			}
-		}
+	if memoryContext != "" {
+		systemPrompt.WriteString("\n\n")
+		systemPrompt.WriteString(memoryContext)
 	}
 
 	messages := []openai.ChatCompletionMessageParamUnion{
@@ -318,23 +315,14 @@ func (s *ChatSession) generateResponseStreaming(userInput string, memories []Sco
 	return fullResponse.String(), nil
 }
 
-func (s *ChatSession) generateResponse(userInput string, memories []ScoredMemory) (string, error) {
+func (s *ChatSession) generateResponse(userInput string, memoryContext string) (string, error) {
 	var systemPrompt strings.Builder;
 
 	systemPrompt.WriteString("You are a helpful assistant")
 	
-	if len(memories) > 0 {
-		fmt.Fprintf(&systemPrompt, "\n\nThese are memories you have from past conversations with %s:\n", s.config.Name)
-		sort.Slice(memories, func(i, j int) bool {
-			return memories[i].Score > memories[j].Score
-		})
-		for _, mem := range memories {
-			fmt.Fprintf(&systemPrompt, "- %s (relevance: %.2f, created at: %s)\n", mem.Text, mem.Score, mem.CreatedAt)
-			systemPrompt .WriteString("  Related context:\n")
-			for _, context := range mem.RelatedContext {
-				fmt.Fprintf(&systemPrompt, "  - From: %s\n    %s\n", context.User, context.Context)
-			}
-		}
+	if memoryContext != "" {
+		systemPrompt.WriteString("\n\n")
+		systemPrompt.WriteString(memoryContext)
 	}
 
 	messages := []openai.ChatCompletionMessageParamUnion{
@@ -18,45 +18,44 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/chat": {
+        "/admin/api-key": {
             "get": {
-                "description": "Get all chats",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Lists the issued api keys, without the keys themselves",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "chat"
+                    "admin"
                 ],
-                "summary": "Get all chats",
+                "summary": "Get api keys",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/core.Chat"
+                                "$ref": "#/definitions/core.ApiKey"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
-                        "schema": {
-                            "type": "string"
-                        }
+                        "schema": {}
                     }
                 }
             },
             "post": {
-                "description": "Create a new chat",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issues a new api key, the key is only shown in this response",
                 "consumes": [
                     "application/json"
                 ],
@@ -64,17 +63,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "chat"
+                    "admin"
                 ],
-                "summary": "Create a new chat",
+                "summary": "Issue api key",
                 "parameters": [
                     {
-                        "description": "chat",
-                        "name": "chat",
+                        "description": "New Api Key",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/core.NewChat"
+                            "$ref": "#/definitions/core.NewApiKey"
                         }
                     }
                 ],
@@ -82,73 +81,140 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/core.Chat"
+                            "$ref": "#/definitions/core.IssuedApiKey"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
-                        "schema": {
-                            "type": "string"
-                        }
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
                     },
                     "500": {
                         "description": "Internal Server Error",
-                        "schema": {
-                            "type": "string"
-                        }
+                        "schema": {}
                     }
                 }
             }
         },
-        "/health": {
+        "/admin/api-key/{api_key_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes an api key, requests using it are rejected from now on",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Revoke api key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Api Key ID",
+                        "name": "api_key_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/admin/retention/runs": {
             "get": {
-                "description": "Check if the API is running",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Lists the purges of inactive memories run by the worker, latest first, with what each one deleted",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "admin"
+                ],
+                "summary": "Get retention runs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit, defaults to 50",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Health Check",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.RetentionRun"
+                            }
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
                     }
                 }
             }
         },
-        "/memory/chat/{chat_id}/deactivate": {
-            "put": {
-                "description": "Deactivates all the memories, should be retried on fail since it may leave unwanted data.",
-                "tags": [
-                    "memories"
-                ],
-                "summary": "Deactivate All Memories",
-                "parameters": [
+        "/admin/tenant": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Chat ID",
-                        "name": "chat_id",
-                        "in": "path",
-                        "required": true
+                        "BearerAuth": []
                     }
                 ],
+                "description": "Lists the tenants, including the default one",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get tenants",
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.Tenant"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
                     }
                 }
-            }
-        },
-        "/memory/chat/{chat_id}/fetch": {
+            },
             "post": {
-                "description": "Fetch memories for a given chat.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a tenant, api keys issued for it only see its own chats and memories",
                 "consumes": [
                     "application/json"
                 ],
@@ -156,43 +222,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "memories"
+                    "admin"
                 ],
-                "summary": "Fetch Memories",
+                "summary": "Create tenant",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Chat ID",
-                        "name": "chat_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Fetch Memories Request",
+                        "description": "New Tenant",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/core.MemoryFetchRequest"
+                            "$ref": "#/definitions/core.NewTenant"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/core.ScoredMemory"
-                            }
+                            "$ref": "#/definitions/core.Tenant"
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
                     }
                 }
             }
         },
-        "/memory/long-term/chat/{chat_id}": {
-            "get": {
-                "description": "Get long term memories for a given chat.",
+        "/admin/tenant/{tenant_id}/quota": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the quota of a tenant, zero means unlimited",
                 "consumes": [
                     "application/json"
                 ],
@@ -200,93 +269,91 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "memories"
+                    "admin"
                 ],
-                "summary": "Get Long Term Memories",
+                "summary": "Update tenant quota",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Chat ID",
-                        "name": "chat_id",
+                        "description": "Tenant ID",
+                        "name": "tenant_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Quota",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/core.TenantQuota"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/core.LongTermMemory"
-                            }
+                            "$ref": "#/definitions/core.Tenant"
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
                     }
                 }
             }
         },
-        "/memory/short-term/chat/{chat_id}": {
+        "/admin/tenant/{tenant_id}/usage": {
             "get": {
-                "description": "Get short term memories for a given chat.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Returns how many chats and active memories a tenant holds, next to its quota",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "memories"
+                    "admin"
                 ],
-                "summary": "Get Short Term Memories",
+                "summary": "Get tenant usage",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Chat ID",
-                        "name": "chat_id",
+                        "description": "Tenant ID",
+                        "name": "tenant_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset",
-                        "name": "offset",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/core.ShortTermMemory"
-                            }
+                            "$ref": "#/definitions/core.TenantUsage"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
                     }
                 }
             }
         },
-        "/message": {
-            "post": {
-                "description": "Sends a message to classification queue",
+        "/chat": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all chats",
                 "consumes": [
                     "application/json"
                 ],
@@ -294,30 +361,306 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "message"
-                ],
-                "summary": "Add message",
-                "parameters": [
-                    {
-                        "description": "Message",
-                        "name": "message",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/core.NewMessage"
-                        }
-                    }
+                    "chat"
                 ],
+                "summary": "Get all chats",
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.MessageResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.Chat"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
-                        "schema": {}
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new chat",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chat"
+                ],
+                "summary": "Create a new chat",
+                "parameters": [
+                    {
+                        "description": "chat",
+                        "name": "chat",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/core.NewChat"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/core.Chat"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a chat with its memories from an export. The chat keeps its exported external id unless\nexternal_id is given. Memories without an embedding, or with one of another size than the vector\nstore's, are embedded again. Nothing is kept when the import fails",
+                "consumes": [
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chat"
+                ],
+                "summary": "Import a chat",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "External id of the imported chat",
+                        "name": "external_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Chat export",
+                        "name": "export",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/core.ChatImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/{chat_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permanently deletes the chat with its memories, related context, messages, merges,\nevents, webhooks, vectors and queued jobs. Unlike deactivating the memories, nothing can be recovered.\nThe report counts what was deleted and what was found after the purge, verified is true when nothing was left",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chat"
+                ],
+                "summary": "Delete a chat",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.ChatDeletionReport"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/{chat_id}/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Exports the chat with all its short and long term memories, active or not, with their counters\nand related context, as JSONL: a header line with the format version, a chat line and a line per memory.\nWith embeddings=true each memory also carries its embedding",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "chat"
+                ],
+                "summary": "Export a chat",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the embeddings",
+                        "name": "embeddings",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.ChatExportLine"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/{chat_id}/messages": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the transcript of a chat, oldest first, with the label and the memory each message produced or was merged into",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "message"
+                ],
+                "summary": "Get chat messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit, defaults to 50",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.MessageArray"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -325,42 +668,2481 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "core.Chat": {
+        },
+        "/health": {
+            "get": {
+                "description": "Check if the API is running",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health Check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/memory/chat/{chat_id}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stores a memory directly as short term (1) or long term (2), skipping the classifier.\nA memory similar to an existing one is merged into it (short term) or not duplicated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Create Memory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New Memory",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/core.NewMemory"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.MemoryStoreResult"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/core.MemoryStoreResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/memory/chat/{chat_id}/context": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch memories for a given chat and render them into a block of text ready to go in a prompt.\nThe template is bullet, xml, json or one registered from CONTEXT_TEMPLATES_DIR.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Fetch Memory Context",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Memory Context Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/core.MemoryContextRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.MemoryContext"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/memory/chat/{chat_id}/deactivate": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deactivates all the memories, should be retried on fail since it may leave unwanted data.",
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Deactivate All Memories",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/memory/chat/{chat_id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Server-sent events stream of the memory changes of a chat: memory.created, memory.merged,\nmemory.promoted, memory.deactivated and memory.discarded, with the event as JSON data.\nEach event id is its cursor, reconnecting with it as since (or Last-Event-ID) replays what was missed.\nWithout a cursor only the events from now on are sent.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Stream Memory Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cursor of the last event received, 0 replays every event",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.MemoryEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/memory/chat/{chat_id}/fetch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch memories for a given chat.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Fetch Memories",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fetch Memories Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/core.MemoryFetchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.ScoredMemory"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/memory/chat/{chat_id}/fetch/detailed": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Same fetch as /fetch, returning the memories along with the rejected candidates (with explain),\nthe tokens used (with max_tokens) and the query and hypothetical memory the search ran with.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Fetch Memories Detailed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fetch Memories Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/core.MemoryFetchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.MemoryFetchResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/memory/fetch/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Runs several fetches, possibly across chats, in one call. The texts are embedded at once and the\nfetches run concurrently, each item carries the result of its fetch as /fetch/detailed returns it,\nor its error. Every chat is validated before anything is fetched",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Fetch Memories Batch",
+                "parameters": [
+                    {
+                        "description": "Fetches",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/core.MemoryFetchBatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.MemoryFetchBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/memory/long-term/chat/{chat_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get long term memories for a given chat.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Get Long Term Memories",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.LongTermMemory"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/memory/long-term/chat/{chat_id}/{memory_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a single long term memory.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Get Long Term Memory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Memory ID",
+                        "name": "memory_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.LongTermMemory"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deactivates a single long term memory and its vector.",
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Delete Long Term Memory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Memory ID",
+                        "name": "memory_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates a single long term memory, the memory is re-embedded when its text changes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Update Long Term Memory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Memory ID",
+                        "name": "memory_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Memory Update Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/core.MemoryUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.LongTermMemory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/memory/long-term/chat/{chat_id}/{memory_id}/provenance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the messages that created a long term memory or were merged into it, with their original text.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Get Long Term Memory Provenance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Memory ID",
+                        "name": "memory_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.MemoryProvenance"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/memory/short-term/chat/{chat_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get short term memories for a given chat.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Get Short Term Memories",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.ShortTermMemory"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/memory/short-term/chat/{chat_id}/{memory_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a single short term memory.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Get Short Term Memory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Memory ID",
+                        "name": "memory_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.ShortTermMemory"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deactivates a single short term memory and its vector.",
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Delete Short Term Memory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Memory ID",
+                        "name": "memory_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates a single short term memory, the memory is re-embedded when its text changes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Update Short Term Memory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Memory ID",
+                        "name": "memory_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Memory Update Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/core.MemoryUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.ShortTermMemory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/memory/short-term/chat/{chat_id}/{memory_id}/merges": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every merge of a short term memory with the text it replaced, oldest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Get Short Term Memory Merges",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Memory ID",
+                        "name": "memory_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.MemoryMergeHistory"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/memory/short-term/chat/{chat_id}/{memory_id}/merges/{merge_id}/revert": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore a short term memory to the text and related context it had before the given merge.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Revert Short Term Memory Merge",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Memory ID",
+                        "name": "memory_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Merge ID",
+                        "name": "merge_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.ShortTermMemory"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/memory/short-term/chat/{chat_id}/{memory_id}/provenance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the messages that created a short term memory or were merged into it, with their original text.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memories"
+                ],
+                "summary": "Get Short Term Memory Provenance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Memory ID",
+                        "name": "memory_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.MemoryProvenance"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/message": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sends a message to classification queue, with wait=true the request blocks until the message is processed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "message"
+                ],
+                "summary": "Add message",
+                "parameters": [
+                    {
+                        "description": "Message",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/core.NewMessage"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wait for the message to be processed",
+                        "name": "wait",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seconds to wait, defaults to WORKER_MESSAGE_WAIT_TIMEOUT and up to WORKER_MESSAGE_WAIT_MAX_TIMEOUT",
+                        "name": "timeout",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.MessageTask"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/v1.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/message/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sends several messages, possibly across chats, to the classification queue at once. Every chat is validated before anything is queued",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "message"
+                ],
+                "summary": "Add messages",
+                "parameters": [
+                    {
+                        "description": "Messages",
+                        "name": "messages",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/core.NewMessageBatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/v1.MessageBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/message/task/{task_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reports what happened to a message: queued, classifying, retrying, stored, merged, covered (by a similar memory), discarded (NoMemory) or failed once no attempt is left",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "message"
+                ],
+                "summary": "Get message task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.MessageTask"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/webhook": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the webhooks of the tenant, without their secrets",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Get webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.Webhook"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Registers a url to be called when memories of a chat, or of every chat, change.\nEach call is signed with the secret, which is only shown in this response:\nX-BetterMem-Signature is \"sha256=\" followed by the hex HMAC-SHA256 of \"\u003cX-BetterMem-Timestamp\u003e.\u003cbody\u003e\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Register webhook",
+                "parameters": [
+                    {
+                        "description": "New Webhook",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/core.NewWebhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/core.RegisteredWebhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/webhook/{webhook_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a webhook, pending retries are dropped",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Delete webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/webhook/{webhook_id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the delivery log of a webhook, one entry per attempt, the latest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Get webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit, defaults to 50",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "core.ApiKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "A name to identify the key",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "The first characters of the key, to tell keys apart",
+                    "type": "string"
+                },
+                "revoked": {
+                    "type": "boolean"
+                },
+                "scopes": {
+                    "description": "The scopes granted to the key",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.ApiKeyScope"
+                    }
+                },
+                "tenant_id": {
+                    "description": "The tenant the key acts on behalf of",
+                    "type": "string"
+                }
+            }
+        },
+        "core.ApiKeyScope": {
+            "type": "string",
+            "enum": [
+                "memory:read",
+                "memory:write",
+                "chat:admin",
+                "admin"
+            ],
+            "x-enum-varnames": [
+                "MemoryReadScope",
+                "MemoryWriteScope",
+                "ChatAdminScope",
+                "AdminScope"
+            ]
+        },
+        "core.Chat": {
+            "type": "object",
+            "properties": {
+                "external_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "core.ChatDataCounts": {
+            "type": "object",
+            "properties": {
+                "chats": {
+                    "type": "integer"
+                },
+                "long_term_memories": {
+                    "type": "integer"
+                },
+                "memory_events": {
+                    "type": "integer"
+                },
+                "memory_merges": {
+                    "type": "integer"
+                },
+                "messages": {
+                    "type": "integer"
+                },
+                "queued_jobs": {
+                    "description": "Jobs waiting in the queue with data of the chat",
+                    "type": "integer"
+                },
+                "related_contexts": {
+                    "description": "Related context attached to memories, messages and merges",
+                    "type": "integer"
+                },
+                "short_term_memories": {
+                    "type": "integer"
+                },
+                "vectors": {
+                    "type": "integer"
+                },
+                "webhook_deliveries": {
+                    "type": "integer"
+                },
+                "webhooks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "core.ChatDeletionReport": {
+            "type": "object",
+            "properties": {
+                "chat_id": {
+                    "description": "The external id of the chat",
+                    "type": "string"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "deleted": {
+                    "$ref": "#/definitions/core.ChatDataCounts"
+                },
+                "remaining": {
+                    "description": "Counted again after the purge, every store should be empty",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.ChatDataCounts"
+                        }
+                    ]
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "verified": {
+                    "description": "True when nothing of the chat was found after the purge",
+                    "type": "boolean"
+                }
+            }
+        },
+        "core.ChatExportHeader": {
+            "type": "object",
+            "properties": {
+                "dimension": {
+                    "description": "Size of the embeddings, zero without them",
+                    "type": "integer"
+                },
+                "embeddings": {
+                    "description": "Whether the memories carry their embeddings",
+                    "type": "boolean"
+                },
+                "exported_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "core.ChatExportLine": {
+            "type": "object",
+            "properties": {
+                "chat": {
+                    "$ref": "#/definitions/core.NewChat"
+                },
+                "embedding": {
+                    "description": "Embedding of the memory, when exported with embeddings",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "header": {
+                    "$ref": "#/definitions/core.ChatExportHeader"
+                },
+                "long_term_memory": {
+                    "$ref": "#/definitions/core.LongTermMemory"
+                },
+                "short_term_memory": {
+                    "$ref": "#/definitions/core.ShortTermMemory"
+                },
+                "type": {
+                    "$ref": "#/definitions/core.ChatExportLineType"
+                }
+            }
+        },
+        "core.ChatExportLineType": {
+            "type": "string",
+            "enum": [
+                "header",
+                "chat",
+                "short_term_memory",
+                "long_term_memory"
+            ],
+            "x-enum-varnames": [
+                "ChatExportHeaderLine",
+                "ChatExportChatLine",
+                "ChatExportShortTermMemoryLine",
+                "ChatExportLongTermMemoryLine"
+            ]
+        },
+        "core.ChatImportReport": {
+            "type": "object",
+            "properties": {
+                "chat_id": {
+                    "description": "The external id of the imported chat",
+                    "type": "string"
+                },
+                "long_term_memories": {
+                    "type": "integer"
+                },
+                "reembedded": {
+                    "description": "Memories embedded again since their embedding was missing\nor of another size than the vector store's",
+                    "type": "integer"
+                },
+                "short_term_memories": {
+                    "type": "integer"
+                }
+            }
+        },
+        "core.ConversationTurn": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "The message",
+                    "type": "string",
+                    "example": "I have a cat and a dog"
+                },
+                "role": {
+                    "description": "Who sent the message, e.g. user or assistant",
+                    "type": "string",
+                    "example": "user"
+                }
+            }
+        },
+        "core.FetchMode": {
+            "type": "string",
+            "enum": [
+                "vector",
+                "keyword",
+                "hybrid"
+            ],
+            "x-enum-varnames": [
+                "FetchModeVector",
+                "FetchModeKeyword",
+                "FetchModeHybrid"
+            ]
+        },
+        "core.IssuedApiKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "description": "The key to be sent as \"Authorization: Bearer \u003ckey\u003e\"",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "A name to identify the key",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "The first characters of the key, to tell keys apart",
+                    "type": "string"
+                },
+                "revoked": {
+                    "type": "boolean"
+                },
+                "scopes": {
+                    "description": "The scopes granted to the key",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.ApiKeyScope"
+                    }
+                },
+                "tenant_id": {
+                    "description": "The tenant the key acts on behalf of",
+                    "type": "string"
+                }
+            }
+        },
+        "core.LongTermMemory": {
+            "type": "object",
+            "properties": {
+                "access_count": {
+                    "type": "integer"
+                },
+                "active": {
+                    "type": "boolean"
+                },
+                "chat_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "memory": {
+                    "type": "string"
+                },
+                "related_context": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.MessageRelatedContext"
+                    }
+                }
+            }
+        },
+        "core.MemoryContext": {
+            "type": "object",
+            "properties": {
+                "context": {
+                    "description": "The rendered text, empty when no memory was found",
+                    "type": "string"
+                },
+                "memories": {
+                    "description": "The memories that were rendered",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.ScoredMemory"
+                    }
+                },
+                "tokens_used": {
+                    "description": "Tokens taken by the memories when fetching with a token budget",
+                    "type": "integer"
+                }
+            }
+        },
+        "core.MemoryContextRequest": {
+            "type": "object",
+            "properties": {
+                "diversity": {
+                    "description": "Trade-off between relevance and novelty when picking the memories, from 0 to 1.\nZero disables the maximal marginal relevance re-ranking (Default: 0)",
+                    "type": "number",
+                    "example": 0.3
+                },
+                "explain": {
+                    "description": "Returns the score breakdown of each memory and the rejected candidates (Default: false)",
+                    "type": "boolean",
+                    "example": false
+                },
+                "hyde": {
+                    "description": "Also searches with a hypothetical memory written by the llm to answer the text, keeping\nthe best similarity of each memory. Ignored by keyword searches and without an llm (Default: false)",
+                    "type": "boolean",
+                    "example": true
+                },
+                "include_related_context": {
+                    "description": "Counts the related context in the budget and returns it, it is left out otherwise (Default: false)",
+                    "type": "boolean",
+                    "example": true
+                },
+                "limit": {
+                    "description": "Max number of memories to be returned (Default: 2)",
+                    "type": "integer",
+                    "example": 2
+                },
+                "long_term_threshold": {
+                    "description": "Specific threshold for long term memories (Default: 0.8)",
+                    "type": "number",
+                    "example": 0.6
+                },
+                "max_tokens": {
+                    "description": "Packs the best memories until this many tokens instead of taking Limit of them,\nthe response then carries the tokens used (Default: 0, disabled)",
+                    "type": "integer",
+                    "example": 500
+                },
+                "mode": {
+                    "description": "How candidates are searched: vector, keyword or hybrid (Default: vector)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.FetchMode"
+                        }
+                    ],
+                    "example": "hybrid"
+                },
+                "query_strategy": {
+                    "description": "How the query is built from Turns: embedding or rewrite (Default: CONVERSATION_QUERY_STRATEGY)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.QueryStrategy"
+                        }
+                    ],
+                    "example": "embedding"
+                },
+                "rerank": {
+                    "description": "Rescores the candidates with the cross-encoder of the inference service before\ntruncating to the limit, the score becomes the reranker score (Default: false)",
+                    "type": "boolean",
+                    "example": true
+                },
+                "scorer": {
+                    "description": "How the memories are scored: relevancy, half_life or weighted (Default: SCORING_DEFAULT_SCORER)",
+                    "type": "string",
+                    "example": "weighted"
+                },
+                "subject": {
+                    "description": "Who the memories are about, used by the templates in their header",
+                    "type": "string",
+                    "example": "Mateus"
+                },
+                "template": {
+                    "description": "Template the memories are rendered with: bullet, xml, json or a registered one (Default: CONTEXT_DEFAULT_TEMPLATE)",
+                    "type": "string",
+                    "example": "bullet"
+                },
+                "text": {
+                    "description": "Text to be searched",
+                    "type": "string",
+                    "example": "I love smart LLMs"
+                },
+                "tokenizer": {
+                    "description": "How tokens are counted for MaxTokens: chars or words (Default: chars)",
+                    "type": "string",
+                    "example": "chars"
+                },
+                "turns": {
+                    "description": "Previous messages of the conversation, oldest first and without Text. They are used to\nresolve follow ups such as \"what about the other one?\" into a useful query",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.ConversationTurn"
+                    }
+                },
+                "vector_search_limit": {
+                    "description": "Max number of memories to be returned from vector search (Default: 10)",
+                    "type": "integer",
+                    "example": 10
+                },
+                "vector_search_threshold": {
+                    "description": "Min score to considerate a memory (Default: 0.6)",
+                    "type": "number",
+                    "example": 0.4
+                },
+                "weights": {
+                    "description": "Overrides the configured scorer parameters for this fetch",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.ScoringWeights"
+                        }
+                    ]
+                }
+            }
+        },
+        "core.MemoryEvent": {
+            "type": "object",
+            "properties": {
+                "chat_id": {
+                    "description": "The external id of the chat",
+                    "type": "string"
+                },
+                "cursor": {
+                    "description": "Position of the event in the event log, streams resume after it",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "memory": {
+                    "description": "The memory text after the event",
+                    "type": "string"
+                },
+                "memory_id": {
+                    "description": "The memory the event is about, empty when every memory\nof the chat was deactivated at once",
+                    "type": "string"
+                },
+                "memory_type": {
+                    "$ref": "#/definitions/core.MemoryTypeEnum"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "promoted_from": {
+                    "description": "The short term memory a promoted memory came from",
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/core.MemoryEventType"
+                }
+            }
+        },
+        "core.MemoryEventType": {
+            "type": "string",
+            "enum": [
+                "memory.created",
+                "memory.merged",
+                "memory.promoted",
+                "memory.deactivated",
+                "memory.discarded"
+            ],
+            "x-enum-varnames": [
+                "MemoryCreatedEvent",
+                "MemoryMergedEvent",
+                "MemoryPromotedEvent",
+                "MemoryDeactivatedEvent",
+                "MemoryDiscardedEvent"
+            ]
+        },
+        "core.MemoryFetchBatch": {
+            "type": "object",
+            "properties": {
+                "fetches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.MemoryFetchBatchEntry"
+                    }
+                }
+            }
+        },
+        "core.MemoryFetchBatchEntry": {
+            "type": "object",
+            "properties": {
+                "chat_id": {
+                    "description": "The chat to fetch from",
+                    "type": "string",
+                    "example": "chat-1"
+                },
+                "diversity": {
+                    "description": "Trade-off between relevance and novelty when picking the memories, from 0 to 1.\nZero disables the maximal marginal relevance re-ranking (Default: 0)",
+                    "type": "number",
+                    "example": 0.3
+                },
+                "explain": {
+                    "description": "Returns the score breakdown of each memory and the rejected candidates (Default: false)",
+                    "type": "boolean",
+                    "example": false
+                },
+                "hyde": {
+                    "description": "Also searches with a hypothetical memory written by the llm to answer the text, keeping\nthe best similarity of each memory. Ignored by keyword searches and without an llm (Default: false)",
+                    "type": "boolean",
+                    "example": true
+                },
+                "include_related_context": {
+                    "description": "Counts the related context in the budget and returns it, it is left out otherwise (Default: false)",
+                    "type": "boolean",
+                    "example": true
+                },
+                "limit": {
+                    "description": "Max number of memories to be returned (Default: 2)",
+                    "type": "integer",
+                    "example": 2
+                },
+                "long_term_threshold": {
+                    "description": "Specific threshold for long term memories (Default: 0.8)",
+                    "type": "number",
+                    "example": 0.6
+                },
+                "max_tokens": {
+                    "description": "Packs the best memories until this many tokens instead of taking Limit of them,\nthe response then carries the tokens used (Default: 0, disabled)",
+                    "type": "integer",
+                    "example": 500
+                },
+                "mode": {
+                    "description": "How candidates are searched: vector, keyword or hybrid (Default: vector)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.FetchMode"
+                        }
+                    ],
+                    "example": "hybrid"
+                },
+                "query_strategy": {
+                    "description": "How the query is built from Turns: embedding or rewrite (Default: CONVERSATION_QUERY_STRATEGY)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.QueryStrategy"
+                        }
+                    ],
+                    "example": "embedding"
+                },
+                "rerank": {
+                    "description": "Rescores the candidates with the cross-encoder of the inference service before\ntruncating to the limit, the score becomes the reranker score (Default: false)",
+                    "type": "boolean",
+                    "example": true
+                },
+                "scorer": {
+                    "description": "How the memories are scored: relevancy, half_life or weighted (Default: SCORING_DEFAULT_SCORER)",
+                    "type": "string",
+                    "example": "weighted"
+                },
+                "text": {
+                    "description": "Text to be searched",
+                    "type": "string",
+                    "example": "I love smart LLMs"
+                },
+                "tokenizer": {
+                    "description": "How tokens are counted for MaxTokens: chars or words (Default: chars)",
+                    "type": "string",
+                    "example": "chars"
+                },
+                "turns": {
+                    "description": "Previous messages of the conversation, oldest first and without Text. They are used to\nresolve follow ups such as \"what about the other one?\" into a useful query",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.ConversationTurn"
+                    }
+                },
+                "vector_search_limit": {
+                    "description": "Max number of memories to be returned from vector search (Default: 10)",
+                    "type": "integer",
+                    "example": 10
+                },
+                "vector_search_threshold": {
+                    "description": "Min score to considerate a memory (Default: 0.6)",
+                    "type": "number",
+                    "example": 0.4
+                },
+                "weights": {
+                    "description": "Overrides the configured scorer parameters for this fetch",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.ScoringWeights"
+                        }
+                    ]
+                }
+            }
+        },
+        "core.MemoryFetchBatchItem": {
+            "type": "object",
+            "properties": {
+                "chat_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "hypothetical_memory": {
+                    "description": "Hypothetical memory the search also ran with, when fetching with hyde",
+                    "type": "string"
+                },
+                "memories": {
+                    "description": "The fetched memories, as returned by the plain fetch",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.ScoredMemory"
+                    }
+                },
+                "query": {
+                    "description": "Query the search ran with, when it was rewritten from the conversation turns",
+                    "type": "string"
+                },
+                "rejected": {
+                    "description": "Candidates dropped by the vector search or long term thresholds",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.ScoredMemory"
+                    }
+                },
+                "tokens_used": {
+                    "description": "Tokens taken by the memories when fetching with a token budget",
+                    "type": "integer"
+                }
+            }
+        },
+        "core.MemoryFetchRequest": {
+            "type": "object",
+            "properties": {
+                "diversity": {
+                    "description": "Trade-off between relevance and novelty when picking the memories, from 0 to 1.\nZero disables the maximal marginal relevance re-ranking (Default: 0)",
+                    "type": "number",
+                    "example": 0.3
+                },
+                "explain": {
+                    "description": "Returns the score breakdown of each memory and the rejected candidates (Default: false)",
+                    "type": "boolean",
+                    "example": false
+                },
+                "hyde": {
+                    "description": "Also searches with a hypothetical memory written by the llm to answer the text, keeping\nthe best similarity of each memory. Ignored by keyword searches and without an llm (Default: false)",
+                    "type": "boolean",
+                    "example": true
+                },
+                "include_related_context": {
+                    "description": "Counts the related context in the budget and returns it, it is left out otherwise (Default: false)",
+                    "type": "boolean",
+                    "example": true
+                },
+                "limit": {
+                    "description": "Max number of memories to be returned (Default: 2)",
+                    "type": "integer",
+                    "example": 2
+                },
+                "long_term_threshold": {
+                    "description": "Specific threshold for long term memories (Default: 0.8)",
+                    "type": "number",
+                    "example": 0.6
+                },
+                "max_tokens": {
+                    "description": "Packs the best memories until this many tokens instead of taking Limit of them,\nthe response then carries the tokens used (Default: 0, disabled)",
+                    "type": "integer",
+                    "example": 500
+                },
+                "mode": {
+                    "description": "How candidates are searched: vector, keyword or hybrid (Default: vector)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.FetchMode"
+                        }
+                    ],
+                    "example": "hybrid"
+                },
+                "query_strategy": {
+                    "description": "How the query is built from Turns: embedding or rewrite (Default: CONVERSATION_QUERY_STRATEGY)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.QueryStrategy"
+                        }
+                    ],
+                    "example": "embedding"
+                },
+                "rerank": {
+                    "description": "Rescores the candidates with the cross-encoder of the inference service before\ntruncating to the limit, the score becomes the reranker score (Default: false)",
+                    "type": "boolean",
+                    "example": true
+                },
+                "scorer": {
+                    "description": "How the memories are scored: relevancy, half_life or weighted (Default: SCORING_DEFAULT_SCORER)",
+                    "type": "string",
+                    "example": "weighted"
+                },
+                "text": {
+                    "description": "Text to be searched",
+                    "type": "string",
+                    "example": "I love smart LLMs"
+                },
+                "tokenizer": {
+                    "description": "How tokens are counted for MaxTokens: chars or words (Default: chars)",
+                    "type": "string",
+                    "example": "chars"
+                },
+                "turns": {
+                    "description": "Previous messages of the conversation, oldest first and without Text. They are used to\nresolve follow ups such as \"what about the other one?\" into a useful query",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.ConversationTurn"
+                    }
+                },
+                "vector_search_limit": {
+                    "description": "Max number of memories to be returned from vector search (Default: 10)",
+                    "type": "integer",
+                    "example": 10
+                },
+                "vector_search_threshold": {
+                    "description": "Min score to considerate a memory (Default: 0.6)",
+                    "type": "number",
+                    "example": 0.4
+                },
+                "weights": {
+                    "description": "Overrides the configured scorer parameters for this fetch",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.ScoringWeights"
+                        }
+                    ]
+                }
+            }
+        },
+        "core.MemoryFetchResult": {
+            "type": "object",
+            "properties": {
+                "hypothetical_memory": {
+                    "description": "Hypothetical memory the search also ran with, when fetching with hyde",
+                    "type": "string"
+                },
+                "memories": {
+                    "description": "The fetched memories, as returned by the plain fetch",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.ScoredMemory"
+                    }
+                },
+                "query": {
+                    "description": "Query the search ran with, when it was rewritten from the conversation turns",
+                    "type": "string"
+                },
+                "rejected": {
+                    "description": "Candidates dropped by the vector search or long term thresholds",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.ScoredMemory"
+                    }
+                },
+                "tokens_used": {
+                    "description": "Tokens taken by the memories when fetching with a token budget",
+                    "type": "integer"
+                }
+            }
+        },
+        "core.MemoryMerge": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "incoming_memory": {
+                    "description": "The text that replaced it",
+                    "type": "string"
+                },
+                "memory_id": {
+                    "type": "string"
+                },
+                "previous_memory": {
+                    "description": "The memory before the merge, restored by a revert",
+                    "type": "string"
+                },
+                "previous_related_context": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.MessageRelatedContext"
+                    }
+                },
+                "score": {
+                    "description": "Similarity between the memory and the incoming text",
+                    "type": "number"
+                }
+            }
+        },
+        "core.MemoryMergeHistory": {
+            "type": "object",
+            "properties": {
+                "memory_id": {
+                    "type": "string"
+                },
+                "merges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.MemoryMerge"
+                    }
+                }
+            }
+        },
+        "core.MemoryProvenance": {
+            "type": "object",
+            "properties": {
+                "memory_id": {
+                    "type": "string"
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.MemorySource"
+                    }
+                }
+            }
+        },
+        "core.MemoryScoreExplanation": {
+            "type": "object",
+            "properties": {
+                "access_count": {
+                    "description": "Times the memory was fetched",
+                    "type": "integer"
+                },
+                "below_vector_search_threshold": {
+                    "description": "The vector similarity is under the vector search threshold",
+                    "type": "boolean"
+                },
+                "filtered_by_long_term_threshold": {
+                    "description": "The long term memory was filtered out by the long term threshold",
+                    "type": "boolean"
+                },
+                "keyword_score": {
+                    "description": "Share of the query words in the memory, when found by the keyword search",
+                    "type": "number"
+                },
+                "merge_count": {
+                    "description": "Times a message was merged into the memory (short term only)",
+                    "type": "integer"
+                },
+                "recency_score": {
+                    "description": "Recency part of the score",
+                    "type": "number"
+                },
+                "rerank_score": {
+                    "description": "Score given by the cross-encoder, when reranked",
+                    "type": "number"
+                },
+                "scorer_score": {
+                    "description": "Score given by the scorer of the fetch",
+                    "type": "number"
+                },
+                "usage_score": {
+                    "description": "Usage part of the score, from the access and merge counts",
+                    "type": "number"
+                },
+                "vector_similarity": {
+                    "description": "Cosine similarity to the query, when found by the vector search",
+                    "type": "number"
+                }
+            }
+        },
+        "core.MemorySource": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "When the message was sent",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "The text as it was sent, before any enhancement",
+                    "type": "string"
+                },
+                "message_id": {
+                    "type": "string"
+                },
+                "related_context": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.MessageRelatedContext"
+                    }
+                },
+                "status": {
+                    "description": "stored when the message created the memory,\nmerged when it was merged into it",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.MessageTaskStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "core.MemoryStoreResult": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "Whether a new memory was created",
+                    "type": "boolean"
+                },
+                "memory_id": {
+                    "description": "The id of the resulting memory",
+                    "type": "string"
+                },
+                "memory_type": {
+                    "description": "The type of the resulting memory",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.MemoryTypeEnum"
+                        }
+                    ]
+                },
+                "merged": {
+                    "description": "Whether the memory was merged into a similar one",
+                    "type": "boolean"
+                }
+            }
+        },
+        "core.MemoryTypeEnum": {
+            "type": "integer",
+            "enum": [
+                0,
+                1,
+                2
+            ],
+            "x-enum-varnames": [
+                "NoMemory",
+                "ShortTerm",
+                "LongTerm"
+            ]
+        },
+        "core.MemoryUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "memory": {
+                    "description": "New text of the memory, the memory is re-embedded when it changes",
+                    "type": "string",
+                    "example": "The user loves smart LLMs"
+                },
+                "related_context": {
+                    "description": "Replaces the related context when present",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.MessageRelatedContext"
+                    }
+                }
+            }
+        },
+        "core.Message": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "chat_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "label": {
+                    "description": "The predicted label",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.MemoryTypeEnum"
+                        }
+                    ]
+                },
+                "memory_id": {
+                    "description": "The memory the message produced or was merged into",
+                    "type": "string"
+                },
+                "merged": {
+                    "description": "Whether the message was merged into an existing memory",
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "related_context": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.MessageRelatedContext"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/core.MessageTaskStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "core.MessageArray": {
+            "type": "object",
+            "properties": {
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.Message"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "core.MessageBatchItem": {
+            "type": "object",
+            "properties": {
+                "chat_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "description": "The task tracking the message, empty if it was not queued",
+                    "type": "string"
+                }
+            }
+        },
+        "core.MessageRelatedContext": {
+            "type": "object",
+            "properties": {
+                "context": {
+                    "description": "The text that was used to generate the memory",
+                    "type": "string"
+                },
+                "user": {
+                    "description": "User that generated the context, might be a name\nor simply \"user and assistant\"",
+                    "type": "string"
+                }
+            }
+        },
+        "core.MessageTask": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "How many times the worker picked the message up",
+                    "type": "integer"
+                },
+                "chat_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "The error of the last attempt",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "label": {
+                    "description": "The classification of the message",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.MemoryTypeEnum"
+                        }
+                    ]
+                },
+                "memory_id": {
+                    "description": "The memory the message was stored as or merged into",
+                    "type": "string"
+                },
+                "merged": {
+                    "description": "Whether the message was merged into an existing memory",
+                    "type": "boolean"
+                },
+                "status": {
+                    "$ref": "#/definitions/core.MessageTaskStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "core.MessageTaskStatus": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "classifying",
+                "stored",
+                "merged",
+                "covered",
+                "discarded",
+                "retrying",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "MessageQueued",
+                "MessageClassifying",
+                "MessageStored",
+                "MessageMerged",
+                "MessageCovered",
+                "MessageDiscarded",
+                "MessageRetrying",
+                "MessageFailed"
+            ]
+        },
+        "core.NewApiKey": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "A name to identify the key",
+                    "type": "string",
+                    "example": "support-dashboard"
+                },
+                "scopes": {
+                    "description": "The scopes granted to the key",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.ApiKeyScope"
+                    },
+                    "example": [
+                        "memory:read",
+                        "memory:write"
+                    ]
+                },
+                "tenant_id": {
+                    "description": "The tenant the key acts on behalf of (Default: default)",
+                    "type": "string",
+                    "example": "acme"
+                }
+            }
+        },
+        "core.NewChat": {
             "type": "object",
             "properties": {
                 "external_id": {
                     "type": "string"
+                }
+            }
+        },
+        "core.NewMemory": {
+            "type": "object",
+            "properties": {
+                "memory": {
+                    "description": "Text of the memory",
+                    "type": "string",
+                    "example": "The user loves smart LLMs"
+                },
+                "memory_type": {
+                    "description": "Type of the memory, 1 for short term and 2 for long term",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.MemoryTypeEnum"
+                        }
+                    ],
+                    "example": 2
                 },
-                "id": {
-                    "type": "string"
+                "related_context": {
+                    "description": "Context that might be related to the memory",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.MessageRelatedContext"
+                    }
                 }
             }
         },
-        "core.LongTermMemory": {
+        "core.NewMessage": {
             "type": "object",
             "properties": {
-                "access_count": {
-                    "type": "integer"
-                },
-                "active": {
-                    "type": "boolean"
-                },
                 "chat_id": {
+                    "description": "The chat id that the message belongs to",
                     "type": "string"
                 },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "memory": {
+                "message": {
+                    "description": "The message text",
                     "type": "string"
                 },
                 "related_context": {
+                    "description": "The related context",
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/core.MessageRelatedContext"
@@ -368,87 +3150,159 @@ const docTemplate = `{
                 }
             }
         },
-        "core.MemoryFetchRequest": {
+        "core.NewMessageBatch": {
             "type": "object",
             "properties": {
-                "limit": {
-                    "description": "Max number of memories to be returned (Default: 2)",
-                    "type": "integer",
-                    "example": 2
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.NewMessage"
+                    }
+                }
+            }
+        },
+        "core.NewTenant": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Unique identifier of the tenant",
+                    "type": "string",
+                    "example": "acme"
                 },
-                "long_term_threshold": {
-                    "description": "Specific threshold for long term memories (Default: 0.8)",
-                    "type": "number",
-                    "example": 0.6
+                "name": {
+                    "description": "A name to identify the tenant",
+                    "type": "string",
+                    "example": "Acme Inc."
                 },
-                "text": {
-                    "description": "Text to be searched",
+                "quota": {
+                    "description": "Limits applied to the tenant, the configured defaults are used when omitted",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.TenantQuota"
+                        }
+                    ]
+                }
+            }
+        },
+        "core.NewWebhook": {
+            "type": "object",
+            "properties": {
+                "chat_id": {
+                    "description": "The external id of the chat to listen to, every chat when empty",
                     "type": "string",
-                    "example": "I love smart LLMs"
+                    "example": "1234567890"
                 },
-                "vector_search_limit": {
-                    "description": "Max number of memories to be returned from vector search (Default: 10)",
-                    "type": "integer",
-                    "example": 10
+                "events": {
+                    "description": "The events to listen to, every event when empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.MemoryEventType"
+                    },
+                    "example": [
+                        "memory.created",
+                        "memory.promoted"
+                    ]
                 },
-                "vector_search_threshold": {
-                    "description": "Min score to considerate a memory (Default: 0.6)",
-                    "type": "number",
-                    "example": 0.4
+                "url": {
+                    "description": "Where the events are posted to",
+                    "type": "string",
+                    "example": "https://example.com/better-mem"
                 }
             }
         },
-        "core.MemoryTypeEnum": {
-            "type": "integer",
+        "core.QueryStrategy": {
+            "type": "string",
             "enum": [
-                0,
-                1,
-                2
+                "embedding",
+                "rewrite"
             ],
             "x-enum-varnames": [
-                "NoMemory",
-                "ShortTerm",
-                "LongTerm"
+                "QueryStrategyEmbedding",
+                "QueryStrategyRewrite"
             ]
         },
-        "core.MessageRelatedContext": {
+        "core.RegisteredWebhook": {
             "type": "object",
             "properties": {
-                "context": {
-                    "description": "The text that was used to generate the memory",
+                "chat_id": {
+                    "description": "The chat listened to, every chat when empty",
                     "type": "string"
                 },
-                "user": {
-                    "description": "User that generated the context, might be a name\nor simply \"user and assistant\"",
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "description": "The events listened to, every event when empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.MemoryEventType"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Key of the HMAC-SHA256 sent in the X-BetterMem-Signature header",
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "core.NewChat": {
+        "core.RetentionPurgeStats": {
             "type": "object",
             "properties": {
-                "external_id": {
-                    "type": "string"
+                "long_term_memories": {
+                    "type": "integer"
+                },
+                "memory_merges": {
+                    "type": "integer"
+                },
+                "orphaned_related_contexts": {
+                    "description": "Related context no longer attached to any memory, message or merge",
+                    "type": "integer"
+                },
+                "short_term_memories": {
+                    "type": "integer"
+                },
+                "vectors": {
+                    "type": "integer"
                 }
             }
         },
-        "core.NewMessage": {
+        "core.RetentionRun": {
             "type": "object",
             "properties": {
-                "chat_id": {
-                    "description": "The chat id that the message belongs to",
+                "completed_at": {
                     "type": "string"
                 },
-                "message": {
-                    "description": "The message text",
+                "cutoff": {
+                    "description": "Memories deactivated before this were deleted",
                     "type": "string"
                 },
-                "related_context": {
-                    "description": "The related context",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/core.MessageRelatedContext"
-                    }
+                "deleted": {
+                    "$ref": "#/definitions/core.RetentionPurgeStats"
+                },
+                "error": {
+                    "description": "Why the run stopped early, what was deleted until then is kept",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "retention_days": {
+                    "description": "How many days a memory stays inactive before being deleted",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "tenants": {
+                    "type": "integer"
                 }
             }
         },
@@ -459,6 +3313,14 @@ const docTemplate = `{
                     "description": "The date the memory was created",
                     "type": "string"
                 },
+                "explanation": {
+                    "description": "How the score was reached, only set in explain mode",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/core.MemoryScoreExplanation"
+                        }
+                    ]
+                },
                 "id": {
                     "description": "The id of the memory",
                     "type": "string"
@@ -485,6 +3347,35 @@ const docTemplate = `{
                 "text": {
                     "description": "The text that was used to generate the memory",
                     "type": "string"
+                },
+                "tokens": {
+                    "description": "Tokens the memory takes, only set when fetching with a token budget",
+                    "type": "integer"
+                }
+            }
+        },
+        "core.ScoringWeights": {
+            "type": "object",
+            "properties": {
+                "half_life_hours": {
+                    "description": "Hours for the recency to halve (half_life and weighted scorers)",
+                    "type": "number",
+                    "example": 72
+                },
+                "recency": {
+                    "description": "Weight of the recency (weighted scorer)",
+                    "type": "number",
+                    "example": 0.25
+                },
+                "similarity": {
+                    "description": "Weight of the search similarity (weighted scorer)",
+                    "type": "number",
+                    "example": 0.6
+                },
+                "usage": {
+                    "description": "Weight of the access and merge counts (weighted scorer)",
+                    "type": "number",
+                    "example": 0.15
                 }
             }
         },
@@ -523,14 +3414,162 @@ const docTemplate = `{
                 }
             }
         },
+        "core.Tenant": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quota": {
+                    "$ref": "#/definitions/core.TenantQuota"
+                }
+            }
+        },
+        "core.TenantQuota": {
+            "type": "object",
+            "properties": {
+                "max_chats": {
+                    "description": "Max number of chats the tenant can create",
+                    "type": "integer",
+                    "example": 100
+                },
+                "max_memories": {
+                    "description": "Max number of active memories, short and long term, the tenant can hold",
+                    "type": "integer",
+                    "example": 10000
+                }
+            }
+        },
+        "core.TenantUsage": {
+            "type": "object",
+            "properties": {
+                "chats": {
+                    "type": "integer"
+                },
+                "memories": {
+                    "type": "integer"
+                },
+                "quota": {
+                    "$ref": "#/definitions/core.TenantQuota"
+                },
+                "tenant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "core.Webhook": {
+            "type": "object",
+            "properties": {
+                "chat_id": {
+                    "description": "The chat listened to, every chat when empty",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "description": "The events listened to, every event when empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.MemoryEventType"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "core.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "description": "How long the request took, in milliseconds",
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "$ref": "#/definitions/core.MemoryEventType"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "description": "Status code of the response, 0 when there was no response",
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.MemoryFetchBatchResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.MemoryFetchBatchItem"
+                    }
+                }
+            }
+        },
+        "v1.MessageBatchResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.MessageBatchItem"
+                    }
+                }
+            }
+        },
         "v1.MessageResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string"
+                },
+                "task_id": {
+                    "description": "Use it to follow the message at /message/task/{task_id}",
+                    "type": "string"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Api key as \"Bearer \u003ckey\u003e\", only required when API_AUTH_ENABLED is set",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
@@ -10,6 +10,7 @@ require (
 	github.com/khepin/liteq v0.1.1
 	github.com/mattn/go-sqlite3 v1.14.33
 	github.com/qdrant/go-client v1.15.2
+	github.com/redis/go-redis/v9 v9.14.0
 	github.com/swaggo/files v1.0.1
 	github.com/swaggo/gin-swagger v1.6.1
 	github.com/swaggo/swag v1.16.6
@@ -77,7 +78,6 @@ require (
 	github.com/montanaflynn/stats v0.7.1 // indirect
 	github.com/openai/openai-go v1.12.0
 	github.com/pelletier/go-toml/v2 v2.2.4 // indirect
-	github.com/redis/go-redis/v9 v9.14.0 // indirect
 	github.com/robfig/cron/v3 v3.0.1 // indirect
 	github.com/spf13/cast v1.10.0 // indirect
 	github.com/twitchyliquid64/golang-asm v0.15.1 // indirect
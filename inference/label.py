@@ -8,12 +8,16 @@ from tokenizers import Tokenizer
 from numpy import ndarray, argmax, mean
 
 import concurrent.futures
+import math
+import os
 from concurrent.futures import ThreadPoolExecutor
 
 _session = None
 _tokenizer = None
 _embedder_tokenizer = None
 _embedder_model = None
+_reranker_tokenizer = None
+_reranker_model = None
 
 logger = logging.getLogger(__name__)
 
@@ -46,13 +50,32 @@ def start_embedder(
     _embedder_tokenizer = Tokenizer.from_file(embedder_tokenizer_path)
 
 
+def start_reranker(
+    reranker_model_path: str,
+    reranker_tokenizer_path: str,
+) -> bool:
+    """Loads the cross-encoder, it is optional so a missing model only disables reranking."""
+    global _reranker_model
+    global _reranker_tokenizer
+    if not os.path.exists(reranker_model_path) or not os.path.exists(reranker_tokenizer_path):
+        logger.warning("Reranker model not found at %s, reranking is disabled", reranker_model_path)
+        return False
+    _reranker_model = ort.InferenceSession(
+        reranker_model_path,
+        sess_options=ort.SessionOptions(),
+        providers=ort.get_available_providers(),
+    )
+    _reranker_tokenizer = Tokenizer.from_file(reranker_tokenizer_path)
+    return True
+
 
 class InferencePool:
     """Thread-based inference pool that works better with asyncio"""
 
     def __init__(self, model_path: str, tokenizer_path: str,
                  embedder_model_path: str, embedder_tokenizer_path: str,
-                 pool_size: int = 1):
+                 pool_size: int = 1,
+                 reranker_model_path: str = "", reranker_tokenizer_path: str = ""):
         self.pool_size = pool_size
         self.executor = ThreadPoolExecutor(max_workers=pool_size)
 
@@ -60,6 +83,7 @@ class InferencePool:
         start_session(model_path)
         start_tokenizer(tokenizer_path)
         start_embedder(embedder_model_path, embedder_tokenizer_path)
+        self.reranker_enabled = start_reranker(reranker_model_path, reranker_tokenizer_path)
 
         logger.info(
             "Created inference pool with %d workers (model: %s, tokenizer: %s, embedder_model: %s, embedder_tokenizer: %s)",
@@ -86,12 +110,15 @@ def create_inference_pool(
     embedder_model_path: str,
     embedder_tokenizer_path: str,
     pool_size: int = 1,
+    reranker_model_path: str = "",
+    reranker_tokenizer_path: str = "",
 ) -> InferencePool:
     """Create a thread-based inference pool."""
     return InferencePool(
         model_path, tokenizer_path,
         embedder_model_path, embedder_tokenizer_path,
-        pool_size
+        pool_size,
+        reranker_model_path, reranker_tokenizer_path,
     )
 
 
@@ -175,6 +202,40 @@ def _embed_in_worker(input_data: str) -> list:
         return []
 
 
+def _embed_batch_in_worker(inputs: Sequence[str]) -> list:
+    """Embeds every input within a single worker task."""
+    return [_embed_in_worker(input_data) for input_data in inputs]
+
+
+def _rerank_in_worker(query: str, candidates: Sequence[str]) -> list:
+    """Scores each (query, candidate) pair with the cross-encoder, from 0 to 1."""
+    global _reranker_model
+    global _reranker_tokenizer
+    try:
+        if _reranker_model is None:
+            raise Exception("Reranker model not initialized in worker")
+        if _reranker_tokenizer is None:
+            raise Exception("Reranker tokenizer not initialized in worker")
+
+        scores = []
+        for candidate in candidates:
+            # The pair is encoded together so the model attends across both
+            encoded = _reranker_tokenizer.encode(query, candidate)
+            model_input = {
+                "input_ids": [encoded.ids],
+                "attention_mask": [encoded.attention_mask],
+                "token_type_ids": [encoded.type_ids],
+            }
+            result = _reranker_model.run(None, model_input)
+            logit = float(result[0].reshape(-1)[0])
+            scores.append(1 / (1 + math.exp(-logit)))
+        return scores
+
+    except Exception as e:
+        logger.error(f"Error in worker reranking: {e}")
+        return []
+
+
 async def yield_input_ids(input_ids: Sequence) -> AsyncGenerator:
     for token in input_ids:
         yield token
@@ -210,3 +271,32 @@ async def embed(
     except Exception as e:
         logger.error(f"Error embedding: {e}")
         return []
+
+
+async def embed_batch(
+    inputs: Sequence[str],
+    inference_pool: InferencePool,
+) -> list:
+    """Run embedding of several inputs using thread pool."""
+    try:
+        loop = asyncio.get_event_loop()
+        result = await loop.run_in_executor(inference_pool.executor, _embed_batch_in_worker, inputs)
+        return result
+    except Exception as e:
+        logger.error(f"Error embedding batch: {e}")
+        return []
+
+
+async def rerank(
+    query: str,
+    candidates: Sequence[str],
+    inference_pool: InferencePool,
+) -> list:
+    """Run reranking using thread pool."""
+    try:
+        loop = asyncio.get_event_loop()
+        result = await loop.run_in_executor(inference_pool.executor, _rerank_in_worker, query, candidates)
+        return result
+    except Exception as e:
+        logger.error(f"Error reranking: {e}")
+        return []
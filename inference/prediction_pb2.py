@@ -24,7 +24,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x10prediction.proto\">\n\x11PredictionRequest\x12\x0f\n\x07message\x18\x01 \x01(\t\x12\x18\n\x10return_embedding\x18\x02 \x01(\x08\"6\n\x12PredictionResponse\x12\r\n\x05label\x18\x01 \x01(\x05\x12\x11\n\tembedding\x18\x02 \x03(\x02\"\x1f\n\x0c\x45mbedRequest\x12\x0f\n\x07message\x18\x01 \x01(\t\"\"\n\rEmbedResponse\x12\x11\n\tembedding\x18\x01 \x03(\x02\x32l\n\nPrediction\x12\x34\n\x07Predict\x12\x12.PredictionRequest\x1a\x13.PredictionResponse\"\x00\x12(\n\x05\x45mbed\x12\r.EmbedRequest\x1a\x0e.EmbedResponse\"\x00\x42\x1cZ\x1a\x62\x65tter-mem/internal/protosb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x10prediction.proto\">\n\x11PredictionRequest\x12\x0f\n\x07message\x18\x01 \x01(\t\x12\x18\n\x10return_embedding\x18\x02 \x01(\x08\"6\n\x12PredictionResponse\x12\r\n\x05label\x18\x01 \x01(\x05\x12\x11\n\tembedding\x18\x02 \x03(\x02\"\x1f\n\x0c\x45mbedRequest\x12\x0f\n\x07message\x18\x01 \x01(\t\"\"\n\rEmbedResponse\x12\x11\n\tembedding\x18\x01 \x03(\x02\"%\n\x11\x45mbedBatchRequest\x12\x10\n\x08messages\x18\x01 \x03(\t\"8\n\x12\x45mbedBatchResponse\x12\"\n\nembeddings\x18\x01 \x03(\x0b\x32\x0e.EmbedResponse\"2\n\rRerankRequest\x12\r\n\x05query\x18\x01 \x01(\t\x12\x12\n\ncandidates\x18\x02 \x03(\t\" \n\x0eRerankResponse\x12\x0e\n\x06scores\x18\x01 \x03(\x02\x32\xd2\x01\n\nPrediction\x12\x34\n\x07Predict\x12\x12.PredictionRequest\x1a\x13.PredictionResponse\"\x00\x12(\n\x05\x45mbed\x12\r.EmbedRequest\x1a\x0e.EmbedResponse\"\x00\x12\x37\n\nEmbedBatch\x12\x12.EmbedBatchRequest\x1a\x13.EmbedBatchResponse\"\x00\x12+\n\x06Rerank\x12\x0e.RerankRequest\x1a\x0f.RerankResponse\"\x00\x42\x1cZ\x1a\x62\x65tter-mem/internal/protosb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -40,6 +40,14 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['_EMBEDREQUEST']._serialized_end=171
   _globals['_EMBEDRESPONSE']._serialized_start=173
   _globals['_EMBEDRESPONSE']._serialized_end=207
-  _globals['_PREDICTION']._serialized_start=209
-  _globals['_PREDICTION']._serialized_end=317
+  _globals['_EMBEDBATCHREQUEST']._serialized_start=209
+  _globals['_EMBEDBATCHREQUEST']._serialized_end=246
+  _globals['_EMBEDBATCHRESPONSE']._serialized_start=248
+  _globals['_EMBEDBATCHRESPONSE']._serialized_end=304
+  _globals['_RERANKREQUEST']._serialized_start=306
+  _globals['_RERANKREQUEST']._serialized_end=356
+  _globals['_RERANKRESPONSE']._serialized_start=358
+  _globals['_RERANKRESPONSE']._serialized_end=390
+  _globals['_PREDICTION']._serialized_start=393
+  _globals['_PREDICTION']._serialized_end=603
 # @@protoc_insertion_point(module_scope)
@@ -44,6 +44,16 @@ class PredictionStub(object):
                 request_serializer=prediction__pb2.EmbedRequest.SerializeToString,
                 response_deserializer=prediction__pb2.EmbedResponse.FromString,
                 _registered_method=True)
+        self.EmbedBatch = channel.unary_unary(
+                '/Prediction/EmbedBatch',
+                request_serializer=prediction__pb2.EmbedBatchRequest.SerializeToString,
+                response_deserializer=prediction__pb2.EmbedBatchResponse.FromString,
+                _registered_method=True)
+        self.Rerank = channel.unary_unary(
+                '/Prediction/Rerank',
+                request_serializer=prediction__pb2.RerankRequest.SerializeToString,
+                response_deserializer=prediction__pb2.RerankResponse.FromString,
+                _registered_method=True)
 
 
 class PredictionServicer(object):
@@ -63,6 +73,20 @@ class PredictionServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def EmbedBatch(self, request, context):
+        """Embeds several messages in one call.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def Rerank(self, request, context):
+        """Scores how relevant each candidate is to the query with a cross-encoder.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_PredictionServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -76,6 +100,16 @@ def add_PredictionServicer_to_server(servicer, server):
                     request_deserializer=prediction__pb2.EmbedRequest.FromString,
                     response_serializer=prediction__pb2.EmbedResponse.SerializeToString,
             ),
+            'EmbedBatch': grpc.unary_unary_rpc_method_handler(
+                    servicer.EmbedBatch,
+                    request_deserializer=prediction__pb2.EmbedBatchRequest.FromString,
+                    response_serializer=prediction__pb2.EmbedBatchResponse.SerializeToString,
+            ),
+            'Rerank': grpc.unary_unary_rpc_method_handler(
+                    servicer.Rerank,
+                    request_deserializer=prediction__pb2.RerankRequest.FromString,
+                    response_serializer=prediction__pb2.RerankResponse.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'Prediction', rpc_method_handlers)
@@ -140,3 +174,57 @@ class Prediction(object):
             timeout,
             metadata,
             _registered_method=True)
+
+    @staticmethod
+    def EmbedBatch(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/Prediction/EmbedBatch',
+            prediction__pb2.EmbedBatchRequest.SerializeToString,
+            prediction__pb2.EmbedBatchResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def Rerank(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/Prediction/Rerank',
+            prediction__pb2.RerankRequest.SerializeToString,
+            prediction__pb2.RerankResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
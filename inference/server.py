@@ -4,10 +4,11 @@ import grpc
 from grpc import aio
 # import message_pb2
 from prediction_pb2 import (
-    PredictionRequest, PredictionResponse, EmbedRequest, EmbedResponse
+    PredictionRequest, PredictionResponse, EmbedRequest, EmbedResponse,
+    EmbedBatchRequest, EmbedBatchResponse, RerankRequest, RerankResponse,
 )
 from prediction_pb2_grpc import PredictionServicer, add_PredictionServicer_to_server
-from label import InferencePool, predict, embed, create_inference_pool
+from label import InferencePool, predict, embed, embed_batch, rerank, create_inference_pool
 
 
 class Server(PredictionServicer):
@@ -36,6 +37,26 @@ class Server(PredictionServicer):
         context.set_details("Tokenization successful")
         return EmbedResponse(embedding=embedding)
 
+    async def EmbedBatch(self, request: EmbedBatchRequest, context):
+        print(f"[EMBED_BATCH] Request received: {len(request.messages)} messages", flush=True)
+        embeddings = await embed_batch(list(request.messages), self.inference_pool)
+        if len(embeddings) != len(request.messages):
+            await context.abort(grpc.StatusCode.INTERNAL, "Batch embedding failed")
+        context.set_code(grpc.StatusCode.OK)
+        context.set_details("Batch embedding successful")
+        return EmbedBatchResponse(embeddings=[EmbedResponse(embedding=embedding) for embedding in embeddings])
+
+    async def Rerank(self, request: RerankRequest, context):
+        print(f"[RERANK] Request received: {len(request.candidates)} candidates", flush=True)
+        if not self.inference_pool.reranker_enabled:
+            await context.abort(grpc.StatusCode.FAILED_PRECONDITION, "Reranker model not loaded")
+        scores = await rerank(request.query, list(request.candidates), self.inference_pool)
+        if len(scores) != len(request.candidates):
+            await context.abort(grpc.StatusCode.INTERNAL, "Reranking failed")
+        context.set_code(grpc.StatusCode.OK)
+        context.set_details("Reranking successful")
+        return RerankResponse(scores=scores)
+
 
 async def serve(pool_size: int = 3, wait_for_termination: bool = True) -> None | tuple[aio.Server, InferencePool]:
     print("TODO: Add a relashionship inference, that predicts if two messages are related as contradiction, entailment or neutral", flush=True)
@@ -45,6 +66,8 @@ async def serve(pool_size: int = 3, wait_for_termination: bool = True) -> None |
         embedder_model_path="models/embedding/model.onnx",
         embedder_tokenizer_path="models/embedding/tokenizer.json",
         pool_size=pool_size,
+        reranker_model_path="models/rerank/model.onnx",
+        reranker_tokenizer_path="models/rerank/tokenizer.json",
     )
     print(f"Created inference pool with pool_size={pool_size}...", flush=True)
     port = 50051
@@ -0,0 +1,231 @@
+package v1
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/service"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	apiKeyService    *service.ApiKeyService
+	tenantService    *service.TenantService
+	retentionService *service.RetentionService
+}
+
+func NewAdminHandler(
+	apiKeyService *service.ApiKeyService,
+	tenantService *service.TenantService,
+	retentionService *service.RetentionService,
+) *AdminHandler {
+	return &AdminHandler{
+		apiKeyService:    apiKeyService,
+		tenantService:    tenantService,
+		retentionService: retentionService,
+	}
+}
+
+// @Summary Issue api key
+// @Description Issues a new api key, the key is only shown in this response
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body core.NewApiKey true "New Api Key"
+// @Success 201 {object} core.IssuedApiKey
+// @Failure 400 {object} any
+// @Failure 500 {object} any
+// @Router /admin/api-key [post]
+func (h *AdminHandler) IssueApiKey(context *gin.Context) {
+	var request core.NewApiKey
+	if err := context.BindJSON(&request); err != nil {
+		context.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	apiKey, err := h.apiKeyService.Issue(context, &request)
+	if errors.Is(err, core.InvalidApiKeyScope) || errors.Is(err, core.TenantNotFound) {
+		context.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(201, apiKey)
+}
+
+// @Summary Get api keys
+// @Description Lists the issued api keys, without the keys themselves
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} core.ApiKey
+// @Failure 500 {object} any
+// @Router /admin/api-key [get]
+func (h *AdminHandler) GetApiKeys(context *gin.Context) {
+	apiKeys, err := h.apiKeyService.GetAll(context)
+	if err != nil {
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if apiKeys == nil {
+		context.JSON(200, []*core.ApiKey{})
+		return
+	}
+	context.JSON(200, apiKeys)
+}
+
+// @Summary Revoke api key
+// @Description Revokes an api key, requests using it are rejected from now on
+// @Tags admin
+// @Security BearerAuth
+// @Param api_key_id path string true "Api Key ID"
+// @Success 204
+// @Failure 404 {object} any
+// @Router /admin/api-key/{api_key_id} [delete]
+func (h *AdminHandler) RevokeApiKey(context *gin.Context) {
+	err := h.apiKeyService.Revoke(context, context.Param("api_key_id"))
+	if errors.Is(err, core.ApiKeyNotFound) {
+		context.JSON(404, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	context.Status(204)
+}
+
+// @Summary Create tenant
+// @Description Creates a tenant, api keys issued for it only see its own chats and memories
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body core.NewTenant true "New Tenant"
+// @Success 201 {object} core.Tenant
+// @Failure 400 {object} any
+// @Failure 500 {object} any
+// @Router /admin/tenant [post]
+func (h *AdminHandler) CreateTenant(context *gin.Context) {
+	var request core.NewTenant
+	if err := context.BindJSON(&request); err != nil {
+		context.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	tenant, err := h.tenantService.Create(context, &request)
+	if errors.Is(err, core.EmptyTenantId) || errors.Is(err, core.TenantAlreadyExists) {
+		context.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(201, tenant)
+}
+
+// @Summary Get tenants
+// @Description Lists the tenants, including the default one
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} core.Tenant
+// @Failure 500 {object} any
+// @Router /admin/tenant [get]
+func (h *AdminHandler) GetTenants(context *gin.Context) {
+	tenants, err := h.tenantService.GetAll(context)
+	if err != nil {
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(200, tenants)
+}
+
+// @Summary Update tenant quota
+// @Description Replaces the quota of a tenant, zero means unlimited
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tenant_id path string true "Tenant ID"
+// @Param request body core.TenantQuota true "Quota"
+// @Success 200 {object} core.Tenant
+// @Failure 400 {object} any
+// @Failure 404 {object} any
+// @Router /admin/tenant/{tenant_id}/quota [put]
+func (h *AdminHandler) UpdateTenantQuota(context *gin.Context) {
+	var quota core.TenantQuota
+	if err := context.BindJSON(&quota); err != nil {
+		context.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	tenant, err := h.tenantService.UpdateQuota(context, context.Param("tenant_id"), quota)
+	if errors.Is(err, core.TenantNotFound) {
+		context.JSON(404, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(200, tenant)
+}
+
+// @Summary Get tenant usage
+// @Description Returns how many chats and active memories a tenant holds, next to its quota
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {object} core.TenantUsage
+// @Failure 404 {object} any
+// @Router /admin/tenant/{tenant_id}/usage [get]
+func (h *AdminHandler) GetTenantUsage(context *gin.Context) {
+	usage, err := h.tenantService.Usage(context, context.Param("tenant_id"))
+	if errors.Is(err, core.TenantNotFound) {
+		context.JSON(404, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(200, usage)
+}
+
+// @Summary Get retention runs
+// @Description Lists the purges of inactive memories run by the worker, latest first, with what each one deleted
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit, defaults to 50"
+// @Param offset query int false "Offset"
+// @Success 200 {array} core.RetentionRun
+// @Failure 400 {object} any
+// @Failure 500 {object} any
+// @Router /admin/retention/runs [get]
+func (h *AdminHandler) GetRetentionRuns(context *gin.Context) {
+	limit, err := strconv.Atoi(context.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		context.JSON(400, gin.H{"error": "Invalid limit"})
+		return
+	}
+	offset, err := strconv.Atoi(context.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		context.JSON(400, gin.H{"error": "Invalid offset"})
+		return
+	}
+	runs, err := h.retentionService.GetRuns(context, limit, offset)
+	if err != nil {
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if runs == nil {
+		context.JSON(200, []*core.RetentionRun{})
+		return
+	}
+	context.JSON(200, runs)
+}
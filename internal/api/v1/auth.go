@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
+	"github.com/Mateus-Lacerda/better-mem/internal/service"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	apiKeyContextKey = "api_key"
+	// Lets admin keys act on behalf of another tenant
+	tenantHeader = "X-Tenant-Id"
+)
+
+type AuthMiddleware struct {
+	apiKeyService *service.ApiKeyService
+	tenantService *service.TenantService
+}
+
+func NewAuthMiddleware(
+	apiKeyService *service.ApiKeyService,
+	tenantService *service.TenantService,
+) *AuthMiddleware {
+	return &AuthMiddleware{
+		apiKeyService: apiKeyService,
+		tenantService: tenantService,
+	}
+}
+
+// Scopes the rest of the request to the tenant
+func setTenant(context *gin.Context, tenantId string) {
+	context.Request = context.Request.WithContext(
+		core.WithTenant(context.Request.Context(), tenantId),
+	)
+}
+
+// Require only lets through requests carrying an api key
+// with the given scope, as "Authorization: Bearer <key>",
+// and scopes them to the tenant of the key
+func (a *AuthMiddleware) Require(scope core.ApiKeyScope) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		if !config.Auth.Enabled {
+			setTenant(context, core.DefaultTenantId)
+			context.Next()
+			return
+		}
+		key, found := strings.CutPrefix(context.GetHeader("Authorization"), "Bearer ")
+		if !found {
+			context.AbortWithStatusJSON(401, gin.H{"error": core.InvalidApiKey.Error()})
+			return
+		}
+		apiKey, err := a.apiKeyService.Authenticate(context, strings.TrimSpace(key))
+		if errors.Is(err, core.InvalidApiKey) {
+			context.AbortWithStatusJSON(401, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			slog.Error("error authenticating api key", "error", err)
+			context.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		if !apiKey.HasScope(scope) {
+			context.AbortWithStatusJSON(403, gin.H{"error": "Missing scope " + string(scope)})
+			return
+		}
+		tenantId := apiKey.TenantId
+		if header := context.GetHeader(tenantHeader); header != "" && apiKey.HasScope(core.AdminScope) {
+			if _, err := a.tenantService.Get(context, header); err != nil {
+				context.AbortWithStatusJSON(404, gin.H{"error": err.Error()})
+				return
+			}
+			tenantId = header
+		}
+		context.Set(apiKeyContextKey, apiKey)
+		setTenant(context, tenantId)
+		context.Next()
+	}
+}
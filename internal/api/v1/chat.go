@@ -3,28 +3,43 @@ package v1
 import (
 	"github.com/Mateus-Lacerda/better-mem/pkg/core"
 	"github.com/Mateus-Lacerda/better-mem/internal/service"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ChatHandler struct {
-	chatService *service.ChatService
+	chatService         *service.ChatService
+	chatPurgeService    *service.ChatPurgeService
+	chatTransferService *service.ChatTransferService
 }
 
-func NewChatHandler(chatService *service.ChatService) *ChatHandler {
-	return &ChatHandler{chatService: chatService}
+func NewChatHandler(
+	chatService *service.ChatService,
+	chatPurgeService *service.ChatPurgeService,
+	chatTransferService *service.ChatTransferService,
+) *ChatHandler {
+	return &ChatHandler{
+		chatService:         chatService,
+		chatPurgeService:    chatPurgeService,
+		chatTransferService: chatTransferService,
+	}
 }
 
 // @Summary Create a new chat
 // @Description Create a new chat
 // @Tags chat
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param chat body core.NewChat true "chat"
 // @Success 201 {object} core.Chat
 // @Failure 422 {object} string
+// @Failure 429 {object} string
 // @Failure 500 {object} string
 // @Router /chat [post]
 func (c *ChatHandler) CreateChat(context *gin.Context) {
@@ -41,6 +56,10 @@ func (c *ChatHandler) CreateChat(context *gin.Context) {
 			context.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
 			return
 		}
+		if errors.Is(err, core.TenantQuotaExceeded) {
+			context.AbortWithStatusJSON(429, gin.H{"error": err.Error()})
+			return
+		}
 		context.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
 		return
 	}
@@ -50,6 +69,7 @@ func (c *ChatHandler) CreateChat(context *gin.Context) {
 // @Summary Get all chats
 // @Description Get all chats
 // @Tags chat
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Success 200 {array} core.Chat
@@ -68,3 +88,103 @@ func (c *ChatHandler) GetChats(context *gin.Context) {
 	}
 	context.JSON(200, chats)
 }
+
+// @Summary Delete a chat
+// @Description Permanently deletes the chat with its memories, related context, messages, merges,
+// @Description events, webhooks, vectors and queued jobs. Unlike deactivating the memories, nothing can be recovered.
+// @Description The report counts what was deleted and what was found after the purge, verified is true when nothing was left
+// @Tags chat
+// @Security BearerAuth
+// @Produce json
+// @Param chat_id path string true "Chat ID"
+// @Success 200 {object} core.ChatDeletionReport
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /chat/{chat_id} [delete]
+func (c *ChatHandler) DeleteChat(context *gin.Context) {
+	report, err := c.chatPurgeService.Purge(context, context.Param("chat_id"))
+	if errors.Is(err, core.ChatNotFound) {
+		context.AbortWithStatusJSON(404, gin.H{"error": "Chat not found"})
+		return
+	}
+	if err != nil {
+		context.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(200, report)
+}
+
+// @Summary Export a chat
+// @Description Exports the chat with all its short and long term memories, active or not, with their counters
+// @Description and related context, as JSONL: a header line with the format version, a chat line and a line per memory.
+// @Description With embeddings=true each memory also carries its embedding
+// @Tags chat
+// @Security BearerAuth
+// @Produce application/x-ndjson
+// @Param chat_id path string true "Chat ID"
+// @Param embeddings query bool false "Include the embeddings"
+// @Success 200 {object} core.ChatExportLine
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /chat/{chat_id}/export [get]
+func (c *ChatHandler) ExportChat(context *gin.Context) {
+	withEmbeddings, err := strconv.ParseBool(context.DefaultQuery("embeddings", "false"))
+	if err != nil {
+		context.AbortWithStatusJSON(400, gin.H{"error": "Invalid embeddings"})
+		return
+	}
+	chatId := context.Param("chat_id")
+	lines, err := c.chatTransferService.Export(context, chatId, withEmbeddings)
+	if errors.Is(err, core.ChatNotFound) {
+		context.AbortWithStatusJSON(404, gin.H{"error": "Chat not found"})
+		return
+	}
+	if err != nil {
+		context.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	context.Header("Content-Type", "application/x-ndjson")
+	context.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", chatId+".jsonl"))
+	context.Status(200)
+	encoder := json.NewEncoder(context.Writer)
+	for _, line := range lines {
+		if err := encoder.Encode(line); err != nil {
+			slog.Error("error writing chat export", "chat_id", chatId, "error", err)
+			return
+		}
+	}
+}
+
+// @Summary Import a chat
+// @Description Creates a chat with its memories from an export. The chat keeps its exported external id unless
+// @Description external_id is given. Memories without an embedding, or with one of another size than the vector
+// @Description store's, are embedded again. Nothing is kept when the import fails
+// @Tags chat
+// @Security BearerAuth
+// @Accept application/x-ndjson
+// @Produce json
+// @Param external_id query string false "External id of the imported chat"
+// @Param export body string true "Chat export"
+// @Success 201 {object} core.ChatImportReport
+// @Failure 400 {object} string
+// @Failure 429 {object} string
+// @Failure 500 {object} string
+// @Router /chat/import [post]
+func (c *ChatHandler) ImportChat(context *gin.Context) {
+	report, err := c.chatTransferService.Import(context, context.Query("external_id"), context.Request.Body)
+	if errors.Is(err, core.InvalidChatExport) ||
+		errors.Is(err, core.ChatExternalIdAlreadyExists) {
+		context.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, core.TenantQuotaExceeded) {
+		context.AbortWithStatusJSON(429, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(201, report)
+}
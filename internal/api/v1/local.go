@@ -3,10 +3,13 @@
 package v1
 
 import (
+	"github.com/Mateus-Lacerda/better-mem/internal/database/sqlite"
 	"github.com/Mateus-Lacerda/better-mem/internal/database/sqlite/repository"
 	vectorRepo "github.com/Mateus-Lacerda/better-mem/internal/database/sqlite/repository/vector"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/sqlite/uow"
 	contracts "github.com/Mateus-Lacerda/better-mem/internal/repository"
 	vectorContracts "github.com/Mateus-Lacerda/better-mem/internal/repository/vector"
+	uowContracts "github.com/Mateus-Lacerda/better-mem/internal/uow"
 )
 
 func getRepositories() (
@@ -14,10 +17,34 @@ func getRepositories() (
 	contracts.LongTermMemoryRepository,
 	contracts.ShortTermMemoryRepository,
 	vectorContracts.MemoryVectorRepository,
+	contracts.ApiKeyRepository,
+	contracts.TenantRepository,
+	contracts.MessageRepository,
+	contracts.MemoryMergeRepository,
+	contracts.MemoryKeywordRepository,
+	contracts.WebhookRepository,
+	contracts.WebhookDeliveryRepository,
+	contracts.MemoryEventRepository,
+	contracts.ChatPurgeRepository,
+	contracts.MemoryRetentionRepository,
+	contracts.RetentionRunRepository,
+	uowContracts.UnitOfWork[int, any],
 ) {
 	chatRepository := repository.NewChatRepository()
 	longTermMemoryRepository := repository.NewLongTermMemoryRepository()
 	shortTermMemoryRepository := repository.NewShortTermMemoryRepository()
 	memoryVectorRepository := vectorRepo.NewMemoryRepository()
-	return chatRepository, longTermMemoryRepository, shortTermMemoryRepository, memoryVectorRepository
+	apiKeyRepository := repository.NewApiKeyRepository()
+	tenantRepository := repository.NewTenantRepository()
+	messageRepository := repository.NewMessageRepository()
+	memoryMergeRepository := repository.NewMemoryMergeRepository()
+	memoryKeywordRepository := repository.NewMemoryKeywordRepository()
+	webhookRepository := repository.NewWebhookRepository()
+	webhookDeliveryRepository := repository.NewWebhookDeliveryRepository()
+	memoryEventRepository := repository.NewMemoryEventRepository()
+	chatPurgeRepository := repository.NewChatPurgeRepository()
+	memoryRetentionRepository := repository.NewMemoryRetentionRepository()
+	retentionRunRepository := repository.NewRetentionRunRepository()
+	sqliteIntUow := uow.NewUnitOfWork[int, any](sqlite.GetDb())
+	return chatRepository, longTermMemoryRepository, shortTermMemoryRepository, memoryVectorRepository, apiKeyRepository, tenantRepository, messageRepository, memoryMergeRepository, memoryKeywordRepository, webhookRepository, webhookDeliveryRepository, memoryEventRepository, chatPurgeRepository, memoryRetentionRepository, retentionRunRepository, sqliteIntUow
 }
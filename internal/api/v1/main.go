@@ -1,7 +1,11 @@
 package v1
 
 import (
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
+	"github.com/Mateus-Lacerda/better-mem/internal/llm"
+	"github.com/Mateus-Lacerda/better-mem/internal/llm/ollama"
 	"github.com/Mateus-Lacerda/better-mem/internal/service"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,44 +22,138 @@ func HealthCheck(c *gin.Context) {
 }
 
 func Register(router *gin.Engine) {
+	// Handlers pass the gin context down to the repositories,
+	// which read the tenant from the request context
+	router.ContextWithFallback = true
 	v1Router := router.Group("/api/v1")
 	{
+		// Providers
+		var llmProvider llm.LLMProvider
+		if provider := ollama.NewLLMProvider(config.Llm.BaseUrl, config.Llm.Model); provider != nil {
+			llmProvider = provider
+		}
+
 		chatRepository,
 			longTermMemoryRepository,
 			shortTermMemoryRepository,
-			memoryVectorRepository := getRepositories()
+			memoryVectorRepository,
+			apiKeyRepository,
+			tenantRepository,
+			messageRepository,
+			memoryMergeRepository,
+			memoryKeywordRepository,
+			webhookRepository,
+			webhookDeliveryRepository,
+			memoryEventRepository,
+			chatPurgeRepository,
+			memoryRetentionRepository,
+			retentionRunRepository,
+			uow := getRepositories()
 
-		chatService := service.NewChatService(chatRepository)
+		tenantService := service.NewTenantService(
+			tenantRepository,
+			chatRepository,
+			shortTermMemoryRepository,
+			longTermMemoryRepository,
+		)
+		chatService := service.NewChatService(chatRepository, tenantService)
+		memoryEventService := service.NewMemoryEventService(memoryEventRepository)
 		longTermMemoryService := service.NewLongTermMemoryService(longTermMemoryRepository, chatRepository)
-		shortTermMemoryService := service.NewShortTermMemoryService(shortTermMemoryRepository, chatRepository)
+		shortTermMemoryService := service.NewShortTermMemoryService(shortTermMemoryRepository, chatRepository, uow)
 		memoryService := service.NewMemoryService(
 			shortTermMemoryRepository,
 			longTermMemoryRepository,
 			memoryVectorRepository,
+			memoryMergeRepository,
+			memoryKeywordRepository,
+			tenantService,
+			service.NewQueryRewriteService(llmProvider),
+			service.NewMemoryEnhancementService(llmProvider),
+			memoryEventService,
+			uow,
 		)
+		messageService := service.NewMessageService(messageRepository, chatRepository)
 		memoryHandler := NewMemoryHandler(
 			shortTermMemoryService,
 			longTermMemoryService,
 			chatService,
 			memoryService,
+			messageService,
+			memoryEventService,
+		)
+		chatPurgeService := service.NewChatPurgeService(chatPurgeRepository, chatRepository, memoryVectorRepository)
+		chatTransferService := service.NewChatTransferService(
+			shortTermMemoryRepository,
+			longTermMemoryRepository,
+			memoryVectorRepository,
+			chatService,
+			tenantService,
+			chatPurgeService,
 		)
-		chatHandler := NewChatHandler(chatService)
-		messageHandler := NewMessageHandler(chatService)
+		chatHandler := NewChatHandler(chatService, chatPurgeService, chatTransferService)
+		webhookService := service.NewWebhookService(webhookRepository, webhookDeliveryRepository, chatRepository)
+		webhookHandler := NewWebhookHandler(webhookService)
+		messageHandler := NewMessageHandler(chatService, messageService)
+		apiKeyService := service.NewApiKeyService(apiKeyRepository, tenantRepository)
+		retentionService := service.NewRetentionService(memoryRetentionRepository, retentionRunRepository, memoryVectorRepository)
+		adminHandler := NewAdminHandler(apiKeyService, tenantService, retentionService)
+		auth := NewAuthMiddleware(apiKeyService, tenantService)
+		memoryRead := auth.Require(core.MemoryReadScope)
+		memoryWrite := auth.Require(core.MemoryWriteScope)
+		chatAdmin := auth.Require(core.ChatAdminScope)
+		admin := auth.Require(core.AdminScope)
 
 		// Health check
 		v1Router.GET("/health", HealthCheck)
 
 		// Memory
-		v1Router.GET("/memory/short-term/chat/:chat_id", memoryHandler.GetShortTermMemories)
-		v1Router.GET("/memory/long-term/chat/:chat_id", memoryHandler.GetLongTermMemories)
-		v1Router.POST("/memory/chat/:chat_id/fetch", memoryHandler.FetchMemories)
-		v1Router.PUT("/memory/chat/:chat_id/deactivate", memoryHandler.DeactivateAllMemories)
+		v1Router.GET("/memory/short-term/chat/:chat_id", memoryRead, memoryHandler.GetShortTermMemories)
+		v1Router.GET("/memory/long-term/chat/:chat_id", memoryRead, memoryHandler.GetLongTermMemories)
+		v1Router.GET("/memory/short-term/chat/:chat_id/:memory_id", memoryRead, memoryHandler.GetShortTermMemory)
+		v1Router.PATCH("/memory/short-term/chat/:chat_id/:memory_id", memoryWrite, memoryHandler.UpdateShortTermMemory)
+		v1Router.DELETE("/memory/short-term/chat/:chat_id/:memory_id", memoryWrite, memoryHandler.DeleteShortTermMemory)
+		v1Router.GET("/memory/long-term/chat/:chat_id/:memory_id", memoryRead, memoryHandler.GetLongTermMemory)
+		v1Router.GET("/memory/short-term/chat/:chat_id/:memory_id/provenance", memoryRead, memoryHandler.GetShortTermMemoryProvenance)
+		v1Router.GET("/memory/long-term/chat/:chat_id/:memory_id/provenance", memoryRead, memoryHandler.GetLongTermMemoryProvenance)
+		v1Router.GET("/memory/short-term/chat/:chat_id/:memory_id/merges", memoryRead, memoryHandler.GetShortTermMemoryMerges)
+		v1Router.POST("/memory/short-term/chat/:chat_id/:memory_id/merges/:merge_id/revert", memoryWrite, memoryHandler.RevertShortTermMemoryMerge)
+		v1Router.PATCH("/memory/long-term/chat/:chat_id/:memory_id", memoryWrite, memoryHandler.UpdateLongTermMemory)
+		v1Router.DELETE("/memory/long-term/chat/:chat_id/:memory_id", memoryWrite, memoryHandler.DeleteLongTermMemory)
+		v1Router.POST("/memory/chat/:chat_id", memoryWrite, memoryHandler.CreateMemory)
+		v1Router.POST("/memory/chat/:chat_id/fetch", memoryRead, memoryHandler.FetchMemories)
+		v1Router.POST("/memory/chat/:chat_id/fetch/detailed", memoryRead, memoryHandler.FetchMemoriesDetailed)
+		v1Router.POST("/memory/chat/:chat_id/context", memoryRead, memoryHandler.RenderContext)
+		v1Router.POST("/memory/fetch/batch", memoryRead, memoryHandler.FetchMemoriesBatch)
+		v1Router.GET("/memory/chat/:chat_id/events", memoryRead, memoryHandler.StreamEvents)
+		v1Router.PUT("/memory/chat/:chat_id/deactivate", memoryWrite, memoryHandler.DeactivateAllMemories)
 
 		// Chat
-		v1Router.GET("/chat", chatHandler.GetChats)
-		v1Router.POST("/chat", chatHandler.CreateChat)
+		v1Router.GET("/chat", chatAdmin, chatHandler.GetChats)
+		v1Router.POST("/chat", chatAdmin, chatHandler.CreateChat)
+		v1Router.DELETE("/chat/:chat_id", chatAdmin, chatHandler.DeleteChat)
+		v1Router.GET("/chat/:chat_id/export", chatAdmin, chatHandler.ExportChat)
+		v1Router.POST("/chat/import", chatAdmin, chatHandler.ImportChat)
+		v1Router.GET("/chat/:chat_id/messages", memoryRead, messageHandler.GetChatMessages)
 
 		// Message
-		v1Router.POST("/message", messageHandler.AddMessage)
+		v1Router.POST("/message", memoryWrite, messageHandler.AddMessage)
+		v1Router.POST("/message/batch", memoryWrite, messageHandler.AddMessages)
+		v1Router.GET("/message/task/:task_id", memoryRead, messageHandler.GetTask)
+
+		// Webhook
+		v1Router.POST("/webhook", chatAdmin, webhookHandler.RegisterWebhook)
+		v1Router.GET("/webhook", chatAdmin, webhookHandler.GetWebhooks)
+		v1Router.DELETE("/webhook/:webhook_id", chatAdmin, webhookHandler.DeleteWebhook)
+		v1Router.GET("/webhook/:webhook_id/deliveries", chatAdmin, webhookHandler.GetWebhookDeliveries)
+
+		// Admin
+		v1Router.POST("/admin/api-key", admin, adminHandler.IssueApiKey)
+		v1Router.GET("/admin/api-key", admin, adminHandler.GetApiKeys)
+		v1Router.DELETE("/admin/api-key/:api_key_id", admin, adminHandler.RevokeApiKey)
+		v1Router.POST("/admin/tenant", admin, adminHandler.CreateTenant)
+		v1Router.GET("/admin/tenant", admin, adminHandler.GetTenants)
+		v1Router.PUT("/admin/tenant/:tenant_id/quota", admin, adminHandler.UpdateTenantQuota)
+		v1Router.GET("/admin/tenant/:tenant_id/usage", admin, adminHandler.GetTenantUsage)
+		v1Router.GET("/admin/retention/runs", admin, adminHandler.GetRetentionRuns)
 	}
 }
@@ -1,11 +1,17 @@
 package v1
 
 import (
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
 	"github.com/Mateus-Lacerda/better-mem/pkg/core"
 	"github.com/Mateus-Lacerda/better-mem/internal/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,6 +21,8 @@ type MemoryHandler struct {
 	longTermMemoryService  *service.LongTermMemoryService
 	chatService *service.ChatService
 	memoryService          *service.MemoryService
+	messageService         *service.MessageService
+	memoryEventService     *service.MemoryEventService
 }
 
 func NewMemoryHandler(
@@ -22,18 +30,23 @@ func NewMemoryHandler(
 	longTermMemoryService *service.LongTermMemoryService,
 	chatService *service.ChatService,
 	memoryService *service.MemoryService,
+	messageService *service.MessageService,
+	memoryEventService *service.MemoryEventService,
 ) *MemoryHandler {
 	return &MemoryHandler{
 		shortTermMemoryService: shortTermMemoryService,
 		longTermMemoryService:  longTermMemoryService,
 		chatService: chatService,
 		memoryService:          memoryService,
+		messageService:         messageService,
+		memoryEventService:     memoryEventService,
 	}
 }
 
 // @Summary Fetch Memories
 // @Description Fetch memories for a given chat.
 // @Tags memories
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param chat_id path string true "Chat ID"
@@ -41,46 +54,230 @@ func NewMemoryHandler(
 // @Success 200 {object} []core.ScoredMemory
 // @Router /memory/chat/{chat_id}/fetch [post]
 func (h *MemoryHandler) FetchMemories(context *gin.Context) {
+	result, ok := h.fetch(context)
+	if !ok {
+		return
+	}
+	context.JSON(200, result.Memories)
+}
+
+// @Summary Fetch Memories Detailed
+// @Description Same fetch as /fetch, returning the memories along with the rejected candidates (with explain),
+// @Description the tokens used (with max_tokens) and the query and hypothetical memory the search ran with.
+// @Tags memories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param chat_id path string true "Chat ID"
+// @Param request body core.MemoryFetchRequest true "Fetch Memories Request"
+// @Success 200 {object} core.MemoryFetchResult
+// @Router /memory/chat/{chat_id}/fetch/detailed [post]
+func (h *MemoryHandler) FetchMemoriesDetailed(context *gin.Context) {
+	result, ok := h.fetch(context)
+	if !ok {
+		return
+	}
+	context.JSON(200, result)
+}
+
+// Runs the fetch of the request body, aborting the request when it fails
+func (h *MemoryHandler) fetch(context *gin.Context) (*core.MemoryFetchResult, bool) {
 	var request core.MemoryFetchRequest
 	if err := context.BindJSON(&request); err != nil {
 		context.JSON(400, gin.H{"error": err.Error()})
-		return
+		return nil, false
 	}
 	externalId := context.Param("chat_id")
 	if externalId == "" {
 		context.JSON(400, gin.H{"error": "Invalid chat id"})
-		return
+		return nil, false
 	}
 	chatId, err := h.chatService.GetByExternalId(context, externalId)
 	if err == core.ChatNotFound {
 		slog.Info("Chat not found", "external_id", externalId)
 		context.JSON(400, gin.H{"error": "Chat not found"})
-		return
+		return nil, false
 	}
 	if chatId == nil || err != nil {
 		slog.Error("Error getting chat", "error", err)
 		context.JSON(500, gin.H{"error": "Error getting chat"})
+		return nil, false
+	}
+	result, err := h.memoryService.Fetch(context, *chatId, &request)
+	switch {
+	case isInvalidFetch(err):
+		context.JSON(400, gin.H{"error": err.Error()})
+		return nil, false
+	case err != nil:
+		context.JSON(500, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return result, true
+}
+
+type MemoryFetchBatchResponse struct {
+	Items []core.MemoryFetchBatchItem `json:"items"`
+}
+
+// @Summary Fetch Memories Batch
+// @Description Runs several fetches, possibly across chats, in one call. The texts are embedded at once and the
+// @Description fetches run concurrently, each item carries the result of its fetch as /fetch/detailed returns it,
+// @Description or its error. Every chat is validated before anything is fetched
+// @Tags memories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body core.MemoryFetchBatch true "Fetches"
+// @Success 200 {object} MemoryFetchBatchResponse
+// @Failure 400 {object} any
+// @Failure 500 {object} any
+// @Router /memory/fetch/batch [post]
+func (h *MemoryHandler) FetchMemoriesBatch(context *gin.Context) {
+	var batch core.MemoryFetchBatch
+	if err := context.BindJSON(&batch); err != nil {
+		context.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
-	memories, err := h.memoryService.Fetch(
-		context,
-		*chatId,
-		request.Text,
-		request.Limit,
-		request.VectorSearchLimit,
-		request.VectorSearchThreshold,
-		request.LongTermThreshold,
-	)
+	if err := h.memoryService.CheckBatchSize(len(batch.Fetches)); err != nil {
+		context.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	chatIds := map[string]string{}
+	var missingChats []string
+	for _, f := range batch.Fetches {
+		if _, ok := chatIds[f.ChatId]; ok {
+			continue
+		}
+		chatId, err := h.chatService.GetByExternalId(context, f.ChatId)
+		if err == core.ChatNotFound {
+			chatIds[f.ChatId] = ""
+			missingChats = append(missingChats, f.ChatId)
+			continue
+		}
+		if chatId == nil || err != nil {
+			slog.Error("Error getting chat", "error", err)
+			context.JSON(500, gin.H{"error": "Error getting chat"})
+			return
+		}
+		chatIds[f.ChatId] = *chatId
+	}
+	if len(missingChats) > 0 {
+		context.JSON(400, gin.H{"error": "Chat not found", "chat_ids": missingChats})
+		return
+	}
+	fetches := make([]core.MemoryFetchBatchEntry, len(batch.Fetches))
+	for i, f := range batch.Fetches {
+		fetches[i] = f
+		fetches[i].ChatId = chatIds[f.ChatId]
+	}
+	items, err := h.memoryService.FetchBatch(context, fetches)
 	if err != nil {
+		slog.Error("Error fetching memories", "error", err)
 		context.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
-	context.JSON(200, memories)
+	// Report the chats the way the caller knows them
+	for i := range items {
+		items[i].ChatId = batch.Fetches[i].ChatId
+	}
+	context.JSON(200, MemoryFetchBatchResponse{Items: items})
+}
+
+// Whether a fetch failed because of its request
+func isInvalidFetch(err error) bool {
+	return errors.Is(err, core.InvalidFetchMode) ||
+		errors.Is(err, core.InvalidDiversity) ||
+		errors.Is(err, core.InvalidScorer) ||
+		errors.Is(err, core.InvalidScoringWeights) ||
+		errors.Is(err, core.InvalidTokenizer) ||
+		errors.Is(err, core.InvalidMaxTokens) ||
+		errors.Is(err, core.InvalidQueryStrategy)
+}
+
+// @Summary Fetch Memory Context
+// @Description Fetch memories for a given chat and render them into a block of text ready to go in a prompt.
+// @Description The template is bullet, xml, json or one registered from CONTEXT_TEMPLATES_DIR.
+// @Tags memories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param chat_id path string true "Chat ID"
+// @Param request body core.MemoryContextRequest true "Memory Context Request"
+// @Success 200 {object} core.MemoryContext
+// @Failure 400 {object} any
+// @Failure 404 {object} any
+// @Router /memory/chat/{chat_id}/context [post]
+func (h *MemoryHandler) RenderContext(context *gin.Context) {
+	var request core.MemoryContextRequest
+	if err := context.BindJSON(&request); err != nil {
+		context.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	chatId, ok := h.resolveChatId(context)
+	if !ok {
+		return
+	}
+	result, err := h.memoryService.RenderContext(context, chatId, &request)
+	switch {
+	case isInvalidFetch(err), errors.Is(err, core.TemplateNotFound):
+		context.JSON(400, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(200, result)
+}
+
+// @Summary Create Memory
+// @Description Stores a memory directly as short term (1) or long term (2), skipping the classifier.
+// @Description A memory similar to an existing one is merged into it (short term) or not duplicated.
+// @Tags memories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param chat_id path string true "Chat ID"
+// @Param request body core.NewMemory true "New Memory"
+// @Success 201 {object} core.MemoryStoreResult
+// @Success 200 {object} core.MemoryStoreResult
+// @Failure 400 {object} any
+// @Failure 404 {object} any
+// @Failure 429 {object} any
+// @Router /memory/chat/{chat_id} [post]
+func (h *MemoryHandler) CreateMemory(context *gin.Context) {
+	var request core.NewMemory
+	if err := context.BindJSON(&request); err != nil {
+		context.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	chatId, ok := h.resolveChatId(context)
+	if !ok {
+		return
+	}
+	result, err := h.memoryService.Create(context, chatId, &request)
+	if errors.Is(err, core.EmptyMemory) || errors.Is(err, core.InvalidMemoryType) {
+		context.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, core.TenantQuotaExceeded) {
+		context.JSON(429, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if result.Created {
+		context.JSON(201, result)
+		return
+	}
+	context.JSON(200, result)
 }
 
 // @Summary Get Long Term Memories
 // @Description Get long term memories for a given chat.
 // @Tags memories
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param chat_id path string true "Chat ID"
@@ -119,6 +316,7 @@ func (h *MemoryHandler) GetLongTermMemories(context *gin.Context) {
 // @Summary Get Short Term Memories
 // @Description Get short term memories for a given chat.
 // @Tags memories
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param chat_id path string true "Chat ID"
@@ -157,6 +355,7 @@ func (h *MemoryHandler) GetShortTermMemories(context *gin.Context) {
 // @Summary Deactivate All Memories
 // @Description Deactivates all the memories, should be retried on fail since it may leave unwanted data.
 // @Tags memories
+// @Security BearerAuth
 // @Param chat_id path string true "Chat ID"
 // @Success 200
 // @Router /memory/chat/{chat_id}/deactivate [put]
@@ -174,3 +373,379 @@ func (h *MemoryHandler) DeactivateAllMemories(context *gin.Context) {
 		context.AbortWithError(http.StatusInternalServerError, err)
 	}
 }
+
+// @Summary Stream Memory Events
+// @Description Server-sent events stream of the memory changes of a chat: memory.created, memory.merged,
+// @Description memory.promoted, memory.deactivated and memory.discarded, with the event as JSON data.
+// @Description Each event id is its cursor, reconnecting with it as since (or Last-Event-ID) replays what was missed.
+// @Description Without a cursor only the events from now on are sent.
+// @Tags memories
+// @Security BearerAuth
+// @Produce text/event-stream
+// @Param chat_id path string true "Chat ID"
+// @Param since query int false "Cursor of the last event received, 0 replays every event"
+// @Success 200 {object} core.MemoryEvent
+// @Failure 400 {object} any
+// @Failure 404 {object} any
+// @Router /memory/chat/{chat_id}/events [get]
+func (h *MemoryHandler) StreamEvents(context *gin.Context) {
+	since := context.Query("since")
+	if since == "" {
+		since = context.GetHeader("Last-Event-ID")
+	}
+	var cursor int64
+	if since != "" {
+		parsed, err := strconv.ParseInt(since, 10, 64)
+		if err != nil || parsed < 0 {
+			context.JSON(400, gin.H{"error": "Invalid since cursor"})
+			return
+		}
+		cursor = parsed
+	}
+	chatId, ok := h.resolveChatId(context)
+	if !ok {
+		return
+	}
+	if since == "" {
+		last, err := h.memoryEventService.LastCursor(context, chatId)
+		if err != nil {
+			context.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		cursor = last
+	}
+	// The stream outlives the handler's gin context, which is pooled,
+	// and stops with the request when the client disconnects
+	events, err := h.memoryEventService.Stream(context.Request.Context(), chatId, cursor)
+	if err != nil {
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	keepAlive := time.NewTicker(time.Duration(config.Events.KeepAlive) * time.Second)
+	defer keepAlive.Stop()
+	context.Header("Content-Type", "text/event-stream")
+	context.Header("Cache-Control", "no-cache")
+	context.Header("Connection", "keep-alive")
+	context.Header("X-Accel-Buffering", "no")
+	context.Status(200)
+	context.Writer.Flush()
+	externalId := context.Param("chat_id")
+	context.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			event.ChatId = externalId
+			data, err := json.Marshal(event)
+			if err != nil {
+				return false
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Cursor, event.Type, data)
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		return true
+	})
+}
+
+// Resolves the chat_id path param into the internal chat id,
+// aborting the request when it can not be resolved
+func (h *MemoryHandler) resolveChatId(context *gin.Context) (string, bool) {
+	externalId := context.Param("chat_id")
+	if externalId == "" {
+		context.JSON(400, gin.H{"error": "Invalid chat id"})
+		return "", false
+	}
+	chatId, err := h.chatService.GetByExternalId(context, externalId)
+	if errors.Is(err, core.ChatNotFound) {
+		context.JSON(404, gin.H{"error": "Chat not found"})
+		return "", false
+	}
+	if chatId == nil || err != nil {
+		slog.Error("Error getting chat", "error", err)
+		context.JSON(500, gin.H{"error": "Error getting chat"})
+		return "", false
+	}
+	return *chatId, true
+}
+
+// @Summary Get Short Term Memory Provenance
+// @Description Get the messages that created a short term memory or were merged into it, with their original text.
+// @Tags memories
+// @Security BearerAuth
+// @Produce json
+// @Param chat_id path string true "Chat ID"
+// @Param memory_id path string true "Memory ID"
+// @Success 200 {object} core.MemoryProvenance
+// @Failure 404 {object} any
+// @Router /memory/short-term/chat/{chat_id}/{memory_id}/provenance [get]
+func (h *MemoryHandler) GetShortTermMemoryProvenance(context *gin.Context) {
+	chatId, ok := h.resolveChatId(context)
+	if !ok {
+		return
+	}
+	memory, err := h.shortTermMemoryService.GetById(
+		context, chatId, context.Param("memory_id"),
+	)
+	if err != nil {
+		memoryErrorResponse(context, err)
+		return
+	}
+	h.writeProvenance(context, memory.Id)
+}
+
+// @Summary Get Long Term Memory Provenance
+// @Description Get the messages that created a long term memory or were merged into it, with their original text.
+// @Tags memories
+// @Security BearerAuth
+// @Produce json
+// @Param chat_id path string true "Chat ID"
+// @Param memory_id path string true "Memory ID"
+// @Success 200 {object} core.MemoryProvenance
+// @Failure 404 {object} any
+// @Router /memory/long-term/chat/{chat_id}/{memory_id}/provenance [get]
+func (h *MemoryHandler) GetLongTermMemoryProvenance(context *gin.Context) {
+	chatId, ok := h.resolveChatId(context)
+	if !ok {
+		return
+	}
+	memory, err := h.longTermMemoryService.GetById(
+		context, chatId, context.Param("memory_id"),
+	)
+	if err != nil {
+		memoryErrorResponse(context, err)
+		return
+	}
+	h.writeProvenance(context, memory.Id)
+}
+
+func (h *MemoryHandler) writeProvenance(context *gin.Context, memoryId string) {
+	provenance, err := h.messageService.GetProvenance(context, memoryId)
+	if err != nil {
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(200, provenance)
+}
+
+// @Summary Get Short Term Memory Merges
+// @Description Get every merge of a short term memory with the text it replaced, oldest first.
+// @Tags memories
+// @Security BearerAuth
+// @Produce json
+// @Param chat_id path string true "Chat ID"
+// @Param memory_id path string true "Memory ID"
+// @Success 200 {object} core.MemoryMergeHistory
+// @Failure 404 {object} any
+// @Router /memory/short-term/chat/{chat_id}/{memory_id}/merges [get]
+func (h *MemoryHandler) GetShortTermMemoryMerges(context *gin.Context) {
+	chatId, ok := h.resolveChatId(context)
+	if !ok {
+		return
+	}
+	history, err := h.memoryService.GetMergeHistory(
+		context, chatId, context.Param("memory_id"),
+	)
+	if err != nil {
+		memoryErrorResponse(context, err)
+		return
+	}
+	context.JSON(200, history)
+}
+
+// @Summary Revert Short Term Memory Merge
+// @Description Restore a short term memory to the text and related context it had before the given merge.
+// @Tags memories
+// @Security BearerAuth
+// @Produce json
+// @Param chat_id path string true "Chat ID"
+// @Param memory_id path string true "Memory ID"
+// @Param merge_id path string true "Merge ID"
+// @Success 200 {object} core.ShortTermMemory
+// @Failure 404 {object} any
+// @Router /memory/short-term/chat/{chat_id}/{memory_id}/merges/{merge_id}/revert [post]
+func (h *MemoryHandler) RevertShortTermMemoryMerge(context *gin.Context) {
+	chatId, ok := h.resolveChatId(context)
+	if !ok {
+		return
+	}
+	memory, err := h.memoryService.RevertMerge(
+		context, chatId, context.Param("memory_id"), context.Param("merge_id"),
+	)
+	if err != nil {
+		memoryErrorResponse(context, err)
+		return
+	}
+	context.JSON(200, memory)
+}
+
+// Writes the response for errors returned by single memory operations
+func memoryErrorResponse(context *gin.Context, err error) {
+	switch {
+	case errors.Is(err, core.MemoryNotFound), errors.Is(err, core.MemoryMergeNotFound):
+		context.JSON(404, gin.H{"error": err.Error()})
+	case errors.Is(err, core.EmptyMemoryUpdate):
+		context.JSON(400, gin.H{"error": err.Error()})
+	default:
+		context.JSON(500, gin.H{"error": err.Error()})
+	}
+}
+
+// @Summary Get Short Term Memory
+// @Description Get a single short term memory.
+// @Tags memories
+// @Security BearerAuth
+// @Produce json
+// @Param chat_id path string true "Chat ID"
+// @Param memory_id path string true "Memory ID"
+// @Success 200 {object} core.ShortTermMemory
+// @Failure 404 {object} any
+// @Router /memory/short-term/chat/{chat_id}/{memory_id} [get]
+func (h *MemoryHandler) GetShortTermMemory(context *gin.Context) {
+	chatId, ok := h.resolveChatId(context)
+	if !ok {
+		return
+	}
+	memory, err := h.shortTermMemoryService.GetById(
+		context, chatId, context.Param("memory_id"),
+	)
+	if err != nil {
+		memoryErrorResponse(context, err)
+		return
+	}
+	context.JSON(200, memory)
+}
+
+// @Summary Get Long Term Memory
+// @Description Get a single long term memory.
+// @Tags memories
+// @Security BearerAuth
+// @Produce json
+// @Param chat_id path string true "Chat ID"
+// @Param memory_id path string true "Memory ID"
+// @Success 200 {object} core.LongTermMemory
+// @Failure 404 {object} any
+// @Router /memory/long-term/chat/{chat_id}/{memory_id} [get]
+func (h *MemoryHandler) GetLongTermMemory(context *gin.Context) {
+	chatId, ok := h.resolveChatId(context)
+	if !ok {
+		return
+	}
+	memory, err := h.longTermMemoryService.GetById(
+		context, chatId, context.Param("memory_id"),
+	)
+	if err != nil {
+		memoryErrorResponse(context, err)
+		return
+	}
+	context.JSON(200, memory)
+}
+
+// @Summary Update Short Term Memory
+// @Description Updates a single short term memory, the memory is re-embedded when its text changes.
+// @Tags memories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param chat_id path string true "Chat ID"
+// @Param memory_id path string true "Memory ID"
+// @Param request body core.MemoryUpdateRequest true "Memory Update Request"
+// @Success 200 {object} core.ShortTermMemory
+// @Failure 400 {object} any
+// @Failure 404 {object} any
+// @Router /memory/short-term/chat/{chat_id}/{memory_id} [patch]
+func (h *MemoryHandler) UpdateShortTermMemory(context *gin.Context) {
+	var request core.MemoryUpdateRequest
+	if err := context.BindJSON(&request); err != nil {
+		context.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	chatId, ok := h.resolveChatId(context)
+	if !ok {
+		return
+	}
+	memory, err := h.memoryService.UpdateShortTerm(
+		context, chatId, context.Param("memory_id"), &request,
+	)
+	if err != nil {
+		memoryErrorResponse(context, err)
+		return
+	}
+	context.JSON(200, memory)
+}
+
+// @Summary Update Long Term Memory
+// @Description Updates a single long term memory, the memory is re-embedded when its text changes.
+// @Tags memories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param chat_id path string true "Chat ID"
+// @Param memory_id path string true "Memory ID"
+// @Param request body core.MemoryUpdateRequest true "Memory Update Request"
+// @Success 200 {object} core.LongTermMemory
+// @Failure 400 {object} any
+// @Failure 404 {object} any
+// @Router /memory/long-term/chat/{chat_id}/{memory_id} [patch]
+func (h *MemoryHandler) UpdateLongTermMemory(context *gin.Context) {
+	var request core.MemoryUpdateRequest
+	if err := context.BindJSON(&request); err != nil {
+		context.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	chatId, ok := h.resolveChatId(context)
+	if !ok {
+		return
+	}
+	memory, err := h.memoryService.UpdateLongTerm(
+		context, chatId, context.Param("memory_id"), &request,
+	)
+	if err != nil {
+		memoryErrorResponse(context, err)
+		return
+	}
+	context.JSON(200, memory)
+}
+
+// @Summary Delete Short Term Memory
+// @Description Deactivates a single short term memory and its vector.
+// @Tags memories
+// @Security BearerAuth
+// @Param chat_id path string true "Chat ID"
+// @Param memory_id path string true "Memory ID"
+// @Success 204
+// @Failure 404 {object} any
+// @Router /memory/short-term/chat/{chat_id}/{memory_id} [delete]
+func (h *MemoryHandler) DeleteShortTermMemory(context *gin.Context) {
+	h.deleteMemory(context, core.ShortTerm)
+}
+
+// @Summary Delete Long Term Memory
+// @Description Deactivates a single long term memory and its vector.
+// @Tags memories
+// @Security BearerAuth
+// @Param chat_id path string true "Chat ID"
+// @Param memory_id path string true "Memory ID"
+// @Success 204
+// @Failure 404 {object} any
+// @Router /memory/long-term/chat/{chat_id}/{memory_id} [delete]
+func (h *MemoryHandler) DeleteLongTermMemory(context *gin.Context) {
+	h.deleteMemory(context, core.LongTerm)
+}
+
+func (h *MemoryHandler) deleteMemory(
+	context *gin.Context, memoryType core.MemoryTypeEnum,
+) {
+	chatId, ok := h.resolveChatId(context)
+	if !ok {
+		return
+	}
+	if err := h.memoryService.Deactivate(
+		context, chatId, memoryType, context.Param("memory_id"),
+	); err != nil {
+		memoryErrorResponse(context, err)
+		return
+	}
+	context.Status(204)
+}
@@ -2,33 +2,50 @@ package v1
 
 import (
 	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
 	"github.com/Mateus-Lacerda/better-mem/internal/service"
 	"log/slog"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type MessageHandler struct{
-	chatService *service.ChatService
+	chatService    *service.ChatService
+	messageService *service.MessageService
 }
 
-func NewMessageHandler(chatService *service.ChatService) *MessageHandler {
-	return &MessageHandler{chatService: chatService}
+func NewMessageHandler(
+	chatService *service.ChatService,
+	messageService *service.MessageService,
+) *MessageHandler {
+	return &MessageHandler{
+		chatService:    chatService,
+		messageService: messageService,
+	}
 }
 
 type MessageResponse struct {
 	Message string `json:"message"`
+	// Use it to follow the message at /message/task/{task_id}
+	TaskId string `json:"task_id"`
 }
 
 // @Summary Add message
-// @Description Sends a message to classification queue
+// @Description Sends a message to classification queue, with wait=true the request blocks until the message is processed
 // @Tags message
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param message body core.NewMessage true "Message"
+// @Param wait query bool false "Wait for the message to be processed"
+// @Param timeout query int false "Seconds to wait, defaults to WORKER_MESSAGE_WAIT_TIMEOUT and up to WORKER_MESSAGE_WAIT_MAX_TIMEOUT"
+// @Success 200 {object} core.MessageTask
 // @Success 202 {object} MessageResponse
 // @Failure 400 {object} any
 // @Failure 500 {object} any
+// @Failure 504 {object} any
 // @Router /message [post]
 func (h *MessageHandler) AddMessage(context *gin.Context) {
 	var m core.NewMessage
@@ -36,6 +53,24 @@ func (h *MessageHandler) AddMessage(context *gin.Context) {
 		context.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
+	wait := false
+	if waitStr := context.Query("wait"); waitStr != "" {
+		var err error
+		wait, err = strconv.ParseBool(waitStr)
+		if err != nil {
+			context.JSON(400, gin.H{"error": "Invalid wait"})
+			return
+		}
+	}
+	timeout := config.Worker.MessageWaitTimeout
+	if timeoutStr := context.Query("timeout"); timeoutStr != "" {
+		var err error
+		timeout, err = strconv.Atoi(timeoutStr)
+		if err != nil || timeout <= 0 || timeout > config.Worker.MessageWaitMaxTimeout {
+			context.JSON(400, gin.H{"error": "Invalid timeout"})
+			return
+		}
+	}
 	chatId, err := h.chatService.GetByExternalId(context, m.ChatId)
 	if err == core.ChatNotFound {
 		slog.Info("Chat not found", "chat_id", m.ChatId)
@@ -47,12 +82,157 @@ func (h *MessageHandler) AddMessage(context *gin.Context) {
 		context.JSON(500, gin.H{"error": "Error getting chat"})
 		return
 	}
-	if err := service.AddMessage(*chatId, m.Message, m.RelatedContext); err != nil {
+	taskId, err := h.messageService.Add(context, *chatId, m.Message, m.RelatedContext)
+	if err != nil {
 		slog.Error("Error adding message", "error", err)
 		context.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
-	messageResponse := MessageResponse{Message: "Message accepted"}
+	if wait {
+		messageTask, err := h.messageService.Wait(
+			context, taskId, time.Duration(timeout)*time.Second,
+		)
+		if err == core.MessageWaitTimeout {
+			context.JSON(504, gin.H{"error": err.Error(), "task": messageTask})
+			return
+		}
+		if err != nil {
+			slog.Error("Error waiting for message", "error", err)
+			context.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		context.JSON(200, messageTask)
+		return
+	}
+	messageResponse := MessageResponse{Message: "Message accepted", TaskId: taskId}
 	context.Header("Content-Type", "application/json")
 	context.JSON(202, messageResponse)
 }
+
+type MessageBatchResponse struct {
+	Items []core.MessageBatchItem `json:"items"`
+}
+
+// @Summary Add messages
+// @Description Sends several messages, possibly across chats, to the classification queue at once. Every chat is validated before anything is queued
+// @Tags message
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param messages body core.NewMessageBatch true "Messages"
+// @Success 202 {object} MessageBatchResponse
+// @Failure 400 {object} any
+// @Failure 500 {object} any
+// @Router /message/batch [post]
+func (h *MessageHandler) AddMessages(context *gin.Context) {
+	var batch core.NewMessageBatch
+	if err := context.BindJSON(&batch); err != nil {
+		context.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.messageService.CheckBatchSize(len(batch.Messages)); err != nil {
+		context.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	chatIds := map[string]string{}
+	var missingChats []string
+	for _, m := range batch.Messages {
+		if _, ok := chatIds[m.ChatId]; ok {
+			continue
+		}
+		chatId, err := h.chatService.GetByExternalId(context, m.ChatId)
+		if err == core.ChatNotFound {
+			chatIds[m.ChatId] = ""
+			missingChats = append(missingChats, m.ChatId)
+			continue
+		}
+		if chatId == nil || err != nil {
+			slog.Error("Error getting chat", "error", err)
+			context.JSON(500, gin.H{"error": "Error getting chat"})
+			return
+		}
+		chatIds[m.ChatId] = *chatId
+	}
+	if len(missingChats) > 0 {
+		context.JSON(400, gin.H{"error": "Chat not found", "chat_ids": missingChats})
+		return
+	}
+	messages := make([]core.NewMessage, len(batch.Messages))
+	for i, m := range batch.Messages {
+		messages[i] = m
+		messages[i].ChatId = chatIds[m.ChatId]
+	}
+	items, err := h.messageService.AddBatch(context, messages)
+	if err != nil {
+		slog.Error("Error adding messages", "error", err)
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	// Report the chats the way the caller knows them
+	for i := range items {
+		items[i].ChatId = batch.Messages[i].ChatId
+	}
+	context.JSON(202, MessageBatchResponse{Items: items})
+}
+
+// @Summary Get message task
+// @Description Reports what happened to a message: queued, classifying, retrying, stored, merged, covered (by a similar memory), discarded (NoMemory) or failed once no attempt is left
+// @Tags message
+// @Security BearerAuth
+// @Produce json
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} core.MessageTask
+// @Failure 404 {object} any
+// @Failure 500 {object} any
+// @Router /message/task/{task_id} [get]
+func (h *MessageHandler) GetTask(context *gin.Context) {
+	taskId := context.Param("task_id")
+	messageTask, err := h.messageService.GetTask(context, taskId)
+	if err == core.MessageNotFound {
+		context.JSON(404, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Error getting message task", "error", err)
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(200, messageTask)
+}
+
+// @Summary Get chat messages
+// @Description Get the transcript of a chat, oldest first, with the label and the memory each message produced or was merged into
+// @Tags message
+// @Security BearerAuth
+// @Produce json
+// @Param chat_id path string true "Chat ID"
+// @Param limit query int false "Limit, defaults to 50"
+// @Param offset query int false "Offset"
+// @Success 200 {object} core.MessageArray
+// @Failure 400 {object} any
+// @Failure 404 {object} any
+// @Failure 500 {object} any
+// @Router /chat/{chat_id}/messages [get]
+func (h *MessageHandler) GetChatMessages(context *gin.Context) {
+	chatId := context.Param("chat_id")
+	limit, err := strconv.Atoi(context.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		context.JSON(400, gin.H{"error": "Invalid limit"})
+		return
+	}
+	offset, err := strconv.Atoi(context.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		context.JSON(400, gin.H{"error": "Invalid offset"})
+		return
+	}
+	messages, err := h.messageService.GetByChatId(context, chatId, limit, offset)
+	if err == core.ChatNotFound {
+		context.JSON(404, gin.H{"error": "Chat not found"})
+		return
+	}
+	if err != nil {
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(200, messages)
+}
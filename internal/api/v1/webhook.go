@@ -0,0 +1,133 @@
+package v1
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/service"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// @Summary Register webhook
+// @Description Registers a url to be called when memories of a chat, or of every chat, change.
+// @Description Each call is signed with the secret, which is only shown in this response:
+// @Description X-BetterMem-Signature is "sha256=" followed by the hex HMAC-SHA256 of "<X-BetterMem-Timestamp>.<body>".
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body core.NewWebhook true "New Webhook"
+// @Success 201 {object} core.RegisteredWebhook
+// @Failure 400 {object} any
+// @Failure 404 {object} any
+// @Failure 500 {object} any
+// @Router /webhook [post]
+func (h *WebhookHandler) RegisterWebhook(context *gin.Context) {
+	var request core.NewWebhook
+	if err := context.BindJSON(&request); err != nil {
+		context.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	webhook, err := h.webhookService.Register(context, &request)
+	if errors.Is(err, core.InvalidWebhookUrl) || errors.Is(err, core.InvalidWebhookEvent) {
+		context.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, core.ChatNotFound) {
+		context.JSON(404, gin.H{"error": "Chat not found"})
+		return
+	}
+	if err != nil {
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(201, webhook)
+}
+
+// @Summary Get webhooks
+// @Description Lists the webhooks of the tenant, without their secrets
+// @Tags webhook
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} core.Webhook
+// @Failure 500 {object} any
+// @Router /webhook [get]
+func (h *WebhookHandler) GetWebhooks(context *gin.Context) {
+	webhooks, err := h.webhookService.GetAll(context)
+	if err != nil {
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if webhooks == nil {
+		context.JSON(200, []*core.Webhook{})
+		return
+	}
+	context.JSON(200, webhooks)
+}
+
+// @Summary Delete webhook
+// @Description Deletes a webhook, pending retries are dropped
+// @Tags webhook
+// @Security BearerAuth
+// @Param webhook_id path string true "Webhook ID"
+// @Success 204
+// @Failure 404 {object} any
+// @Router /webhook/{webhook_id} [delete]
+func (h *WebhookHandler) DeleteWebhook(context *gin.Context) {
+	err := h.webhookService.Delete(context, context.Param("webhook_id"))
+	if errors.Is(err, core.WebhookNotFound) {
+		context.JSON(404, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	context.Status(204)
+}
+
+// @Summary Get webhook deliveries
+// @Description Returns the delivery log of a webhook, one entry per attempt, the latest first
+// @Tags webhook
+// @Produce json
+// @Security BearerAuth
+// @Param webhook_id path string true "Webhook ID"
+// @Param limit query int false "Limit, defaults to 50"
+// @Param offset query int false "Offset"
+// @Success 200 {array} core.WebhookDelivery
+// @Failure 400 {object} any
+// @Failure 404 {object} any
+// @Router /webhook/{webhook_id}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(context *gin.Context) {
+	limit, err := strconv.Atoi(context.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		context.JSON(400, gin.H{"error": "Invalid limit"})
+		return
+	}
+	offset, err := strconv.Atoi(context.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		context.JSON(400, gin.H{"error": "Invalid offset"})
+		return
+	}
+	deliveries, err := h.webhookService.GetDeliveries(
+		context, context.Param("webhook_id"), limit, offset,
+	)
+	if errors.Is(err, core.WebhookNotFound) {
+		context.JSON(404, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(200, deliveries)
+}
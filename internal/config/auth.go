@@ -0,0 +1,17 @@
+package config
+
+type authConfig struct {
+	// Whether requests must carry an api key
+	Enabled bool
+	// Key with the admin scope that is always accepted,
+	// used to issue the first api keys
+	RootKey string
+}
+
+func newAuthConfig() authConfig {
+	enabled := getBool("API_AUTH_ENABLED", false)
+	rootKey := getString("API_ROOT_KEY", "")
+	return authConfig{Enabled: enabled, RootKey: rootKey}
+}
+
+var Auth = newAuthConfig()
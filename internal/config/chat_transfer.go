@@ -0,0 +1,19 @@
+package config
+
+type chatTransferConfig struct {
+	// Most memories embedded at once when importing
+	ImportEmbedBatchSize int
+	// Longest line accepted by an import, in bytes
+	ImportMaxLineBytes int
+}
+
+func newChatTransferConfig() chatTransferConfig {
+	importEmbedBatchSize := getInt("CHAT_IMPORT_EMBED_BATCH_SIZE", 64)
+	importMaxLineBytes := getInt("CHAT_IMPORT_MAX_LINE_BYTES", 1024*1024)
+	return chatTransferConfig{
+		ImportEmbedBatchSize: importEmbedBatchSize,
+		ImportMaxLineBytes:   importMaxLineBytes,
+	}
+}
+
+var ChatTransfer = newChatTransferConfig()
@@ -0,0 +1,19 @@
+package config
+
+type contextConfig struct {
+	// Template used when a context request does not pick one
+	DefaultTemplate string
+	// Directory with *.tmpl files registered as templates under their file name, empty to skip
+	TemplatesDir string
+}
+
+func newContextConfig() contextConfig {
+	defaultTemplate := getString("CONTEXT_DEFAULT_TEMPLATE", "bullet")
+	templatesDir := getString("CONTEXT_TEMPLATES_DIR", "")
+	return contextConfig{
+		DefaultTemplate: defaultTemplate,
+		TemplatesDir:    templatesDir,
+	}
+}
+
+var Context = newContextConfig()
@@ -0,0 +1,23 @@
+package config
+
+type conversationConfig struct {
+	// How a fetch with turns builds its query: embedding or rewrite
+	QueryStrategy string
+	// Most recent turns taken into account, older ones are dropped
+	MaxTurns int
+	// Weight kept by each turn going back from the fetch text, which weighs 1
+	TurnDecay float32
+}
+
+func newConversationConfig() conversationConfig {
+	queryStrategy := getString("CONVERSATION_QUERY_STRATEGY", "embedding")
+	maxTurns := getInt("CONVERSATION_MAX_TURNS", 6)
+	turnDecay := getFloat32("CONVERSATION_TURN_DECAY", 0.5)
+	return conversationConfig{
+		QueryStrategy: queryStrategy,
+		MaxTurns:      maxTurns,
+		TurnDecay:     turnDecay,
+	}
+}
+
+var Conversation = newConversationConfig()
@@ -0,0 +1,23 @@
+package config
+
+type eventsConfig struct {
+	// Milliseconds between the event log reads of a stream in local mode
+	PollInterval int
+	// Seconds between the keep alive comments of a stream
+	KeepAlive int
+	// Most events read from the log at once
+	PageSize int
+}
+
+func newEventsConfig() eventsConfig {
+	pollInterval := getInt("EVENTS_POLL_INTERVAL_MS", 500)
+	keepAlive := getInt("EVENTS_KEEP_ALIVE", 15)
+	pageSize := getInt("EVENTS_PAGE_SIZE", 100)
+	return eventsConfig{
+		PollInterval: pollInterval,
+		KeepAlive:    keepAlive,
+		PageSize:     pageSize,
+	}
+}
+
+var Events = newEventsConfig()
@@ -0,0 +1,19 @@
+package config
+
+type fetchConfig struct {
+	// Most fetches a batch can carry
+	BatchMaxSize int
+	// Fetches of a batch that run at the same time
+	BatchConcurrency int
+}
+
+func newFetchConfig() fetchConfig {
+	batchMaxSize := getInt("FETCH_BATCH_MAX_SIZE", 32)
+	batchConcurrency := getInt("FETCH_BATCH_CONCURRENCY", 8)
+	return fetchConfig{
+		BatchMaxSize:     batchMaxSize,
+		BatchConcurrency: batchConcurrency,
+	}
+}
+
+var Fetch = newFetchConfig()
@@ -0,0 +1,28 @@
+package config
+
+type retentionConfig struct {
+	// Days a memory stays inactive before being permanently deleted,
+	// 0 (the default) keeps them forever
+	InactiveMemoryDays int
+	// Cron spec of the purge in server mode
+	PurgeTaskPeriod string
+	// Seconds between the purges in local mode
+	PurgeTaskPeriodInt int
+	// Most memories deleted at once
+	PurgeBatchSize int
+}
+
+func newRetentionConfig() retentionConfig {
+	inactiveMemoryDays := getInt("RETENTION_INACTIVE_MEMORY_DAYS", 0)
+	purgeTaskPeriod := getString("RETENTION_PURGE_TASK_PERIOD", "@every 1h")
+	purgeTaskPeriodInt := getInt("RETENTION_PURGE_TASK_PERIOD_SECONDS_INT", 60*60)
+	purgeBatchSize := getInt("RETENTION_PURGE_BATCH_SIZE", 500)
+	return retentionConfig{
+		InactiveMemoryDays: inactiveMemoryDays,
+		PurgeTaskPeriod:    purgeTaskPeriod,
+		PurgeTaskPeriodInt: purgeTaskPeriodInt,
+		PurgeBatchSize:     purgeBatchSize,
+	}
+}
+
+var Retention = newRetentionConfig()
@@ -0,0 +1,29 @@
+package config
+
+type scoringConfig struct {
+	// Scorer used when a fetch does not pick one
+	DefaultScorer string
+	// Weights of the weighted scorer, they do not need to add up to one
+	SimilarityWeight float32
+	RecencyWeight    float32
+	UsageWeight      float32
+	// Hours for the recency of a memory to halve, used by the decaying scorers
+	HalfLifeHours float32
+}
+
+func newScoringConfig() scoringConfig {
+	defaultScorer := getString("SCORING_DEFAULT_SCORER", "relevancy")
+	similarityWeight := getFloat32("SCORING_SIMILARITY_WEIGHT", 0.6)
+	recencyWeight := getFloat32("SCORING_RECENCY_WEIGHT", 0.25)
+	usageWeight := getFloat32("SCORING_USAGE_WEIGHT", 0.15)
+	halfLifeHours := getFloat32("SCORING_HALF_LIFE_HOURS", 72)
+	return scoringConfig{
+		DefaultScorer:    defaultScorer,
+		SimilarityWeight: similarityWeight,
+		RecencyWeight:    recencyWeight,
+		UsageWeight:      usageWeight,
+		HalfLifeHours:    halfLifeHours,
+	}
+}
+
+var Scoring = newScoringConfig()
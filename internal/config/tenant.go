@@ -0,0 +1,18 @@
+package config
+
+type tenantConfig struct {
+	// Quota given to tenants created without one, zero means unlimited
+	DefaultMaxChats    int
+	DefaultMaxMemories int
+}
+
+func newTenantConfig() tenantConfig {
+	defaultMaxChats := getInt("TENANT_DEFAULT_MAX_CHATS", 0)
+	defaultMaxMemories := getInt("TENANT_DEFAULT_MAX_MEMORIES", 0)
+	return tenantConfig{
+		DefaultMaxChats:    defaultMaxChats,
+		DefaultMaxMemories: defaultMaxMemories,
+	}
+}
+
+var Tenant = newTenantConfig()
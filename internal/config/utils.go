@@ -72,3 +72,26 @@ func getFloat32(envVarName string, defaultValue float32) float32 {
 	}
 	return float32(floatValue)
 }
+
+func getBool(envVarName string, defaultValue bool) bool {
+	envVar, exists := os.LookupEnv(envVarName)
+	if !exists {
+		printWarning(
+			"Using default value for environment variable",
+			slog.String("envVarName", envVarName),
+			slog.Bool("defaultValue", defaultValue),
+		)
+		return defaultValue
+	}
+	boolValue, err := strconv.ParseBool(envVar)
+	if err != nil {
+		printWarning(
+			"Failed to parse environment variable",
+			slog.String("envVarName", envVarName),
+			slog.String("envVarValue", envVar),
+			slog.Bool("defaultValue", defaultValue),
+		)
+		return defaultValue
+	}
+	return boolValue
+}
@@ -0,0 +1,23 @@
+package config
+
+type webhookConfig struct {
+	// Most attempts at delivering an event to a webhook
+	MaxAttempts int
+	// Timeout of a delivery request in seconds
+	Timeout int
+	// Seconds before the first retry, doubled on every retry
+	RetryBackoff int
+}
+
+func newWebhookConfig() webhookConfig {
+	maxAttempts := getInt("WEBHOOK_MAX_ATTEMPTS", 5)
+	timeout := getInt("WEBHOOK_TIMEOUT", 10)
+	retryBackoff := getInt("WEBHOOK_RETRY_BACKOFF", 30)
+	return webhookConfig{
+		MaxAttempts:  maxAttempts,
+		Timeout:      timeout,
+		RetryBackoff: retryBackoff,
+	}
+}
+
+var Webhook = newWebhookConfig()
@@ -7,16 +7,28 @@ type workerConfig struct {
 	Timeout int
 	// Concurrency for task
 	Concurrency int
+	// Time in seconds a request waits for its message to be processed
+	MessageWaitTimeout int
+	// Max time in seconds a request can ask to wait for its message
+	MessageWaitMaxTimeout int
+	// Max number of messages accepted in a single batch
+	MessageBatchMaxSize int
 }
 
 func newWorkerConfig() *workerConfig {
 	maxRetry := getInt("WORKER_MAX_RETRY", 5)
 	timeout := getInt("WORKER_TIMEOUT", 60)
 	concurrency := getInt("WORKER_CONCURRENCY", 5)
+	messageWaitTimeout := getInt("WORKER_MESSAGE_WAIT_TIMEOUT", 30)
+	messageWaitMaxTimeout := getInt("WORKER_MESSAGE_WAIT_MAX_TIMEOUT", 120)
+	messageBatchMaxSize := getInt("WORKER_MESSAGE_BATCH_MAX_SIZE", 100)
 	return &workerConfig{
-		MaxRetry:    maxRetry,
-		Timeout:     timeout,
-		Concurrency: concurrency,
+		MaxRetry:              maxRetry,
+		Timeout:               timeout,
+		Concurrency:           concurrency,
+		MessageWaitTimeout:    messageWaitTimeout,
+		MessageWaitMaxTimeout: messageWaitMaxTimeout,
+		MessageBatchMaxSize:   messageBatchMaxSize,
 	}
 }
 
@@ -3,6 +3,7 @@ package mongo
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -11,6 +12,7 @@ import (
 
 type Chat struct {
 	ID         string `bson:"_id,omitempty"`
+	TenantID   string `bson:"tenant_id"`
 	ExternalID string `bson:"external_id"`
 }
 
@@ -22,6 +24,7 @@ type chatConfig struct {
 func ChatConfig() chatConfig {
 	indexes := mongo.IndexModel{
 		Keys: bson.D{
+			{Key: "tenant_id", Value: 1},
 			{Key: "external_id", Value: 1},
 		},
 		Options: options.Index().SetUnique(true),
@@ -32,35 +35,51 @@ func ChatConfig() chatConfig {
 	}
 }
 
-// TODO: Add related context here
 type LongTermMemory struct {
-	ID          string `bson:"_id,omitempty"`
-	Memory      string `bson:"memory"`
-	ChatID      string `bson:"chat_id"`
-	AccessCount int    `bson:"access_count"`
-	CreatedAt   string `bson:"created_at"`
-	Active      bool   `bson:"active"`
+	ID             string                  `bson:"_id,omitempty"`
+	Memory         string                  `bson:"memory"`
+	TenantID       string                  `bson:"tenant_id"`
+	ChatID         string                  `bson:"chat_id"`
+	AccessCount    int                     `bson:"access_count"`
+	CreatedAt      string                  `bson:"created_at"`
+	Active         bool                    `bson:"active"`
+	RelatedContext []MessageRelatedContext `bson:"related_context"`
+	// When the memory was first deactivated, the retention counts from it
+	DeactivatedAt *time.Time `bson:"deactivated_at,omitempty"`
 }
 
 type ShortTermMemory struct {
-	ID          string `bson:"_id,omitempty"`
-	Memory      string `bson:"memory"`
-	ChatID      string `bson:"chat_id"`
-	AccessCount int    `bson:"access_count"`
-	MergeCount  int    `bson:"merge_count"`
-	Merged      bool   `bson:"merged"`
-	CreatedAt   string `bson:"created_at"`
-	Active      bool   `bson:"active"`
+	ID             string                  `bson:"_id,omitempty"`
+	Memory         string                  `bson:"memory"`
+	TenantID       string                  `bson:"tenant_id"`
+	ChatID         string                  `bson:"chat_id"`
+	AccessCount    int                     `bson:"access_count"`
+	MergeCount     int                     `bson:"merge_count"`
+	Merged         bool                    `bson:"merged"`
+	CreatedAt      string                  `bson:"created_at"`
+	Active         bool                    `bson:"active"`
+	RelatedContext []MessageRelatedContext `bson:"related_context"`
+	// When the memory was first deactivated, the retention counts from it
+	DeactivatedAt *time.Time `bson:"deactivated_at,omitempty"`
 }
 
 type MemoryConfig struct {
 	CollectionName string
 	Indexes        mongo.IndexModel
+	// Used by the keyword search
+	TextIndexes mongo.IndexModel
+}
+
+func memoryTextIndexes() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{{Key: "memory", Value: "text"}},
+	}
 }
 
 func LongTermMemoryConfig() MemoryConfig {
 	indexes := mongo.IndexModel{
 		Keys: bson.D{
+			{Key: "tenant_id", Value: 1},
 			{Key: "chat_id", Value: 1},
 			{Key: "created_at", Value: -1},
 		},
@@ -69,12 +88,14 @@ func LongTermMemoryConfig() MemoryConfig {
 	return MemoryConfig{
 		CollectionName: "long_term_memory",
 		Indexes:        indexes,
+		TextIndexes:    memoryTextIndexes(),
 	}
 }
 
 func ShortTermMemoryConfig() MemoryConfig {
 	indexes := mongo.IndexModel{
 		Keys: bson.D{
+			{Key: "tenant_id", Value: 1},
 			{Key: "chat_id", Value: 1},
 			{Key: "created_at", Value: -1},
 		},
@@ -83,6 +104,251 @@ func ShortTermMemoryConfig() MemoryConfig {
 	return MemoryConfig{
 		CollectionName: "short_term_memory",
 		Indexes:        indexes,
+		TextIndexes:    memoryTextIndexes(),
+	}
+}
+
+type ApiKey struct {
+	ID        string    `bson:"_id,omitempty"`
+	Name      string    `bson:"name"`
+	Prefix    string    `bson:"prefix"`
+	Hash      string    `bson:"hash"`
+	Scopes    []string  `bson:"scopes"`
+	TenantID  string    `bson:"tenant_id"`
+	CreatedAt time.Time `bson:"created_at"`
+	Revoked   bool      `bson:"revoked"`
+}
+
+type apiKeyConfig struct {
+	CollectionName string
+	Indexes        mongo.IndexModel
+}
+
+func ApiKeyConfig() apiKeyConfig {
+	indexes := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "hash", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+	return apiKeyConfig{
+		CollectionName: "api_keys",
+		Indexes:        indexes,
+	}
+}
+
+type Tenant struct {
+	ID          string    `bson:"_id"`
+	Name        string    `bson:"name"`
+	MaxChats    int       `bson:"max_chats"`
+	MaxMemories int       `bson:"max_memories"`
+	CreatedAt   time.Time `bson:"created_at"`
+}
+
+type tenantConfig struct {
+	CollectionName string
+}
+
+func TenantConfig() tenantConfig {
+	return tenantConfig{
+		CollectionName: "tenants",
+	}
+}
+
+type MessageRelatedContext struct {
+	Context string `bson:"context"`
+	User    string `bson:"user"`
+}
+
+type Message struct {
+	ID             string                  `bson:"_id"`
+	TenantID       string                  `bson:"tenant_id"`
+	ChatID         string                  `bson:"chat_id"`
+	Message        string                  `bson:"message"`
+	RelatedContext []MessageRelatedContext `bson:"related_context"`
+	Status         string                  `bson:"status"`
+	Label          int                     `bson:"label"`
+	MemoryID       string                  `bson:"memory_id"`
+	Error          string                  `bson:"error"`
+	Attempts       int                     `bson:"attempts"`
+	CreatedAt      time.Time               `bson:"created_at"`
+	UpdatedAt      time.Time               `bson:"updated_at"`
+}
+
+type messageConfig struct {
+	CollectionName string
+	Indexes        mongo.IndexModel
+}
+
+func MessageConfig() messageConfig {
+	indexes := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "tenant_id", Value: 1},
+			{Key: "chat_id", Value: 1},
+			{Key: "created_at", Value: 1},
+		},
+	}
+	return messageConfig{
+		CollectionName: "messages",
+		Indexes:        indexes,
+	}
+}
+
+type MemoryMerge struct {
+	ID                     string                  `bson:"_id"`
+	TenantID               string                  `bson:"tenant_id"`
+	MemoryID               string                  `bson:"memory_id"`
+	PreviousMemory         string                  `bson:"previous_memory"`
+	PreviousRelatedContext []MessageRelatedContext `bson:"previous_related_context"`
+	IncomingMemory         string                  `bson:"incoming_memory"`
+	Score                  float32                 `bson:"score"`
+	CreatedAt              time.Time               `bson:"created_at"`
+}
+
+type memoryMergeConfig struct {
+	CollectionName string
+	Indexes        mongo.IndexModel
+}
+
+func MemoryMergeConfig() memoryMergeConfig {
+	indexes := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "tenant_id", Value: 1},
+			{Key: "memory_id", Value: 1},
+		},
+	}
+	return memoryMergeConfig{
+		CollectionName: "memory_merges",
+		Indexes:        indexes,
+	}
+}
+
+type Webhook struct {
+	ID        string    `bson:"_id"`
+	TenantID  string    `bson:"tenant_id"`
+	ChatID    string    `bson:"chat_id"`
+	Url       string    `bson:"url"`
+	Secret    string    `bson:"secret"`
+	Events    []string  `bson:"events"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+type webhookConfig struct {
+	CollectionName string
+	Indexes        mongo.IndexModel
+}
+
+func WebhookConfig() webhookConfig {
+	indexes := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "tenant_id", Value: 1},
+			{Key: "chat_id", Value: 1},
+		},
+	}
+	return webhookConfig{
+		CollectionName: "webhooks",
+		Indexes:        indexes,
+	}
+}
+
+type WebhookDelivery struct {
+	ID         string    `bson:"_id"`
+	TenantID   string    `bson:"tenant_id"`
+	WebhookID  string    `bson:"webhook_id"`
+	EventID    string    `bson:"event_id"`
+	EventType  string    `bson:"event_type"`
+	Attempt    int       `bson:"attempt"`
+	StatusCode int       `bson:"status_code"`
+	Success    bool      `bson:"success"`
+	Error      string    `bson:"error"`
+	Duration   int64     `bson:"duration_ms"`
+	CreatedAt  time.Time `bson:"created_at"`
+}
+
+type webhookDeliveryConfig struct {
+	CollectionName string
+	Indexes        mongo.IndexModel
+}
+
+func WebhookDeliveryConfig() webhookDeliveryConfig {
+	indexes := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "tenant_id", Value: 1},
+			{Key: "webhook_id", Value: 1},
+			{Key: "created_at", Value: -1},
+		},
+	}
+	return webhookDeliveryConfig{
+		CollectionName: "webhook_deliveries",
+		Indexes:        indexes,
+	}
+}
+
+type MemoryEvent struct {
+	ID string `bson:"_id"`
+	// Taken from the counters collection, used as the cursor of the event streams
+	Seq          int64     `bson:"seq"`
+	TenantID     string    `bson:"tenant_id"`
+	ChatID       string    `bson:"chat_id"`
+	Type         string    `bson:"type"`
+	MemoryID     string    `bson:"memory_id"`
+	MemoryType   int       `bson:"memory_type"`
+	Memory       string    `bson:"memory"`
+	PromotedFrom string    `bson:"promoted_from"`
+	OccurredAt   time.Time `bson:"occurred_at"`
+}
+
+type memoryEventConfig struct {
+	CollectionName string
+	// Holds the last sequence given to an event
+	CountersCollectionName string
+	Indexes                mongo.IndexModel
+}
+
+func MemoryEventConfig() memoryEventConfig {
+	indexes := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "tenant_id", Value: 1},
+			{Key: "chat_id", Value: 1},
+			{Key: "seq", Value: 1},
+		},
+	}
+	return memoryEventConfig{
+		CollectionName:         "memory_events",
+		CountersCollectionName: "counters",
+		Indexes:                indexes,
+	}
+}
+
+type RetentionRun struct {
+	ID                      string    `bson:"_id"`
+	RetentionDays           int       `bson:"retention_days"`
+	Cutoff                  time.Time `bson:"cutoff"`
+	Tenants                 int       `bson:"tenants"`
+	ShortTermMemories       int       `bson:"short_term_memories"`
+	LongTermMemories        int       `bson:"long_term_memories"`
+	Vectors                 int       `bson:"vectors"`
+	MemoryMerges            int       `bson:"memory_merges"`
+	OrphanedRelatedContexts int       `bson:"orphaned_related_contexts"`
+	Error                   string    `bson:"error"`
+	StartedAt               time.Time `bson:"started_at"`
+	CompletedAt             time.Time `bson:"completed_at"`
+}
+
+type retentionRunConfig struct {
+	CollectionName string
+	Indexes        mongo.IndexModel
+}
+
+func RetentionRunConfig() retentionRunConfig {
+	indexes := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "started_at", Value: -1},
+		},
+	}
+	return retentionRunConfig{
+		CollectionName: "retention_runs",
+		Indexes:        indexes,
 	}
 }
 
@@ -93,10 +359,27 @@ func CreateCollections(db mongo.Database) error {
 	longTermMemoryConfig := LongTermMemoryConfig()
 	shortTermMemoryConfig := ShortTermMemoryConfig()
 	chatConfig := ChatConfig()
+	apiKeyConfig := ApiKeyConfig()
+	tenantConfig := TenantConfig()
+	messageConfig := MessageConfig()
+	memoryMergeConfig := MemoryMergeConfig()
+	webhookConfig := WebhookConfig()
+	webhookDeliveryConfig := WebhookDeliveryConfig()
+	memoryEventConfig := MemoryEventConfig()
+	retentionRunConfig := RetentionRunConfig()
 	collections := []string{
 		longTermMemoryConfig.CollectionName,
 		shortTermMemoryConfig.CollectionName,
 		chatConfig.CollectionName,
+		apiKeyConfig.CollectionName,
+		tenantConfig.CollectionName,
+		messageConfig.CollectionName,
+		memoryMergeConfig.CollectionName,
+		webhookConfig.CollectionName,
+		webhookDeliveryConfig.CollectionName,
+		memoryEventConfig.CollectionName,
+		memoryEventConfig.CountersCollectionName,
+		retentionRunConfig.CollectionName,
 	}
 	for _, collection := range collections {
 		err := db.CreateCollection(ctx, collection)
@@ -117,6 +400,13 @@ func CreateIndexes(db mongo.Database) error {
 	longTermMemoryConfig := LongTermMemoryConfig()
 	shortTermMemoryConfig := ShortTermMemoryConfig()
 	chatConfig := ChatConfig()
+	apiKeyConfig := ApiKeyConfig()
+	messageConfig := MessageConfig()
+	memoryMergeConfig := MemoryMergeConfig()
+	webhookConfig := WebhookConfig()
+	webhookDeliveryConfig := WebhookDeliveryConfig()
+	memoryEventConfig := MemoryEventConfig()
+	retentionRunConfig := RetentionRunConfig()
 
 	ctx := context.Background()
 	defer ctx.Done()
@@ -147,6 +437,27 @@ func CreateIndexes(db mongo.Database) error {
 		return err
 	}
 
+	for _, memoryConfig := range []MemoryConfig{longTermMemoryConfig, shortTermMemoryConfig} {
+		_, err = db.Collection(
+			memoryConfig.CollectionName,
+		).Indexes().CreateOne(
+			ctx, memoryConfig.TextIndexes,
+		)
+		if err != nil {
+			slog.Error(
+				"failed to create text indexes",
+				"collection", memoryConfig.CollectionName,
+				"error", err,
+			)
+			return err
+		}
+	}
+
+	// External ids used to be unique globally, now they are unique per tenant
+	_, _ = db.Collection(
+		chatConfig.CollectionName,
+	).Indexes().DropOne(ctx, "external_id_1")
+
 	_, err = db.Collection(
 		chatConfig.CollectionName,
 	).Indexes().CreateOne(
@@ -160,5 +471,96 @@ func CreateIndexes(db mongo.Database) error {
 		)
 		return err
 	}
+
+	_, err = db.Collection(
+		apiKeyConfig.CollectionName,
+	).Indexes().CreateOne(
+		ctx, apiKeyConfig.Indexes,
+	)
+	if err != nil {
+		slog.Error(
+			"failed to create indexes for api key",
+			"error", err,
+		)
+		return err
+	}
+
+	_, err = db.Collection(
+		messageConfig.CollectionName,
+	).Indexes().CreateOne(
+		ctx, messageConfig.Indexes,
+	)
+	if err != nil {
+		slog.Error(
+			"failed to create indexes for message",
+			"error", err,
+		)
+		return err
+	}
+
+	_, err = db.Collection(
+		memoryMergeConfig.CollectionName,
+	).Indexes().CreateOne(
+		ctx, memoryMergeConfig.Indexes,
+	)
+	if err != nil {
+		slog.Error(
+			"failed to create indexes for memory merge",
+			"error", err,
+		)
+		return err
+	}
+
+	_, err = db.Collection(
+		webhookConfig.CollectionName,
+	).Indexes().CreateOne(
+		ctx, webhookConfig.Indexes,
+	)
+	if err != nil {
+		slog.Error(
+			"failed to create indexes for webhook",
+			"error", err,
+		)
+		return err
+	}
+
+	_, err = db.Collection(
+		webhookDeliveryConfig.CollectionName,
+	).Indexes().CreateOne(
+		ctx, webhookDeliveryConfig.Indexes,
+	)
+	if err != nil {
+		slog.Error(
+			"failed to create indexes for webhook delivery",
+			"error", err,
+		)
+		return err
+	}
+
+	_, err = db.Collection(
+		memoryEventConfig.CollectionName,
+	).Indexes().CreateOne(
+		ctx, memoryEventConfig.Indexes,
+	)
+	if err != nil {
+		slog.Error(
+			"failed to create indexes for memory event",
+			"error", err,
+		)
+		return err
+	}
+
+	_, err = db.Collection(
+		retentionRunConfig.CollectionName,
+	).Indexes().CreateOne(
+		ctx, retentionRunConfig.Indexes,
+	)
+	if err != nil {
+		slog.Error(
+			"failed to create indexes for retention run",
+			"error", err,
+		)
+		return err
+	}
 	return nil
 }
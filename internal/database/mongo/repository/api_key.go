@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/mongo"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ApiKeyRepository struct {
+	*mongoDriver.Collection
+}
+
+func NewApiKeyRepository() *ApiKeyRepository {
+	collectionName := mongo.ApiKeyConfig().CollectionName
+	database := mongo.GetMongoDatabase()
+	return &ApiKeyRepository{
+		Collection: database.Collection(collectionName),
+	}
+}
+
+func apiKeyDbModelToSchema(k *mongo.ApiKey) *core.ApiKey {
+	var scopes []core.ApiKeyScope
+	for _, scope := range k.Scopes {
+		scopes = append(scopes, core.ApiKeyScope(scope))
+	}
+	return &core.ApiKey{
+		Id:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    scopes,
+		TenantId:  k.TenantID,
+		CreatedAt: k.CreatedAt,
+		Revoked:   k.Revoked,
+	}
+}
+
+// Create implements [repository.ApiKeyRepository].
+func (r *ApiKeyRepository) Create(ctx context.Context, apiKey *core.ApiKeyModel) (*core.ApiKey, error) {
+	var scopes []string
+	for _, scope := range apiKey.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	dbApiKey := mongo.ApiKey{
+		Name:      apiKey.Name,
+		Prefix:    apiKey.Prefix,
+		Hash:      apiKey.Hash,
+		Scopes:    scopes,
+		TenantID:  apiKey.TenantId,
+		CreatedAt: apiKey.CreatedAt,
+	}
+	result, err := r.InsertOne(ctx, dbApiKey)
+	if err != nil {
+		return nil, err
+	}
+	dbApiKey.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return apiKeyDbModelToSchema(&dbApiKey), nil
+}
+
+// GetAll implements [repository.ApiKeyRepository].
+func (r *ApiKeyRepository) GetAll(ctx context.Context) ([]*core.ApiKey, error) {
+	cursor, err := r.Find(
+		ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var dbApiKeys []*mongo.ApiKey
+	if err := cursor.All(ctx, &dbApiKeys); err != nil {
+		return nil, err
+	}
+	var apiKeys []*core.ApiKey
+	for _, k := range dbApiKeys {
+		apiKeys = append(apiKeys, apiKeyDbModelToSchema(k))
+	}
+	return apiKeys, nil
+}
+
+// GetByHash implements [repository.ApiKeyRepository].
+func (r *ApiKeyRepository) GetByHash(ctx context.Context, hash string) (*core.ApiKey, error) {
+	result := r.FindOne(ctx, bson.M{"hash": hash, "revoked": false})
+	if result.Err() == mongoDriver.ErrNoDocuments {
+		return nil, core.InvalidApiKey
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	var dbApiKey mongo.ApiKey
+	if err := result.Decode(&dbApiKey); err != nil {
+		return nil, err
+	}
+	return apiKeyDbModelToSchema(&dbApiKey), nil
+}
+
+// Revoke implements [repository.ApiKeyRepository].
+func (r *ApiKeyRepository) Revoke(ctx context.Context, id string) error {
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return core.ApiKeyNotFound
+	}
+	result, err := r.UpdateOne(
+		ctx,
+		bson.M{"_id": objectId},
+		bson.M{"$set": bson.M{"revoked": true}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return core.ApiKeyNotFound
+	}
+	return nil
+}
+
+var _ repository.ApiKeyRepository = (*ApiKeyRepository)(nil)
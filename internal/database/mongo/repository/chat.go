@@ -9,6 +9,7 @@ import (
 	"github.com/Mateus-Lacerda/better-mem/internal/repository"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	mongoDriver "go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -26,7 +27,10 @@ func NewChatRepository() *ChatRepository {
 
 // Create implements repository.ChatRepository.
 func (r *ChatRepository) Create(ctx context.Context, chat *core.NewChat) error {
-	dbChat := mongo.Chat{ExternalID: chat.ExternalId}
+	dbChat := mongo.Chat{
+		TenantID:   core.TenantFromContext(ctx),
+		ExternalID: chat.ExternalId,
+	}
 	result, err := r.InsertOne(ctx, dbChat)
 	if IsMongoDuplicateKeyError(err) {
 		return core.ChatExternalIdAlreadyExists
@@ -34,13 +38,16 @@ func (r *ChatRepository) Create(ctx context.Context, chat *core.NewChat) error {
 	if err != nil {
 		return err
 	}
-	slog.Info("Chat created", "id", result.InsertedID)
+	slog.Info("Chat created", "id", result.InsertedID, "tenant_id", dbChat.TenantID)
 	return nil
 }
 
 // GetByExternalID implements repository.ChatRepository.
 func (r *ChatRepository) GetByExternalID(ctx context.Context, externalID string) (*string, error) {
-	result := r.FindOne(ctx, bson.D{{Key: "externalid", Value: externalID}})
+	result := r.FindOne(ctx, bson.M{
+		"tenant_id":   tenantFilter(ctx),
+		"external_id": externalID,
+	})
 	if result.Err() == mongoDriver.ErrNoDocuments {
 		return nil, core.ChatNotFound
 	}
@@ -55,10 +62,37 @@ func (r *ChatRepository) GetByExternalID(ctx context.Context, externalID string)
 	return &chat.ID, nil
 }
 
+// GetById implements repository.ChatRepository.
+func (r *ChatRepository) GetById(ctx context.Context, id string) (*core.Chat, error) {
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, core.ChatNotFound
+	}
+	result := r.FindOne(ctx, bson.M{
+		"_id":       objectId,
+		"tenant_id": tenantFilter(ctx),
+	})
+	if result.Err() == mongoDriver.ErrNoDocuments {
+		return nil, core.ChatNotFound
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	var chat mongo.Chat
+	if err := result.Decode(&chat); err != nil {
+		return nil, err
+	}
+	return &core.Chat{
+		ExternalId: chat.ExternalID,
+		ID:         chat.ID,
+		TenantId:   core.TenantFromContext(ctx),
+	}, nil
+}
+
 // GetAll implements repository.ChatRepository.
 func (r *ChatRepository) GetAll(ctx context.Context) ([]*core.Chat, error) {
 	result, err := r.Find(
-		ctx, bson.D{},
+		ctx, bson.M{"tenant_id": tenantFilter(ctx)},
 	)
 	if err != nil {
 		slog.Error("Error getting all chats", "error", err)
@@ -75,9 +109,19 @@ func (r *ChatRepository) GetAll(ctx context.Context) ([]*core.Chat, error) {
 		chats = append(chats, &core.Chat{
 			ExternalId: chat.ExternalID,
 			ID:         chat.ID,
+			TenantId:   core.TenantFromContext(ctx),
 		})
 	}
 	return chats, nil
 }
 
+// Count implements repository.ChatRepository.
+func (r *ChatRepository) Count(ctx context.Context) (int, error) {
+	count, err := r.CountDocuments(ctx, bson.M{"tenant_id": tenantFilter(ctx)})
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
 var _ repository.ChatRepository = (*ChatRepository)(nil)
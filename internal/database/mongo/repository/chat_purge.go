@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/mongo"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+// Related context is embedded in the messages and merges here, so it
+// goes away with them and is not counted on its own
+type ChatPurgeRepository struct {
+	*mongoDriver.Database
+}
+
+func NewChatPurgeRepository() *ChatPurgeRepository {
+	return &ChatPurgeRepository{
+		Database: mongo.GetMongoDatabase(),
+	}
+}
+
+// A set of documents of the chat, the steps are in deletion order since
+// the chat and its memories are what the others are found through
+type chatPurgeStep struct {
+	collection string
+	counter    func(counts *core.ChatDataCounts) *int
+	filter     func(ctx context.Context, chatId string) (bson.M, error)
+}
+
+func (r *ChatPurgeRepository) steps() []chatPurgeStep {
+	byChat := func(ctx context.Context, chatId string) (bson.M, error) {
+		return bson.M{"tenant_id": tenantFilter(ctx), "chat_id": chatId}, nil
+	}
+	return []chatPurgeStep{
+		{
+			collection: mongo.MemoryMergeConfig().CollectionName,
+			counter:    func(c *core.ChatDataCounts) *int { return &c.MemoryMerges },
+			filter: func(ctx context.Context, chatId string) (bson.M, error) {
+				memoryIds, err := r.memoryIds(ctx, chatId)
+				if err != nil {
+					return nil, err
+				}
+				return bson.M{"tenant_id": tenantFilter(ctx), "memory_id": bson.M{"$in": memoryIds}}, nil
+			},
+		},
+		{
+			collection: mongo.WebhookDeliveryConfig().CollectionName,
+			counter:    func(c *core.ChatDataCounts) *int { return &c.WebhookDeliveries },
+			filter: func(ctx context.Context, chatId string) (bson.M, error) {
+				webhookIds, err := r.Collection(mongo.WebhookConfig().CollectionName).
+					Distinct(ctx, "_id", bson.M{"tenant_id": tenantFilter(ctx), "chat_id": chatId})
+				if err != nil {
+					return nil, err
+				}
+				return bson.M{"webhook_id": bson.M{"$in": append(bson.A{}, webhookIds...)}}, nil
+			},
+		},
+		{
+			collection: mongo.ShortTermMemoryConfig().CollectionName,
+			counter:    func(c *core.ChatDataCounts) *int { return &c.ShortTermMemories },
+			filter:     byChat,
+		},
+		{
+			collection: mongo.LongTermMemoryConfig().CollectionName,
+			counter:    func(c *core.ChatDataCounts) *int { return &c.LongTermMemories },
+			filter:     byChat,
+		},
+		{
+			collection: mongo.MessageConfig().CollectionName,
+			counter:    func(c *core.ChatDataCounts) *int { return &c.Messages },
+			filter:     byChat,
+		},
+		{
+			collection: mongo.MemoryEventConfig().CollectionName,
+			counter:    func(c *core.ChatDataCounts) *int { return &c.MemoryEvents },
+			filter:     byChat,
+		},
+		{
+			collection: mongo.WebhookConfig().CollectionName,
+			counter:    func(c *core.ChatDataCounts) *int { return &c.Webhooks },
+			filter:     byChat,
+		},
+		{
+			collection: mongo.ChatConfig().CollectionName,
+			counter:    func(c *core.ChatDataCounts) *int { return &c.Chats },
+			filter: func(ctx context.Context, chatId string) (bson.M, error) {
+				objectId, err := primitive.ObjectIDFromHex(chatId)
+				if err != nil {
+					return nil, core.ChatNotFound
+				}
+				return bson.M{"tenant_id": tenantFilter(ctx), "_id": objectId}, nil
+			},
+		},
+	}
+}
+
+// The ids of the short and long term memories of the chat, as the
+// merges store them
+func (r *ChatPurgeRepository) memoryIds(ctx context.Context, chatId string) (bson.A, error) {
+	filter := bson.M{"tenant_id": tenantFilter(ctx), "chat_id": chatId}
+	memoryIds := bson.A{}
+	for _, collection := range []string{
+		mongo.ShortTermMemoryConfig().CollectionName,
+		mongo.LongTermMemoryConfig().CollectionName,
+	} {
+		ids, err := r.Collection(collection).Distinct(ctx, "_id", filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			if objectId, ok := id.(primitive.ObjectID); ok {
+				memoryIds = append(memoryIds, objectId.Hex())
+			}
+		}
+	}
+	return memoryIds, nil
+}
+
+// Purge implements [repository.ChatPurgeRepository].
+// There is no transaction, a purge that fails midway is finished by
+// running it again
+func (r *ChatPurgeRepository) Purge(ctx context.Context, chatId string) (*core.ChatDataCounts, error) {
+	var deleted core.ChatDataCounts
+	for _, step := range r.steps() {
+		filter, err := step.filter(ctx, chatId)
+		if err != nil {
+			return nil, err
+		}
+		result, err := r.Collection(step.collection).DeleteMany(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("purging %s: %w", step.collection, err)
+		}
+		*step.counter(&deleted) = int(result.DeletedCount)
+	}
+	return &deleted, nil
+}
+
+// Count implements [repository.ChatPurgeRepository].
+func (r *ChatPurgeRepository) Count(ctx context.Context, chatId string) (*core.ChatDataCounts, error) {
+	var counts core.ChatDataCounts
+	for _, step := range r.steps() {
+		filter, err := step.filter(ctx, chatId)
+		if err != nil {
+			return nil, err
+		}
+		count, err := r.Collection(step.collection).CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("counting %s: %w", step.collection, err)
+		}
+		*step.counter(&counts) = int(count)
+	}
+	return &counts, nil
+}
+
+var _ repository.ChatPurgeRepository = (*ChatPurgeRepository)(nil)
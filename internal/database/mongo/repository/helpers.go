@@ -3,9 +3,12 @@ package repository
 import (
 	"github.com/Mateus-Lacerda/better-mem/pkg/core"
 	"github.com/Mateus-Lacerda/better-mem/internal/database/mongo"
+	"context"
 	"errors"
+	"strings"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	mongoDriver "go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -17,46 +20,31 @@ func (h *ShortTermMemoryHelper) SchemaToDbModel(
 	m *core.NewShortTermMemory,
 ) *mongo.ShortTermMemory {
 	return &mongo.ShortTermMemory{
-		Memory:      m.Memory,
-		ChatID:      m.ChatId,
-		AccessCount: m.AccessCount,
-		MergeCount:  m.MergeCount,
-		Merged:      m.Merged,
-		CreatedAt:   m.CreatedAt.String(),
-		Active:      m.Active,
+		Memory:         m.Memory,
+		ChatID:         m.ChatId,
+		AccessCount:    m.AccessCount,
+		MergeCount:     m.MergeCount,
+		Merged:         m.Merged,
+		CreatedAt:      m.CreatedAt.String(),
+		Active:         m.Active,
+		RelatedContext: relatedContextToDbModel(m.RelatedContext),
 	}
 }
 
-func (h *ShortTermMemoryHelper) GetMaxCounts(memories []core.ShortTermMemoryModel) (int, int) {
-	maxAccessCount := 0
-	maxMergeCount := 0
-	for _, memory := range memories {
-		if memory.AccessCount > maxAccessCount {
-			maxAccessCount = memory.AccessCount
-		}
-		if memory.MergeCount > maxMergeCount {
-			maxMergeCount = memory.MergeCount
-		}
+func (h *ShortTermMemoryHelper) DbModelToSchema(
+	m *mongo.ShortTermMemory,
+) *core.ShortTermMemory {
+	return &core.ShortTermMemory{
+		Id:             m.ID,
+		Memory:         m.Memory,
+		ChatId:         m.ChatID,
+		AccessCount:    m.AccessCount,
+		MergeCount:     m.MergeCount,
+		Merged:         m.Merged,
+		CreatedAt:      parseCreatedAt(m.CreatedAt),
+		Active:         m.Active,
+		RelatedContext: relatedContextToSchema(m.RelatedContext),
 	}
-	return maxAccessCount, maxMergeCount
-}
-
-func (h *ShortTermMemoryHelper) GetTemporalScore(now int64, createdAt time.Time) float64 {
-	return max(1, time.Since(createdAt).Seconds()/(60*60))
-}
-
-func (h *ShortTermMemoryHelper) CalculateScore(
-	memory core.ShortTermMemoryModel,
-	maxAccessCount int,
-	maxMergeCount int,
-	now int64,
-) (float32, error) {
-	relevancyScore :=
-		(memory.AccessCount + memory.MergeCount) / max((maxAccessCount)+(maxMergeCount), 1)
-
-	temporalScore := h.GetTemporalScore(now, memory.CreatedAt)
-	score := (float32(relevancyScore) + 1/float32(temporalScore)) / 2
-	return score, nil
 }
 
 type LongTermMemoryHelper struct{}
@@ -67,43 +55,66 @@ func (h *LongTermMemoryHelper) SchemaToDbModel(
 	m *core.NewLongTermMemory,
 ) *mongo.LongTermMemory {
 	return &mongo.LongTermMemory{
-		Memory:      m.Memory,
-		ChatID:      m.ChatId,
-		AccessCount: m.AccessCount,
-		CreatedAt:   m.CreatedAt.String(),
-		Active:      m.Active,
+		Memory:         m.Memory,
+		ChatID:         m.ChatId,
+		AccessCount:    m.AccessCount,
+		CreatedAt:      m.CreatedAt.String(),
+		Active:         m.Active,
+		RelatedContext: relatedContextToDbModel(m.RelatedContext),
 	}
 }
 
-func (h *LongTermMemoryHelper) GetMaxCounts(
-	memories []core.LongTermMemoryModel,
-) (int, int, error) {
-	maxAge := 0
-	maxAccessCount := 0
-	for _, memory := range memories {
-		age := time.Since(memory.CreatedAt).Seconds() / (60 * 60)
-		if int(age) > maxAge {
-			maxAge = int(age)
-		}
-		if memory.AccessCount > maxAccessCount {
-			maxAccessCount = memory.AccessCount
-		}
+func (h *LongTermMemoryHelper) DbModelToSchema(
+	m *mongo.LongTermMemory,
+) *core.LongTermMemory {
+	return &core.LongTermMemory{
+		Id:             m.ID,
+		Memory:         m.Memory,
+		ChatId:         m.ChatID,
+		AccessCount:    m.AccessCount,
+		CreatedAt:      parseCreatedAt(m.CreatedAt),
+		Active:         m.Active,
+		RelatedContext: relatedContextToSchema(m.RelatedContext),
+	}
+}
 
+func relatedContextToDbModel(
+	relatedContext []core.MessageRelatedContext,
+) []mongo.MessageRelatedContext {
+	dbRelatedContext := []mongo.MessageRelatedContext{}
+	for _, c := range relatedContext {
+		dbRelatedContext = append(
+			dbRelatedContext,
+			mongo.MessageRelatedContext{Context: c.Context, User: c.User},
+		)
 	}
-	return maxAge, maxAccessCount, nil
+	return dbRelatedContext
 }
 
-func (h *LongTermMemoryHelper) CalculateScore(
-	memory core.LongTermMemoryModel,
-	maxAge int,
-	maxAccessCount int,
-	now int64,
-) (float32, error) {
-	age := time.Since(memory.CreatedAt).Seconds() / (60 * 60)
-	relevancyScore := float32(memory.AccessCount) / float32(max(maxAccessCount, 1))
-	temporalScore := float32(age) / float32(max(maxAge, 1))
-	score := (relevancyScore + temporalScore) / 2
-	return score, nil
+func relatedContextToSchema(
+	relatedContext []mongo.MessageRelatedContext,
+) []core.MessageRelatedContext {
+	schemaRelatedContext := []core.MessageRelatedContext{}
+	for _, c := range relatedContext {
+		schemaRelatedContext = append(
+			schemaRelatedContext,
+			core.MessageRelatedContext{Context: c.Context, User: c.User},
+		)
+	}
+	return schemaRelatedContext
+}
+
+// The memories store their creation time as [time.Time.String], which
+// may end with a monotonic clock reading that can not be parsed back
+func parseCreatedAt(createdAt string) time.Time {
+	if i := strings.Index(createdAt, " m="); i >= 0 {
+		createdAt = createdAt[:i]
+	}
+	parsed, err := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", createdAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
 }
 
 func IsMongoDuplicateKeyError(err error) bool {
@@ -117,3 +128,13 @@ func IsMongoDuplicateKeyError(err error) bool {
 	}
 	return false
 }
+
+// tenantFilter matches the documents of the tenant carried by ctx,
+// documents stored before tenants existed belong to the default tenant
+func tenantFilter(ctx context.Context) any {
+	tenantId := core.TenantFromContext(ctx)
+	if tenantId == core.DefaultTenantId {
+		return bson.M{"$in": bson.A{tenantId, nil}}
+	}
+	return tenantId
+}
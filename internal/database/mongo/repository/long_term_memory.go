@@ -31,6 +31,7 @@ func NewLongTermMemoryRepository() *LongTermMemoryRepository {
 // Create implements [repository.LongTermMemoryRepository].
 func (l *LongTermMemoryRepository) Create(ctx context.Context, memory *core.NewLongTermMemory) (*core.LongTermMemory, error) {
 	dbMemory := l.helper.SchemaToDbModel(memory)
+	dbMemory.TenantID = core.TenantFromContext(ctx)
 
 	res, err := l.InsertOne(ctx, dbMemory)
 	if err != nil {
@@ -53,8 +54,8 @@ func (l *LongTermMemoryRepository) Deactivate(ctx context.Context, chatId string
 	if err != nil {
 		return err
 	}
-	filter := bson.M{"chatid": chatId, "_id": memoryIdObjectId}
-	update := bson.M{"$set": bson.M{"active": false}}
+	filter := bson.M{"tenant_id": tenantFilter(ctx), "chatid": chatId, "_id": memoryIdObjectId, "active": true}
+	update := bson.M{"$set": bson.M{"active": false, "deactivated_at": time.Now()}}
 	_, err = l.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
@@ -64,7 +65,7 @@ func (l *LongTermMemoryRepository) Deactivate(ctx context.Context, chatId string
 
 // GetByChatId implements [repository.LongTermMemoryRepository].
 func (l *LongTermMemoryRepository) GetByChatId(ctx context.Context, chatId string, limit int, offset int) (*core.LongTermMemoryArray, error) {
-	filter := bson.M{"chatid": chatId, "active": true}
+	filter := bson.M{"tenant_id": tenantFilter(ctx), "chatid": chatId, "active": true}
 	cursor, err := l.Find(
 		ctx,
 		filter,
@@ -89,10 +90,13 @@ func (l *LongTermMemoryRepository) GetByChatId(ctx context.Context, chatId strin
 func (l *LongTermMemoryRepository) GetById(ctx context.Context, chatId string, memoryId string) (*core.LongTermMemory, error) {
 	memoryIdObjectId, err := primitive.ObjectIDFromHex(memoryId)
 	if err != nil {
-		return nil, err
+		return nil, core.MemoryNotFound
 	}
-	filter := bson.M{"_id": memoryIdObjectId, "chatid": chatId}
+	filter := bson.M{"_id": memoryIdObjectId, "tenant_id": tenantFilter(ctx), "chatid": chatId}
 	result := l.FindOne(ctx, filter)
+	if result.Err() == mongoDriver.ErrNoDocuments {
+		return nil, core.MemoryNotFound
+	}
 	if result.Err() != nil {
 		return nil, result.Err()
 	}
@@ -104,15 +108,47 @@ func (l *LongTermMemoryRepository) GetById(ctx context.Context, chatId string, m
 	return &memory, nil
 }
 
-// GetScored implements [repository.LongTermMemoryRepository].
-func (l *LongTermMemoryRepository) GetScored(
+// Update implements [repository.LongTermMemoryRepository].
+func (l *LongTermMemoryRepository) Update(
+	ctx context.Context,
+	chatId string,
+	memoryId string,
+	memory string,
+	relatedContext []core.MessageRelatedContext,
+) (*core.LongTermMemory, error) {
+	memoryIdObjectId, err := primitive.ObjectIDFromHex(memoryId)
+	if err != nil {
+		return nil, core.MemoryNotFound
+	}
+	fields := bson.M{}
+	if memory != "" {
+		fields["memory"] = memory
+	}
+	if relatedContext != nil {
+		fields["related_context"] = relatedContextToDbModel(relatedContext)
+	}
+	if len(fields) > 0 {
+		filter := bson.M{"_id": memoryIdObjectId, "tenant_id": tenantFilter(ctx), "chatid": chatId}
+		result, err := l.UpdateOne(ctx, filter, bson.M{"$set": fields})
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			return nil, core.MemoryNotFound
+		}
+	}
+	return l.GetById(ctx, chatId, memoryId)
+}
+
+// GetByIds implements [repository.LongTermMemoryRepository].
+func (l *LongTermMemoryRepository) GetByIds(
 	ctx context.Context,
 	chatId string,
 	memoriesIds []string,
-) ([]*core.ScoredMemory, error) {
-	var scoredMemories []*core.ScoredMemory
+) ([]*core.LongTermMemory, error) {
+	var memories []*core.LongTermMemory
 	if len(memoriesIds) == 0 {
-		return scoredMemories, nil
+		return memories, nil
 	}
 	var objectIds []primitive.ObjectID
 	for _, id := range memoriesIds {
@@ -123,52 +159,21 @@ func (l *LongTermMemoryRepository) GetScored(
 		objectIds = append(objectIds, objectId)
 	}
 	filter := bson.M{
-		"chatid": chatId,
-		"_id":    bson.M{"$in": objectIds},
-		"active": true,
+		"tenant_id": tenantFilter(ctx),
+		"chatid":    chatId,
+		"_id":       bson.M{"$in": objectIds},
+		"active":    true,
 	}
 	cursor, err := l.Find(ctx, filter)
 	if err != nil {
 		slog.Error("failed to get memories", "error", err)
 		return nil, err
 	}
-	var rawMemories []core.LongTermMemoryModel
-	err = cursor.All(ctx, &rawMemories)
-	if err != nil {
+	if err := cursor.All(ctx, &memories); err != nil {
 		slog.Error("failed to get memories", "error", err)
 		return nil, err
 	}
-
-	maxAge, maxAccessCount, err := l.helper.GetMaxCounts(rawMemories)
-	if err != nil {
-		slog.Error("failed to get max counts", "error", err)
-		return nil, err
-	}
-	slog.Info("max age", "maxAge", maxAge, "maxAccessCount", maxAccessCount)
-	now := time.Now().Unix()
-	for _, memory := range rawMemories {
-		score, err := l.helper.CalculateScore(
-			memory,
-			maxAge,
-			maxAccessCount,
-			now,
-		)
-		if err != nil {
-			return nil, err
-		}
-		scoredMemories = append(
-			scoredMemories, &core.ScoredMemory{
-				Id:             memory.Id,
-				Text:           memory.Memory,
-				Score:          score,
-				MemoryType:     core.LongTerm,
-				CreatedAt:      memory.CreatedAt,
-				RelatedContext: memory.RelatedContext,
-			},
-		)
-	}
-
-	return scoredMemories, nil
+	return memories, nil
 }
 
 // RegisterUsage implements [repository.LongTermMemoryRepository].
@@ -182,7 +187,7 @@ func (l *LongTermMemoryRepository) RegisterUsage(ctx context.Context, chatId str
 	if err != nil {
 		return err
 	}
-	filter := bson.M{"_id": memoryIdObjectId}
+	filter := bson.M{"_id": memoryIdObjectId, "tenant_id": tenantFilter(ctx)}
 	update := bson.M{"$set": bson.M{"accesscount": memory.AccessCount}}
 	_, err = l.UpdateOne(ctx, filter, update)
 	return err
@@ -190,8 +195,8 @@ func (l *LongTermMemoryRepository) RegisterUsage(ctx context.Context, chatId str
 
 // DeactivateAll implements [repository.LongTermMemoryRepository].
 func (l *LongTermMemoryRepository) DeactivateAll(ctx context.Context, chatId string) error {
-	filter := bson.M{"chatid": chatId}
-	update := bson.M{"$set": bson.M{"active": false}}
+	filter := bson.M{"tenant_id": tenantFilter(ctx), "chatid": chatId, "active": true}
+	update := bson.M{"$set": bson.M{"active": false, "deactivated_at": time.Now()}}
 	_, err := l.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
@@ -199,4 +204,37 @@ func (l *LongTermMemoryRepository) DeactivateAll(ctx context.Context, chatId str
 	return nil
 }
 
+// CountActive implements [repository.LongTermMemoryRepository].
+func (l *LongTermMemoryRepository) CountActive(ctx context.Context) (int, error) {
+	count, err := l.CountDocuments(
+		ctx, bson.M{"tenant_id": tenantFilter(ctx), "active": true},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// GetAllByChatId implements [repository.LongTermMemoryRepository].
+// The ids grow with the insertion time, so they give the order
+func (l *LongTermMemoryRepository) GetAllByChatId(ctx context.Context, chatId string) ([]*core.LongTermMemory, error) {
+	cursor, err := l.Find(
+		ctx,
+		bson.M{"tenant_id": tenantFilter(ctx), "chat_id": chatId},
+		options.Find().SetSort(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var dbMemories []mongo.LongTermMemory
+	if err := cursor.All(ctx, &dbMemories); err != nil {
+		return nil, err
+	}
+	memories := make([]*core.LongTermMemory, 0, len(dbMemories))
+	for _, m := range dbMemories {
+		memories = append(memories, l.helper.DbModelToSchema(&m))
+	}
+	return memories, nil
+}
+
 var _ repository.LongTermMemoryRepository = (*LongTermMemoryRepository)(nil)
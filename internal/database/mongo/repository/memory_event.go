@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/mongo"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Id of the counter holding the last event sequence
+const memoryEventCounterId = "memory_events"
+
+type MemoryEventRepository struct {
+	*mongoDriver.Collection
+	counters *mongoDriver.Collection
+}
+
+func NewMemoryEventRepository() *MemoryEventRepository {
+	config := mongo.MemoryEventConfig()
+	database := mongo.GetMongoDatabase()
+	return &MemoryEventRepository{
+		Collection: database.Collection(config.CollectionName),
+		counters:   database.Collection(config.CountersCollectionName),
+	}
+}
+
+func memoryEventDbModelToSchema(e *mongo.MemoryEvent) *core.MemoryEvent {
+	return &core.MemoryEvent{
+		Id:           e.ID,
+		Cursor:       e.Seq,
+		Type:         core.MemoryEventType(e.Type),
+		TenantId:     e.TenantID,
+		MemoryId:     e.MemoryID,
+		MemoryType:   core.MemoryTypeEnum(e.MemoryType),
+		Memory:       e.Memory,
+		PromotedFrom: e.PromotedFrom,
+		OccurredAt:   e.OccurredAt,
+	}
+}
+
+// nextSeq atomically takes the next event sequence. An event may land
+// after one with a higher sequence when both are written at once, a
+// stream reading in between skips it
+func (r *MemoryEventRepository) nextSeq(ctx context.Context) (int64, error) {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := r.counters.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": memoryEventCounterId},
+		bson.M{"$inc": bson.M{"seq": 1}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	return counter.Seq, err
+}
+
+// Create implements [repository.MemoryEventRepository].
+func (r *MemoryEventRepository) Create(
+	ctx context.Context, chatId string, event *core.MemoryEvent,
+) error {
+	seq, err := r.nextSeq(ctx)
+	if err != nil {
+		return err
+	}
+	dbEvent := mongo.MemoryEvent{
+		ID:           event.Id,
+		Seq:          seq,
+		TenantID:     core.TenantFromContext(ctx),
+		ChatID:       chatId,
+		Type:         string(event.Type),
+		MemoryID:     event.MemoryId,
+		MemoryType:   int(event.MemoryType),
+		Memory:       event.Memory,
+		PromotedFrom: event.PromotedFrom,
+		OccurredAt:   event.OccurredAt,
+	}
+	if _, err := r.InsertOne(ctx, dbEvent); err != nil {
+		return err
+	}
+	event.Cursor = seq
+	return nil
+}
+
+// GetSince implements [repository.MemoryEventRepository].
+func (r *MemoryEventRepository) GetSince(
+	ctx context.Context, chatId string, cursor int64, limit int,
+) ([]*core.MemoryEvent, error) {
+	result, err := r.Find(
+		ctx,
+		bson.M{
+			"tenant_id": tenantFilter(ctx),
+			"chat_id":   chatId,
+			"seq":       bson.M{"$gt": cursor},
+		},
+		options.Find().SetSort(bson.D{{Key: "seq", Value: 1}}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var dbEvents []mongo.MemoryEvent
+	if err := result.All(ctx, &dbEvents); err != nil {
+		return nil, err
+	}
+	var events []*core.MemoryEvent
+	for _, e := range dbEvents {
+		events = append(events, memoryEventDbModelToSchema(&e))
+	}
+	return events, nil
+}
+
+// LastCursor implements [repository.MemoryEventRepository].
+func (r *MemoryEventRepository) LastCursor(ctx context.Context, chatId string) (int64, error) {
+	var dbEvent mongo.MemoryEvent
+	err := r.FindOne(
+		ctx,
+		bson.M{"tenant_id": tenantFilter(ctx), "chat_id": chatId},
+		options.FindOne().SetSort(bson.D{{Key: "seq", Value: -1}}),
+	).Decode(&dbEvent)
+	if err == mongoDriver.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return dbEvent.Seq, nil
+}
+
+var _ repository.MemoryEventRepository = (*MemoryEventRepository)(nil)
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/mongo"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MemoryKeywordRepository struct {
+	longTerm  *mongoDriver.Collection
+	shortTerm *mongoDriver.Collection
+}
+
+func NewMemoryKeywordRepository() *MemoryKeywordRepository {
+	database := mongo.GetMongoDatabase()
+	return &MemoryKeywordRepository{
+		longTerm:  database.Collection(mongo.LongTermMemoryConfig().CollectionName),
+		shortTerm: database.Collection(mongo.ShortTermMemoryConfig().CollectionName),
+	}
+}
+
+type keywordMatch struct {
+	ID    primitive.ObjectID `bson:"_id"`
+	Score float64            `bson:"score"`
+}
+
+func (r *MemoryKeywordRepository) search(
+	ctx context.Context,
+	collection *mongoDriver.Collection,
+	memoryType core.MemoryTypeEnum,
+	chatId string,
+	query string,
+	limit int,
+) ([]core.ScoredMemoryVector, error) {
+	score := bson.M{"score": bson.M{"$meta": "textScore"}}
+	cursor, err := collection.Find(
+		ctx,
+		bson.M{
+			"$text":     bson.M{"$search": query},
+			"tenant_id": tenantFilter(ctx),
+			"chat_id":   chatId,
+			"active":    true,
+		},
+		options.Find().
+			SetProjection(score).
+			SetSort(score).
+			SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var matches []keywordMatch
+	if err := cursor.All(ctx, &matches); err != nil {
+		return nil, err
+	}
+	var results []core.ScoredMemoryVector
+	for _, match := range matches {
+		results = append(results, core.ScoredMemoryVector{
+			Id:    match.ID.Hex(),
+			Score: float32(match.Score),
+			Payload: core.MemoryPayload{
+				TenantId:   core.TenantFromContext(ctx),
+				ChatId:     chatId,
+				MemoryType: memoryType,
+				MemoryId:   match.ID.Hex(),
+				Active:     true,
+			},
+		})
+	}
+	return results, nil
+}
+
+// Search implements [repository.MemoryKeywordRepository].
+func (r *MemoryKeywordRepository) Search(
+	ctx context.Context, chatId string, query string, limit int,
+) ([]core.ScoredMemoryVector, error) {
+	longTermResults, err := r.search(ctx, r.longTerm, core.LongTerm, chatId, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	shortTermResults, err := r.search(ctx, r.shortTerm, core.ShortTerm, chatId, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	results := append(longTermResults, shortTermResults...)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if limit > 0 && limit < len(results) {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+var _ repository.MemoryKeywordRepository = (*MemoryKeywordRepository)(nil)
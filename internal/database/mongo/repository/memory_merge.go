@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/mongo"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MemoryMergeRepository struct {
+	*mongoDriver.Collection
+}
+
+func NewMemoryMergeRepository() *MemoryMergeRepository {
+	collectionName := mongo.MemoryMergeConfig().CollectionName
+	database := mongo.GetMongoDatabase()
+	return &MemoryMergeRepository{
+		Collection: database.Collection(collectionName),
+	}
+}
+
+func memoryMergeDbModelToSchema(m *mongo.MemoryMerge) *core.MemoryMerge {
+	previousRelatedContext := []core.MessageRelatedContext{}
+	for _, c := range m.PreviousRelatedContext {
+		previousRelatedContext = append(
+			previousRelatedContext,
+			core.MessageRelatedContext{Context: c.Context, User: c.User},
+		)
+	}
+	return &core.MemoryMerge{
+		Id:                     m.ID,
+		MemoryId:               m.MemoryID,
+		PreviousMemory:         m.PreviousMemory,
+		PreviousRelatedContext: previousRelatedContext,
+		IncomingMemory:         m.IncomingMemory,
+		Score:                  m.Score,
+		CreatedAt:              m.CreatedAt,
+	}
+}
+
+// Create implements [repository.MemoryMergeRepository].
+func (r *MemoryMergeRepository) Create(
+	ctx context.Context, merge *core.MemoryMerge,
+) (*core.MemoryMerge, error) {
+	previousRelatedContext := []mongo.MessageRelatedContext{}
+	for _, c := range merge.PreviousRelatedContext {
+		previousRelatedContext = append(
+			previousRelatedContext,
+			mongo.MessageRelatedContext{Context: c.Context, User: c.User},
+		)
+	}
+	dbMerge := mongo.MemoryMerge{
+		ID:                     uuid.New().String(),
+		TenantID:               core.TenantFromContext(ctx),
+		MemoryID:               merge.MemoryId,
+		PreviousMemory:         merge.PreviousMemory,
+		PreviousRelatedContext: previousRelatedContext,
+		IncomingMemory:         merge.IncomingMemory,
+		Score:                  merge.Score,
+		CreatedAt:              merge.CreatedAt,
+	}
+	if _, err := r.InsertOne(ctx, dbMerge); err != nil {
+		return nil, err
+	}
+	return memoryMergeDbModelToSchema(&dbMerge), nil
+}
+
+// GetById implements [repository.MemoryMergeRepository].
+func (r *MemoryMergeRepository) GetById(
+	ctx context.Context, memoryId string, mergeId string,
+) (*core.MemoryMerge, error) {
+	result := r.FindOne(ctx, bson.M{
+		"_id":       mergeId,
+		"tenant_id": tenantFilter(ctx),
+		"memory_id": memoryId,
+	})
+	if result.Err() == mongoDriver.ErrNoDocuments {
+		return nil, core.MemoryMergeNotFound
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	var dbMerge mongo.MemoryMerge
+	if err := result.Decode(&dbMerge); err != nil {
+		return nil, err
+	}
+	return memoryMergeDbModelToSchema(&dbMerge), nil
+}
+
+// GetByMemoryId implements [repository.MemoryMergeRepository].
+func (r *MemoryMergeRepository) GetByMemoryId(
+	ctx context.Context, memoryId string,
+) ([]*core.MemoryMerge, error) {
+	cursor, err := r.Find(
+		ctx,
+		bson.M{"tenant_id": tenantFilter(ctx), "memory_id": memoryId},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var dbMerges []mongo.MemoryMerge
+	if err := cursor.All(ctx, &dbMerges); err != nil {
+		return nil, err
+	}
+	merges := []*core.MemoryMerge{}
+	for _, m := range dbMerges {
+		merges = append(merges, memoryMergeDbModelToSchema(&m))
+	}
+	return merges, nil
+}
+
+var _ repository.MemoryMergeRepository = (*MemoryMergeRepository)(nil)
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/mongo"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Related context is embedded in the documents here, it goes away with
+// them and can never be orphaned
+type MemoryRetentionRepository struct {
+	*mongoDriver.Database
+}
+
+func NewMemoryRetentionRepository() *MemoryRetentionRepository {
+	return &MemoryRetentionRepository{
+		Database: mongo.GetMongoDatabase(),
+	}
+}
+
+func (r *MemoryRetentionRepository) collectionOf(memoryType core.MemoryTypeEnum) (*mongoDriver.Collection, error) {
+	switch memoryType {
+	case core.ShortTerm:
+		return r.Collection(mongo.ShortTermMemoryConfig().CollectionName), nil
+	case core.LongTerm:
+		return r.Collection(mongo.LongTermMemoryConfig().CollectionName), nil
+	}
+	return nil, fmt.Errorf("no collection for memory type %d", memoryType)
+}
+
+// MarkDeactivated implements [repository.MemoryRetentionRepository].
+func (r *MemoryRetentionRepository) MarkDeactivated(ctx context.Context, at time.Time) (int, error) {
+	marked := 0
+	for _, memoryType := range []core.MemoryTypeEnum{core.ShortTerm, core.LongTerm} {
+		collection, err := r.collectionOf(memoryType)
+		if err != nil {
+			return marked, err
+		}
+		result, err := collection.UpdateMany(
+			ctx,
+			bson.M{
+				"tenant_id":      tenantFilter(ctx),
+				"active":         false,
+				"deactivated_at": bson.M{"$exists": false},
+			},
+			bson.M{"$set": bson.M{"deactivated_at": at}},
+		)
+		if err != nil {
+			return marked, err
+		}
+		marked += int(result.ModifiedCount)
+	}
+	return marked, nil
+}
+
+// GetExpired implements [repository.MemoryRetentionRepository].
+func (r *MemoryRetentionRepository) GetExpired(
+	ctx context.Context, memoryType core.MemoryTypeEnum, cutoff time.Time, limit int,
+) ([]string, error) {
+	collection, err := r.collectionOf(memoryType)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := collection.Find(
+		ctx,
+		bson.M{
+			"tenant_id":      tenantFilter(ctx),
+			"active":         false,
+			"deactivated_at": bson.M{"$lt": cutoff},
+		},
+		options.Find().
+			SetProjection(bson.M{"_id": 1}).
+			SetSort(bson.D{{Key: "deactivated_at", Value: 1}}).
+			SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var documents []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(documents))
+	for _, document := range documents {
+		ids = append(ids, document.ID.Hex())
+	}
+	return ids, nil
+}
+
+// Delete implements [repository.MemoryRetentionRepository].
+func (r *MemoryRetentionRepository) Delete(
+	ctx context.Context, memoryType core.MemoryTypeEnum, ids []string,
+) (*core.RetentionPurgeStats, error) {
+	collection, err := r.collectionOf(memoryType)
+	if err != nil {
+		return nil, err
+	}
+	objectIds := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objectId, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+		objectIds = append(objectIds, objectId)
+	}
+	var deleted core.RetentionPurgeStats
+	merges, err := r.Collection(mongo.MemoryMergeConfig().CollectionName).DeleteMany(
+		ctx, bson.M{"tenant_id": tenantFilter(ctx), "memory_id": bson.M{"$in": ids}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	deleted.MemoryMerges = int(merges.DeletedCount)
+	// Only the memories that are still inactive
+	memories, err := collection.DeleteMany(
+		ctx, bson.M{"tenant_id": tenantFilter(ctx), "active": false, "_id": bson.M{"$in": objectIds}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if memoryType == core.ShortTerm {
+		deleted.ShortTermMemories = int(memories.DeletedCount)
+	} else {
+		deleted.LongTermMemories = int(memories.DeletedCount)
+	}
+	return &deleted, nil
+}
+
+// DeleteOrphanedRelatedContext implements [repository.MemoryRetentionRepository].
+func (r *MemoryRetentionRepository) DeleteOrphanedRelatedContext(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+var _ repository.MemoryRetentionRepository = (*MemoryRetentionRepository)(nil)
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/mongo"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MessageRepository struct {
+	*mongoDriver.Collection
+}
+
+func NewMessageRepository() *MessageRepository {
+	collectionName := mongo.MessageConfig().CollectionName
+	database := mongo.GetMongoDatabase()
+	return &MessageRepository{
+		Collection: database.Collection(collectionName),
+	}
+}
+
+func messageDbModelToSchema(m *mongo.Message) *core.Message {
+	var relatedContext []core.MessageRelatedContext
+	for _, c := range m.RelatedContext {
+		relatedContext = append(
+			relatedContext,
+			core.MessageRelatedContext{Context: c.Context, User: c.User},
+		)
+	}
+	status := core.MessageTaskStatus(m.Status)
+	return &core.Message{
+		Id:             m.ID,
+		ChatId:         m.ChatID,
+		Message:        m.Message,
+		RelatedContext: relatedContext,
+		Status:         status,
+		Label:          core.MemoryTypeEnum(m.Label),
+		Merged:         status == core.MessageMerged,
+		MemoryId:       m.MemoryID,
+		Error:          m.Error,
+		Attempts:       m.Attempts,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+// Create implements [repository.MessageRepository].
+func (r *MessageRepository) Create(ctx context.Context, message *core.Message) error {
+	relatedContext := []mongo.MessageRelatedContext{}
+	for _, c := range message.RelatedContext {
+		relatedContext = append(
+			relatedContext,
+			mongo.MessageRelatedContext{Context: c.Context, User: c.User},
+		)
+	}
+	_, err := r.InsertOne(ctx, mongo.Message{
+		ID:             message.Id,
+		TenantID:       core.TenantFromContext(ctx),
+		ChatID:         message.ChatId,
+		Message:        message.Message,
+		RelatedContext: relatedContext,
+		Status:         string(message.Status),
+		CreatedAt:      message.CreatedAt,
+		UpdatedAt:      message.CreatedAt,
+	})
+	return err
+}
+
+// GetById implements [repository.MessageRepository].
+func (r *MessageRepository) GetById(ctx context.Context, id string) (*core.Message, error) {
+	result := r.FindOne(ctx, bson.M{"_id": id, "tenant_id": tenantFilter(ctx)})
+	if result.Err() == mongoDriver.ErrNoDocuments {
+		return nil, core.MessageNotFound
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	var dbMessage mongo.Message
+	if err := result.Decode(&dbMessage); err != nil {
+		return nil, err
+	}
+	return messageDbModelToSchema(&dbMessage), nil
+}
+
+// GetByChatId implements [repository.MessageRepository].
+func (r *MessageRepository) GetByChatId(
+	ctx context.Context, chatId string, limit int, offset int,
+) (*core.MessageArray, error) {
+	filter := bson.M{"tenant_id": tenantFilter(ctx), "chat_id": chatId}
+	total, err := r.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := r.Find(
+		ctx,
+		filter,
+		options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: 1}}).
+			SetSkip(int64(offset)).
+			SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var dbMessages []mongo.Message
+	if err := cursor.All(ctx, &dbMessages); err != nil {
+		return nil, err
+	}
+	messages := []*core.Message{}
+	for _, m := range dbMessages {
+		messages = append(messages, messageDbModelToSchema(&m))
+	}
+	return &core.MessageArray{
+		Messages: messages,
+		Total:    int(total),
+	}, nil
+}
+
+// GetByMemoryId implements [repository.MessageRepository].
+func (r *MessageRepository) GetByMemoryId(ctx context.Context, memoryId string) ([]*core.Message, error) {
+	cursor, err := r.Find(
+		ctx,
+		bson.M{"tenant_id": tenantFilter(ctx), "memory_id": memoryId},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var dbMessages []mongo.Message
+	if err := cursor.All(ctx, &dbMessages); err != nil {
+		return nil, err
+	}
+	messages := []*core.Message{}
+	for _, m := range dbMessages {
+		messages = append(messages, messageDbModelToSchema(&m))
+	}
+	return messages, nil
+}
+
+// ReassignMemory implements [repository.MessageRepository].
+func (r *MessageRepository) ReassignMemory(
+	ctx context.Context, memoryId string, newMemoryId string,
+) error {
+	_, err := r.UpdateMany(
+		ctx,
+		bson.M{"tenant_id": tenantFilter(ctx), "memory_id": memoryId},
+		bson.M{"$set": bson.M{"memory_id": newMemoryId}},
+	)
+	return err
+}
+
+// StartAttempt implements [repository.MessageRepository].
+func (r *MessageRepository) StartAttempt(ctx context.Context, id string) error {
+	updateResult, err := r.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "tenant_id": tenantFilter(ctx)},
+		bson.M{
+			"$set": bson.M{
+				"status":     string(core.MessageClassifying),
+				"error":      "",
+				"updated_at": time.Now(),
+			},
+			"$inc": bson.M{"attempts": 1},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if updateResult.MatchedCount == 0 {
+		return core.MessageNotFound
+	}
+	return nil
+}
+
+// SetResult implements [repository.MessageRepository].
+func (r *MessageRepository) SetResult(
+	ctx context.Context, id string, result *core.MessageTaskResult,
+) error {
+	updateResult, err := r.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "tenant_id": tenantFilter(ctx)},
+		bson.M{"$set": bson.M{
+			"status":     string(result.Status),
+			"label":      int(result.Label),
+			"memory_id":  result.MemoryId,
+			"error":      result.Error,
+			"updated_at": time.Now(),
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if updateResult.MatchedCount == 0 {
+		return core.MessageNotFound
+	}
+	return nil
+}
+
+var _ repository.MessageRepository = (*MessageRepository)(nil)
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/mongo"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RetentionRunRepository struct {
+	*mongoDriver.Collection
+}
+
+func NewRetentionRunRepository() *RetentionRunRepository {
+	collectionName := mongo.RetentionRunConfig().CollectionName
+	database := mongo.GetMongoDatabase()
+	return &RetentionRunRepository{
+		Collection: database.Collection(collectionName),
+	}
+}
+
+func retentionRunDbModelToSchema(r *mongo.RetentionRun) *core.RetentionRun {
+	return &core.RetentionRun{
+		Id:            r.ID,
+		RetentionDays: r.RetentionDays,
+		Cutoff:        r.Cutoff,
+		Tenants:       r.Tenants,
+		Deleted: core.RetentionPurgeStats{
+			ShortTermMemories:       r.ShortTermMemories,
+			LongTermMemories:        r.LongTermMemories,
+			Vectors:                 r.Vectors,
+			MemoryMerges:            r.MemoryMerges,
+			OrphanedRelatedContexts: r.OrphanedRelatedContexts,
+		},
+		Error:       r.Error,
+		StartedAt:   r.StartedAt,
+		CompletedAt: r.CompletedAt,
+	}
+}
+
+// Create implements [repository.RetentionRunRepository].
+func (r *RetentionRunRepository) Create(ctx context.Context, run *core.RetentionRun) error {
+	dbRun := mongo.RetentionRun{
+		ID:                      uuid.New().String(),
+		RetentionDays:           run.RetentionDays,
+		Cutoff:                  run.Cutoff,
+		Tenants:                 run.Tenants,
+		ShortTermMemories:       run.Deleted.ShortTermMemories,
+		LongTermMemories:        run.Deleted.LongTermMemories,
+		Vectors:                 run.Deleted.Vectors,
+		MemoryMerges:            run.Deleted.MemoryMerges,
+		OrphanedRelatedContexts: run.Deleted.OrphanedRelatedContexts,
+		Error:                   run.Error,
+		StartedAt:               run.StartedAt,
+		CompletedAt:             run.CompletedAt,
+	}
+	if _, err := r.InsertOne(ctx, dbRun); err != nil {
+		return err
+	}
+	run.Id = dbRun.ID
+	return nil
+}
+
+// GetAll implements [repository.RetentionRunRepository].
+func (r *RetentionRunRepository) GetAll(ctx context.Context, limit int, offset int) ([]*core.RetentionRun, error) {
+	cursor, err := r.Find(
+		ctx,
+		bson.M{},
+		options.Find().
+			SetSort(bson.D{{Key: "started_at", Value: -1}}).
+			SetLimit(int64(limit)).
+			SetSkip(int64(offset)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var dbRuns []mongo.RetentionRun
+	if err := cursor.All(ctx, &dbRuns); err != nil {
+		return nil, err
+	}
+	runs := []*core.RetentionRun{}
+	for _, run := range dbRuns {
+		runs = append(runs, retentionRunDbModelToSchema(&run))
+	}
+	return runs, nil
+}
+
+var _ repository.RetentionRunRepository = (*RetentionRunRepository)(nil)
@@ -31,6 +31,7 @@ func NewShortTermMemoryRepository() ShortTermMemoryRepository {
 // Create implements [repository.ShortTermMemoryRepository].
 func (s ShortTermMemoryRepository) Create(ctx context.Context, memory *core.NewShortTermMemory) (*core.ShortTermMemory, error) {
 	dbMemory := s.helper.SchemaToDbModel(memory)
+	dbMemory.TenantID = core.TenantFromContext(ctx)
 
 	res, err := s.InsertOne(ctx, dbMemory)
 	if err != nil {
@@ -55,15 +56,15 @@ func (s ShortTermMemoryRepository) Deactivate(ctx context.Context, chatId string
 	if err != nil {
 		return err
 	}
-	filter := bson.M{"_id": memoryIdObjectId, "chatid": chatId}
-	update := bson.M{"$set": bson.M{"active": false}}
+	filter := bson.M{"_id": memoryIdObjectId, "tenant_id": tenantFilter(ctx), "chatid": chatId, "active": true}
+	update := bson.M{"$set": bson.M{"active": false, "deactivated_at": time.Now()}}
 	_, err = s.UpdateOne(ctx, filter, update)
 	return err
 }
 
 // GetByChatId implements [repository.ShortTermMemoryRepository].
 func (s ShortTermMemoryRepository) GetByChatId(ctx context.Context, chatId string, limit int, offset int) (*core.ShortTermMemoryArray, error) {
-	filter := bson.M{"chatid": chatId, "active": true}
+	filter := bson.M{"tenant_id": tenantFilter(ctx), "chatid": chatId, "active": true}
 	cursor, err := s.Find(
 		ctx,
 		filter,
@@ -88,10 +89,13 @@ func (s ShortTermMemoryRepository) GetByChatId(ctx context.Context, chatId strin
 func (s ShortTermMemoryRepository) GetById(ctx context.Context, chatId string, memoryId string) (*core.ShortTermMemory, error) {
 	memoryIdObjectId, err := primitive.ObjectIDFromHex(memoryId)
 	if err != nil {
-		return nil, err
+		return nil, core.MemoryNotFound
 	}
-	filter := bson.M{"_id": memoryIdObjectId, "chatid": chatId}
+	filter := bson.M{"_id": memoryIdObjectId, "tenant_id": tenantFilter(ctx), "chatid": chatId}
 	result := s.FindOne(ctx, filter)
+	if result.Err() == mongoDriver.ErrNoDocuments {
+		return nil, core.MemoryNotFound
+	}
 	if result.Err() != nil {
 		return nil, result.Err()
 	}
@@ -103,13 +107,45 @@ func (s ShortTermMemoryRepository) GetById(ctx context.Context, chatId string, m
 	return &memory, nil
 }
 
-// GetScored implements [repository.ShortTermMemoryRepository].
-func (s ShortTermMemoryRepository) GetScored(
+// Update implements [repository.ShortTermMemoryRepository].
+func (s ShortTermMemoryRepository) Update(
+	ctx context.Context,
+	chatId string,
+	memoryId string,
+	memory string,
+	relatedContext []core.MessageRelatedContext,
+) (*core.ShortTermMemory, error) {
+	memoryIdObjectId, err := primitive.ObjectIDFromHex(memoryId)
+	if err != nil {
+		return nil, core.MemoryNotFound
+	}
+	fields := bson.M{}
+	if memory != "" {
+		fields["memory"] = memory
+	}
+	if relatedContext != nil {
+		fields["related_context"] = relatedContextToDbModel(relatedContext)
+	}
+	if len(fields) > 0 {
+		filter := bson.M{"_id": memoryIdObjectId, "tenant_id": tenantFilter(ctx), "chatid": chatId}
+		result, err := s.UpdateOne(ctx, filter, bson.M{"$set": fields})
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			return nil, core.MemoryNotFound
+		}
+	}
+	return s.GetById(ctx, chatId, memoryId)
+}
+
+// GetByIds implements [repository.ShortTermMemoryRepository].
+func (s ShortTermMemoryRepository) GetByIds(
 	ctx context.Context,
 	chatId string,
 	memoriesIds []string,
-) ([]*core.ScoredMemory, error) {
-	var memories []*core.ScoredMemory
+) ([]*core.ShortTermMemory, error) {
+	var memories []*core.ShortTermMemory
 	if len(memoriesIds) == 0 {
 		return memories, nil
 	}
@@ -121,40 +157,21 @@ func (s ShortTermMemoryRepository) GetScored(
 		}
 		objectIds = append(objectIds, objectId)
 	}
-
 	filter := bson.M{
-		"chatid": chatId,
-		"_id":    bson.M{"$in": objectIds},
-		"active": true,
+		"tenant_id": tenantFilter(ctx),
+		"chatid":    chatId,
+		"_id":       bson.M{"$in": objectIds},
+		"active":    true,
 	}
 	cursor, err := s.Find(ctx, filter)
 	if err != nil {
+		slog.Error("failed to get memories", "error", err)
 		return nil, err
 	}
-	var rawMemories []core.ShortTermMemoryModel
-	err = cursor.All(ctx, &rawMemories)
-	if err != nil {
+	if err := cursor.All(ctx, &memories); err != nil {
+		slog.Error("failed to get memories", "error", err)
 		return nil, err
 	}
-
-	maxAccessCount, maxMergeCount := s.helper.GetMaxCounts(rawMemories)
-	now := time.Now().Unix()
-	for _, memory := range rawMemories {
-		score, err := s.helper.CalculateScore(
-			memory, maxAccessCount, maxMergeCount, now,
-		)
-		if err != nil {
-			return nil, err
-		}
-		memories = append(memories, &core.ScoredMemory{
-			Id:             memory.Id,
-			Score:          score,
-			Text:           memory.Memory,
-			MemoryType:     core.ShortTerm,
-			CreatedAt:      memory.CreatedAt,
-			RelatedContext: memory.RelatedContext,
-		})
-	}
 	return memories, nil
 }
 
@@ -167,7 +184,7 @@ func (s ShortTermMemoryRepository) Merge(
 	otherMemoryRelatedContext []core.MessageRelatedContext,
 ) (*core.ShortTermMemory, error) {
 	// We will just use the newest memory text, and increment the merge count
-	// TODO: Store merges in a separate collection for data analysis
+	// The previous text is kept by the merge history
 	oldMemory, err := s.GetById(ctx, chatId, memoryId)
 	if err != nil {
 		return nil, err
@@ -176,8 +193,12 @@ func (s ShortTermMemoryRepository) Merge(
 	oldMemory.RelatedContext = otherMemoryRelatedContext
 	oldMemory.MergeCount++
 	memoryIdObjectId, err := primitive.ObjectIDFromHex(memoryId)
-	filter := bson.M{"_id": memoryIdObjectId}
-	update := bson.M{"$set": oldMemory}
+	filter := bson.M{"_id": memoryIdObjectId, "tenant_id": tenantFilter(ctx)}
+	update := bson.M{"$set": bson.M{
+		"memory":          oldMemory.Memory,
+		"related_context": relatedContextToDbModel(oldMemory.RelatedContext),
+		"merge_count":     oldMemory.MergeCount,
+	}}
 	_, err = s.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return nil, err
@@ -197,7 +218,7 @@ func (s ShortTermMemoryRepository) RegisterUsage(ctx context.Context, chatId str
 	if err != nil {
 		return err
 	}
-	filter := bson.M{"_id": memoryIdObjectId}
+	filter := bson.M{"_id": memoryIdObjectId, "tenant_id": tenantFilter(ctx)}
 	update := bson.M{"$set": bson.M{"accesscount": memory.AccessCount}}
 	_, err = s.UpdateOne(ctx, filter, update)
 	return err
@@ -211,8 +232,9 @@ func (s ShortTermMemoryRepository) GetElligibleForDeactivation(
 	minimalRelevance int,
 ) ([]*core.ShortTermMemory, error) {
 	filter := bson.M{
-		"chatid": chatId,
-		"active": true,
+		"tenant_id": tenantFilter(ctx),
+		"chatid":    chatId,
+		"active":    true,
 		"createdat": bson.M{
 			"$lt": time.Now().Add(-window),
 		},
@@ -240,8 +262,9 @@ func (s ShortTermMemoryRepository) GetElligibleForPromotion(
 	ctx context.Context, chatId string, minimalRelevance int,
 ) ([]*core.ShortTermMemory, error) {
 	filter := bson.M{
-		"chatid": chatId,
-		"active": true,
+		"tenant_id": tenantFilter(ctx),
+		"chatid":    chatId,
+		"active":    true,
 		"$expr": bson.M{
 			"$gte": []any{
 				bson.M{"$add": []any{"$accesscount", "$mergecount"}},
@@ -263,8 +286,8 @@ func (s ShortTermMemoryRepository) GetElligibleForPromotion(
 
 // DeactivateAll implements [repository.ShortTermMemoryRepository].
 func (s ShortTermMemoryRepository) DeactivateAll(ctx context.Context, chatId string) error {
-	filter := bson.M{"chatid": chatId}
-	update := bson.M{"$set": bson.M{"active": false}}
+	filter := bson.M{"tenant_id": tenantFilter(ctx), "chatid": chatId, "active": true}
+	update := bson.M{"$set": bson.M{"active": false, "deactivated_at": time.Now()}}
 	_, err := s.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
@@ -272,4 +295,37 @@ func (s ShortTermMemoryRepository) DeactivateAll(ctx context.Context, chatId str
 	return nil
 }
 
+// CountActive implements [repository.ShortTermMemoryRepository].
+func (s ShortTermMemoryRepository) CountActive(ctx context.Context) (int, error) {
+	count, err := s.CountDocuments(
+		ctx, bson.M{"tenant_id": tenantFilter(ctx), "active": true},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// GetAllByChatId implements [repository.ShortTermMemoryRepository].
+// The ids grow with the insertion time, so they give the order
+func (s ShortTermMemoryRepository) GetAllByChatId(ctx context.Context, chatId string) ([]*core.ShortTermMemory, error) {
+	cursor, err := s.Find(
+		ctx,
+		bson.M{"tenant_id": tenantFilter(ctx), "chat_id": chatId},
+		options.Find().SetSort(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var dbMemories []mongo.ShortTermMemory
+	if err := cursor.All(ctx, &dbMemories); err != nil {
+		return nil, err
+	}
+	memories := make([]*core.ShortTermMemory, 0, len(dbMemories))
+	for _, m := range dbMemories {
+		memories = append(memories, s.helper.DbModelToSchema(&m))
+	}
+	return memories, nil
+}
+
 var _ repository.ShortTermMemoryRepository = (*ShortTermMemoryRepository)(nil)
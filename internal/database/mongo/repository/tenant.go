@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/mongo"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TenantRepository struct {
+	*mongoDriver.Collection
+}
+
+func NewTenantRepository() *TenantRepository {
+	collectionName := mongo.TenantConfig().CollectionName
+	database := mongo.GetMongoDatabase()
+	return &TenantRepository{
+		Collection: database.Collection(collectionName),
+	}
+}
+
+func tenantDbModelToSchema(t *mongo.Tenant) *core.Tenant {
+	return &core.Tenant{
+		Id:   t.ID,
+		Name: t.Name,
+		Quota: core.TenantQuota{
+			MaxChats:    t.MaxChats,
+			MaxMemories: t.MaxMemories,
+		},
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+// Create implements [repository.TenantRepository].
+func (r *TenantRepository) Create(ctx context.Context, tenant *core.Tenant) (*core.Tenant, error) {
+	dbTenant := mongo.Tenant{
+		ID:          tenant.Id,
+		Name:        tenant.Name,
+		MaxChats:    tenant.Quota.MaxChats,
+		MaxMemories: tenant.Quota.MaxMemories,
+		CreatedAt:   tenant.CreatedAt,
+	}
+	_, err := r.InsertOne(ctx, dbTenant)
+	if IsMongoDuplicateKeyError(err) {
+		return nil, core.TenantAlreadyExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tenantDbModelToSchema(&dbTenant), nil
+}
+
+// GetAll implements [repository.TenantRepository].
+func (r *TenantRepository) GetAll(ctx context.Context) ([]*core.Tenant, error) {
+	cursor, err := r.Find(
+		ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var dbTenants []*mongo.Tenant
+	if err := cursor.All(ctx, &dbTenants); err != nil {
+		return nil, err
+	}
+	var tenants []*core.Tenant
+	for _, t := range dbTenants {
+		tenants = append(tenants, tenantDbModelToSchema(t))
+	}
+	return tenants, nil
+}
+
+// GetById implements [repository.TenantRepository].
+func (r *TenantRepository) GetById(ctx context.Context, id string) (*core.Tenant, error) {
+	result := r.FindOne(ctx, bson.M{"_id": id})
+	if result.Err() == mongoDriver.ErrNoDocuments {
+		return nil, core.TenantNotFound
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	var dbTenant mongo.Tenant
+	if err := result.Decode(&dbTenant); err != nil {
+		return nil, err
+	}
+	return tenantDbModelToSchema(&dbTenant), nil
+}
+
+// UpdateQuota implements [repository.TenantRepository].
+func (r *TenantRepository) UpdateQuota(
+	ctx context.Context, id string, quota core.TenantQuota,
+) (*core.Tenant, error) {
+	result, err := r.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{
+			"max_chats":    quota.MaxChats,
+			"max_memories": quota.MaxMemories,
+		}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, core.TenantNotFound
+	}
+	return r.GetById(ctx, id)
+}
+
+var _ repository.TenantRepository = (*TenantRepository)(nil)
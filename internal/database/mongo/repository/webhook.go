@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/mongo"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhookRepository struct {
+	*mongoDriver.Collection
+}
+
+func NewWebhookRepository() *WebhookRepository {
+	collectionName := mongo.WebhookConfig().CollectionName
+	database := mongo.GetMongoDatabase()
+	return &WebhookRepository{
+		Collection: database.Collection(collectionName),
+	}
+}
+
+func webhookDbModelToSchema(w *mongo.Webhook) *core.Webhook {
+	events := []core.MemoryEventType{}
+	for _, event := range w.Events {
+		events = append(events, core.MemoryEventType(event))
+	}
+	return &core.Webhook{
+		Id:        w.ID,
+		TenantId:  w.TenantID,
+		ChatId:    w.ChatID,
+		Url:       w.Url,
+		Events:    events,
+		CreatedAt: w.CreatedAt,
+		Secret:    w.Secret,
+	}
+}
+
+// find returns the webhooks of the tenant matching the filter
+func (r *WebhookRepository) find(ctx context.Context, filter bson.M) ([]*core.Webhook, error) {
+	filter["tenant_id"] = tenantFilter(ctx)
+	cursor, err := r.Find(
+		ctx,
+		filter,
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var dbWebhooks []mongo.Webhook
+	if err := cursor.All(ctx, &dbWebhooks); err != nil {
+		return nil, err
+	}
+	var webhooks []*core.Webhook
+	for _, w := range dbWebhooks {
+		webhooks = append(webhooks, webhookDbModelToSchema(&w))
+	}
+	return webhooks, nil
+}
+
+// Create implements [repository.WebhookRepository].
+func (r *WebhookRepository) Create(ctx context.Context, webhook *core.Webhook) (*core.Webhook, error) {
+	events := []string{}
+	for _, event := range webhook.Events {
+		events = append(events, string(event))
+	}
+	dbWebhook := mongo.Webhook{
+		ID:        uuid.New().String(),
+		TenantID:  core.TenantFromContext(ctx),
+		ChatID:    webhook.ChatId,
+		Url:       webhook.Url,
+		Secret:    webhook.Secret,
+		Events:    events,
+		CreatedAt: webhook.CreatedAt,
+	}
+	if _, err := r.InsertOne(ctx, dbWebhook); err != nil {
+		return nil, err
+	}
+	return webhookDbModelToSchema(&dbWebhook), nil
+}
+
+// GetAll implements [repository.WebhookRepository].
+func (r *WebhookRepository) GetAll(ctx context.Context) ([]*core.Webhook, error) {
+	return r.find(ctx, bson.M{})
+}
+
+// GetById implements [repository.WebhookRepository].
+func (r *WebhookRepository) GetById(ctx context.Context, id string) (*core.Webhook, error) {
+	result := r.FindOne(ctx, bson.M{"_id": id, "tenant_id": tenantFilter(ctx)})
+	if result.Err() == mongoDriver.ErrNoDocuments {
+		return nil, core.WebhookNotFound
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	var dbWebhook mongo.Webhook
+	if err := result.Decode(&dbWebhook); err != nil {
+		return nil, err
+	}
+	return webhookDbModelToSchema(&dbWebhook), nil
+}
+
+// GetByChatId implements [repository.WebhookRepository].
+func (r *WebhookRepository) GetByChatId(ctx context.Context, chatId string) ([]*core.Webhook, error) {
+	return r.find(ctx, bson.M{"chat_id": bson.M{"$in": bson.A{chatId, ""}}})
+}
+
+// Delete implements [repository.WebhookRepository].
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.DeleteOne(ctx, bson.M{"_id": id, "tenant_id": tenantFilter(ctx)})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return core.WebhookNotFound
+	}
+	return nil
+}
+
+var _ repository.WebhookRepository = (*WebhookRepository)(nil)
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/mongo"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhookDeliveryRepository struct {
+	*mongoDriver.Collection
+}
+
+func NewWebhookDeliveryRepository() *WebhookDeliveryRepository {
+	collectionName := mongo.WebhookDeliveryConfig().CollectionName
+	database := mongo.GetMongoDatabase()
+	return &WebhookDeliveryRepository{
+		Collection: database.Collection(collectionName),
+	}
+}
+
+func webhookDeliveryDbModelToSchema(d *mongo.WebhookDelivery) *core.WebhookDelivery {
+	return &core.WebhookDelivery{
+		Id:         d.ID,
+		WebhookId:  d.WebhookID,
+		EventId:    d.EventID,
+		EventType:  core.MemoryEventType(d.EventType),
+		Attempt:    d.Attempt,
+		StatusCode: d.StatusCode,
+		Success:    d.Success,
+		Error:      d.Error,
+		Duration:   d.Duration,
+		CreatedAt:  d.CreatedAt,
+	}
+}
+
+// Create implements [repository.WebhookDeliveryRepository].
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *core.WebhookDelivery) error {
+	dbDelivery := mongo.WebhookDelivery{
+		ID:         uuid.New().String(),
+		TenantID:   core.TenantFromContext(ctx),
+		WebhookID:  delivery.WebhookId,
+		EventID:    delivery.EventId,
+		EventType:  string(delivery.EventType),
+		Attempt:    delivery.Attempt,
+		StatusCode: delivery.StatusCode,
+		Success:    delivery.Success,
+		Error:      delivery.Error,
+		Duration:   delivery.Duration,
+		CreatedAt:  delivery.CreatedAt,
+	}
+	if _, err := r.InsertOne(ctx, dbDelivery); err != nil {
+		return err
+	}
+	delivery.Id = dbDelivery.ID
+	return nil
+}
+
+// GetByWebhookId implements [repository.WebhookDeliveryRepository].
+func (r *WebhookDeliveryRepository) GetByWebhookId(
+	ctx context.Context, webhookId string, limit int, offset int,
+) ([]*core.WebhookDelivery, error) {
+	cursor, err := r.Find(
+		ctx,
+		bson.M{"tenant_id": tenantFilter(ctx), "webhook_id": webhookId},
+		options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: -1}}).
+			SetLimit(int64(limit)).
+			SetSkip(int64(offset)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var dbDeliveries []mongo.WebhookDelivery
+	if err := cursor.All(ctx, &dbDeliveries); err != nil {
+		return nil, err
+	}
+	deliveries := []*core.WebhookDelivery{}
+	for _, d := range dbDeliveries {
+		deliveries = append(deliveries, webhookDeliveryDbModelToSchema(&d))
+	}
+	return deliveries, nil
+}
+
+var _ repository.WebhookDeliveryRepository = (*WebhookDeliveryRepository)(nil)
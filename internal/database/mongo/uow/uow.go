@@ -16,7 +16,7 @@ type MongoUnitOfWork[T any] struct {
 
 // Do implements uow.UnitOfWork.
 func (m *MongoUnitOfWork[T]) Do(
-	ctx context.Context, fn func(repos repository.AllRepositories) (T, error),
+	ctx context.Context, fn func(ctx context.Context, repos repository.AllRepositories) (T, error),
 ) (T, error) {
 	transactionResult := *new(T)
 	sess, err := m.Database.Client().StartSession()
@@ -31,7 +31,9 @@ func (m *MongoUnitOfWork[T]) Do(
 	result, err := sess.WithTransaction(
 		ctx,
 		func(sessCtx mongoClient.SessionContext) (any, error) {
-			return fn(m.Repositories(sessCtx))
+			// The session travels in sessCtx, only the calls made with it
+			// are part of the transaction
+			return fn(sessCtx, m.Repositories(sessCtx))
 		},
 	)
 	if err != nil {
@@ -46,6 +48,8 @@ func (m *MongoUnitOfWork[T]) Repositories(_ any) repository.AllRepositories {
 		Chat:            mongoRepository.NewChatRepository(),
 		ShortTermMemory: mongoRepository.NewShortTermMemoryRepository(),
 		LongTermMemory:  mongoRepository.NewLongTermMemoryRepository(),
+		Message:         mongoRepository.NewMessageRepository(),
+		MemoryMerge:     mongoRepository.NewMemoryMergeRepository(),
 	}
 }
 
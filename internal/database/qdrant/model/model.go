@@ -3,6 +3,7 @@ package model
 import "github.com/Mateus-Lacerda/better-mem/pkg/core"
 
 type MemoryPayload struct {
+	TenantId   string              `json:"tenant_id"`
 	MemoryType core.MemoryTypeEnum `json:"memory_type"`
 	MemoryId   string              `json:"memory_id"`
 	Active     bool                `json:"active"`
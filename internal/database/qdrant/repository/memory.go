@@ -12,6 +12,21 @@ import (
 	qdrantClient "github.com/qdrant/go-client/qdrant"
 )
 
+// tenantCondition matches the points of the tenant carried by ctx,
+// points stored before tenants existed belong to the default tenant
+func tenantCondition(ctx context.Context) *qdrantClient.Condition {
+	tenantId := core.TenantFromContext(ctx)
+	if tenantId != core.DefaultTenantId {
+		return qdrantClient.NewMatchKeyword("tenant_id", tenantId)
+	}
+	return qdrantClient.NewFilterAsCondition(&qdrantClient.Filter{
+		Should: []*qdrantClient.Condition{
+			qdrantClient.NewMatchKeyword("tenant_id", tenantId),
+			qdrantClient.NewIsEmpty("tenant_id"),
+		},
+	})
+}
+
 type MemoryRepository struct {
 	*qdrant.QdrantClient
 }
@@ -31,6 +46,7 @@ func (m *MemoryRepository) Create(
 	memoryId string,
 ) error {
 	payload := core.MemoryPayload{
+		TenantId:   core.TenantFromContext(ctx),
 		ChatId:     chatId,
 		MemoryType: memoryType,
 		Active:     true,
@@ -61,6 +77,45 @@ func (m *MemoryRepository) Create(
 	return err
 }
 
+// Update implements [vector.MemoryVectorRepository].
+func (m *MemoryRepository) Update(
+	ctx context.Context,
+	chatId string,
+	vectors []float32,
+	memoryId string,
+) error {
+	filter := qdrantClient.Filter{
+		Must: []*qdrantClient.Condition{
+			qdrantClient.NewMatchKeyword("memory_id", memoryId),
+			qdrantClient.NewMatchKeyword("chat_id", chatId),
+			tenantCondition(ctx),
+		},
+	}
+	points, err := m.Client.Scroll(ctx, &qdrantClient.ScrollPoints{
+		CollectionName: config.Database.DefaultCollectionName,
+		Filter:         &filter,
+	})
+	if err != nil {
+		return err
+	}
+	var pointVectors []*qdrantClient.PointVectors
+	for _, point := range points {
+		pointVectors = append(pointVectors, &qdrantClient.PointVectors{
+			Id:      point.GetId(),
+			Vectors: qdrantClient.NewVectors(vectors...),
+		})
+	}
+	if len(pointVectors) == 0 {
+		return core.MemoryNotFound
+	}
+	request := qdrantClient.UpdatePointVectors{
+		CollectionName: config.Database.DefaultCollectionName,
+		Points:         pointVectors,
+	}
+	_, err = m.Client.UpdateVectors(ctx, &request)
+	return err
+}
+
 // Search implements [vector.MemoryVectorRepository].
 func (m *MemoryRepository) Search(
 	ctx context.Context,
@@ -72,7 +127,8 @@ func (m *MemoryRepository) Search(
 	filter := qdrantClient.Filter{
 		Must: []*qdrantClient.Condition{
 			qdrantClient.NewMatchBool("active", true),
-			qdrantClient.NewMatchText("chat_id", chatId),
+			qdrantClient.NewMatchKeyword("chat_id", chatId),
+			tenantCondition(ctx),
 		},
 	}
 	query := qdrantClient.NewQuery(vector...)
@@ -84,6 +140,10 @@ func (m *MemoryRepository) Search(
 		WithVectors:    qdrantClient.NewWithVectors(true),
 		WithPayload:    qdrantClient.NewWithPayload(true),
 	}
+	// Left out, qdrant applies its own default
+	if limit > 0 {
+		request.Limit = qdrantClient.PtrOf(uint64(limit))
+	}
 	result, err := m.Client.Query(
 		ctx, &request,
 	)
@@ -112,6 +172,7 @@ func (m *MemoryRepository) Search(
 			Vectors: vectors.Data,
 			Score:   score,
 			Payload: core.MemoryPayload{
+				TenantId:   core.TenantFromContext(ctx),
 				ChatId:     chatId,
 				MemoryType: memoryType,
 				MemoryId:   memoryId,
@@ -127,9 +188,10 @@ func (m *MemoryRepository) Search(
 func (m *MemoryRepository) Deactivate(ctx context.Context, chatId string, id string) error {
 	filter := qdrantClient.Filter{
 		Must: []*qdrantClient.Condition{
-			qdrantClient.NewMatchText("memory_id", id),
+			qdrantClient.NewMatchKeyword("memory_id", id),
 			qdrantClient.NewMatchBool("active", true),
-			qdrantClient.NewMatchText("chat_id", chatId),
+			qdrantClient.NewMatchKeyword("chat_id", chatId),
+			tenantCondition(ctx),
 		},
 	}
 	request := qdrantClient.SetPayloadPoints{
@@ -148,7 +210,8 @@ func (m *MemoryRepository) DeactivateAll(ctx context.Context, chatId string) err
 	filter := qdrantClient.Filter{
 		Must: []*qdrantClient.Condition{
 			qdrantClient.NewMatchBool("active", true),
-			qdrantClient.NewMatchText("chat_id", chatId),
+			qdrantClient.NewMatchKeyword("chat_id", chatId),
+			tenantCondition(ctx),
 		},
 	}
 	request := qdrantClient.SetPayloadPoints{
@@ -162,4 +225,109 @@ func (m *MemoryRepository) DeactivateAll(ctx context.Context, chatId string) err
 	return err
 }
 
+// Every point of the chat, active or not. Matches the keyword exactly,
+// a text match would also catch chats whose id contains this one
+func chatFilter(ctx context.Context, chatId string) *qdrantClient.Filter {
+	return &qdrantClient.Filter{
+		Must: []*qdrantClient.Condition{
+			qdrantClient.NewMatchKeyword("chat_id", chatId),
+			tenantCondition(ctx),
+		},
+	}
+}
+
+// DeleteAll implements [vector.MemoryVectorRepository].
+func (m *MemoryRepository) DeleteAll(ctx context.Context, chatId string) (int, error) {
+	count, err := m.Count(ctx, chatId)
+	if err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	_, err = m.Client.Delete(ctx, &qdrantClient.DeletePoints{
+		CollectionName: config.Database.DefaultCollectionName,
+		Points:         qdrantClient.NewPointsSelectorFilter(chatFilter(ctx, chatId)),
+		Wait:           qdrantClient.PtrOf(true),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Count implements [vector.MemoryVectorRepository].
+func (m *MemoryRepository) Count(ctx context.Context, chatId string) (int, error) {
+	count, err := m.Client.Count(ctx, &qdrantClient.CountPoints{
+		CollectionName: config.Database.DefaultCollectionName,
+		Filter:         chatFilter(ctx, chatId),
+		Exact:          qdrantClient.PtrOf(true),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// DeleteByMemoryIds implements [vector.MemoryVectorRepository].
+func (m *MemoryRepository) DeleteByMemoryIds(ctx context.Context, memoryIds []string) (int, error) {
+	if len(memoryIds) == 0 {
+		return 0, nil
+	}
+	filter := qdrantClient.Filter{
+		Must: []*qdrantClient.Condition{
+			qdrantClient.NewMatchKeywords("memory_id", memoryIds...),
+			tenantCondition(ctx),
+		},
+	}
+	count, err := m.Client.Count(ctx, &qdrantClient.CountPoints{
+		CollectionName: config.Database.DefaultCollectionName,
+		Filter:         &filter,
+		Exact:          qdrantClient.PtrOf(true),
+	})
+	if err != nil || count == 0 {
+		return 0, err
+	}
+	_, err = m.Client.Delete(ctx, &qdrantClient.DeletePoints{
+		CollectionName: config.Database.DefaultCollectionName,
+		Points:         qdrantClient.NewPointsSelectorFilter(&filter),
+		Wait:           qdrantClient.PtrOf(true),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// GetByMemoryIds implements [vector.MemoryVectorRepository].
+func (m *MemoryRepository) GetByMemoryIds(ctx context.Context, memoryIds []string) (map[string][]float32, error) {
+	vectors := make(map[string][]float32, len(memoryIds))
+	if len(memoryIds) == 0 {
+		return vectors, nil
+	}
+	points, err := m.Client.Scroll(ctx, &qdrantClient.ScrollPoints{
+		CollectionName: config.Database.DefaultCollectionName,
+		Filter: &qdrantClient.Filter{
+			Must: []*qdrantClient.Condition{
+				qdrantClient.NewMatchKeywords("memory_id", memoryIds...),
+				tenantCondition(ctx),
+			},
+		},
+		Limit:       qdrantClient.PtrOf(uint32(len(memoryIds))),
+		WithVectors: qdrantClient.NewWithVectors(true),
+		WithPayload: qdrantClient.NewWithPayload(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, point := range points {
+		pointVectors := point.GetVectors().GetVector()
+		if pointVectors == nil || len(pointVectors.Data) == 0 {
+			continue
+		}
+		vectors[point.GetPayload()["memory_id"].GetStringValue()] = pointVectors.Data
+	}
+	return vectors, nil
+}
+
 var _ vector.MemoryVectorRepository = (*MemoryRepository)(nil)
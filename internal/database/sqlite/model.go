@@ -11,7 +11,8 @@ import (
 
 type Chat struct {
 	ID         string `gorm:"primaryKey"`
-	ExternalID string `gorm:"uniqueIndex;not null"`
+	TenantID   string `gorm:"uniqueIndex:idx_chats_tenant_external_id;not null;default:'default'"`
+	ExternalID string `gorm:"uniqueIndex:idx_chats_tenant_external_id;not null"`
 	// Has Many
 	LongTermMemories  []LongTermMemory  `gorm:"foreignKey:ChatID;constraint:OnDelete:CASCADE"`
 	ShortTermMemories []ShortTermMemory `gorm:"foreignKey:ChatID;constraint:OnDelete:CASCADE"`
@@ -27,11 +28,14 @@ func (c *Chat) BeforeCreate(tx *gorm.DB) (err error) {
 type LongTermMemory struct {
 	ID             string                  `gorm:"primaryKey"`
 	Memory         string                  `gorm:"type:text;not null"`
+	TenantID       string                  `gorm:"index;not null;default:'default'"`
 	ChatID         string                  `gorm:"index:idx_ltm_query"`
 	AccessCount    int                     `gorm:"default:0"`
 	CreatedAt      time.Time               `gorm:"index:idx_ltm_query,priority:2;sort:desc"`
 	Active         bool                    `gorm:"default:true"`
-	RelatedContext []RelatedContextContent `gorm:"many2many:related_content"`
+	DeactivatedAt  *time.Time              `gorm:"index"`
+	// Can not share the short term join table, it is keyed by the owner column
+	RelatedContext []RelatedContextContent `gorm:"many2many:long_term_related_content"`
 }
 
 func (c *LongTermMemory) BeforeCreate(tx *gorm.DB) (err error) {
@@ -44,12 +48,14 @@ func (c *LongTermMemory) BeforeCreate(tx *gorm.DB) (err error) {
 type ShortTermMemory struct {
 	ID             string                  `gorm:"primaryKey"`
 	Memory         string                  `gorm:"type:text;not null"`
+	TenantID       string                  `gorm:"index;not null;default:'default'"`
 	ChatID         string                  `gorm:"index:idx_stm_query"`
 	AccessCount    int                     `gorm:"default:0"`
 	MergeCount     int                     `gorm:"default:0"`
 	Merged         bool                    `gorm:"default:false"`
 	CreatedAt      time.Time               `gorm:"index:idx_stm_query,priority:2;sort:desc"`
 	Active         bool                    `gorm:"default:true"`
+	DeactivatedAt  *time.Time              `gorm:"index"`
 	RelatedContext []RelatedContextContent `gorm:"many2many:related_content"`
 }
 
@@ -73,16 +79,168 @@ func (c *RelatedContextContent) BeforeCreate(tx *gorm.DB) (err error) {
 	return
 }
 
+type ApiKey struct {
+	ID        string `gorm:"primaryKey"`
+	Name      string `gorm:"type:text;not null"`
+	Prefix    string `gorm:"type:text;not null"`
+	Hash      string `gorm:"uniqueIndex;not null"`
+	Scopes    string `gorm:"type:text;not null"`
+	TenantID  string `gorm:"not null;default:'default'"`
+	CreatedAt time.Time
+	Revoked   bool `gorm:"default:false"`
+}
+
+func (c *ApiKey) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return
+}
+
+type Tenant struct {
+	ID          string `gorm:"primaryKey"`
+	Name        string `gorm:"type:text"`
+	MaxChats    int    `gorm:"default:0"`
+	MaxMemories int    `gorm:"default:0"`
+	CreatedAt   time.Time
+}
+
+type Message struct {
+	ID             string                  `gorm:"primaryKey"`
+	TenantID       string                  `gorm:"index;not null;default:'default'"`
+	ChatID         string                  `gorm:"index:idx_messages_query"`
+	Message        string                  `gorm:"type:text;not null"`
+	RelatedContext []RelatedContextContent `gorm:"many2many:message_related_content"`
+	Status         string                  `gorm:"type:text;not null"`
+	Label          int                     `gorm:"default:0"`
+	MemoryID       string                  `gorm:"type:text"`
+	Error          string                  `gorm:"type:text"`
+	Attempts       int                     `gorm:"default:0"`
+	CreatedAt      time.Time               `gorm:"index:idx_messages_query,priority:2"`
+	UpdatedAt      time.Time
+}
+
+type MemoryMerge struct {
+	ID                     string                  `gorm:"primaryKey"`
+	TenantID               string                  `gorm:"index;not null;default:'default'"`
+	MemoryID               string                  `gorm:"index"`
+	PreviousMemory         string                  `gorm:"type:text;not null"`
+	PreviousRelatedContext []RelatedContextContent `gorm:"many2many:memory_merge_related_content"`
+	IncomingMemory         string                  `gorm:"type:text;not null"`
+	Score                  float32
+	CreatedAt              time.Time
+}
+
+func (c *MemoryMerge) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return
+}
+
+type Webhook struct {
+	ID        string `gorm:"primaryKey"`
+	TenantID  string `gorm:"index:idx_webhooks_query;not null;default:'default'"`
+	ChatID    string `gorm:"index:idx_webhooks_query,priority:2"`
+	Url       string `gorm:"type:text;not null"`
+	Secret    string `gorm:"type:text;not null"`
+	Events    string `gorm:"type:text"`
+	CreatedAt time.Time
+}
+
+func (c *Webhook) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return
+}
+
+type WebhookDelivery struct {
+	ID         string `gorm:"primaryKey"`
+	TenantID   string `gorm:"not null;default:'default'"`
+	WebhookID  string `gorm:"index:idx_webhook_deliveries_query"`
+	EventID    string `gorm:"type:text;not null"`
+	EventType  string `gorm:"type:text;not null"`
+	Attempt    int
+	StatusCode int
+	Success    bool
+	Error      string `gorm:"type:text"`
+	Duration   int64
+	CreatedAt  time.Time `gorm:"index:idx_webhook_deliveries_query,priority:2"`
+}
+
+func (c *WebhookDelivery) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return
+}
+
+type MemoryEvent struct {
+	// Autoincrement, used as the cursor of the event streams
+	Seq          int64  `gorm:"primaryKey;autoIncrement"`
+	ID           string `gorm:"uniqueIndex;not null"`
+	TenantID     string `gorm:"index:idx_memory_events_query;not null;default:'default'"`
+	ChatID       string `gorm:"index:idx_memory_events_query,priority:2"`
+	Type         string `gorm:"type:text;not null"`
+	MemoryID     string `gorm:"type:text"`
+	MemoryType   int
+	Memory       string `gorm:"type:text"`
+	PromotedFrom string `gorm:"type:text"`
+	OccurredAt   time.Time
+}
+
+type RetentionRun struct {
+	ID                      string `gorm:"primaryKey"`
+	RetentionDays           int
+	Cutoff                  time.Time
+	Tenants                 int
+	ShortTermMemories       int
+	LongTermMemories        int
+	Vectors                 int
+	MemoryMerges            int
+	OrphanedRelatedContexts int
+	Error                   string    `gorm:"type:text"`
+	StartedAt               time.Time `gorm:"index"`
+	CompletedAt             time.Time
+}
+
+func (c *RetentionRun) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return
+}
+
 func Migrate(db *gorm.DB) error {
 	if err := db.AutoMigrate(
 		&Chat{},
 		&LongTermMemory{},
 		&ShortTermMemory{},
 		&RelatedContextContent{},
+		&ApiKey{},
+		&Tenant{},
+		&Message{},
+		&MemoryMerge{},
+		&Webhook{},
+		&WebhookDelivery{},
+		&MemoryEvent{},
+		&RetentionRun{},
 	); err != nil {
 		return err
 	}
 
+	// External ids used to be unique globally, now they are unique per tenant
+	if db.Migrator().HasIndex(&Chat{}, "idx_chats_external_id") {
+		if err := db.Migrator().DropIndex(&Chat{}, "idx_chats_external_id"); err != nil {
+			return err
+		}
+	}
+
+	if err := migrateKeywordIndexes(db); err != nil {
+		return err
+	}
+
 	return db.Exec(
 		fmt.Sprintf(`
 			CREATE VIRTUAL TABLE IF NOT EXISTS vec_memories USING vec0(
@@ -95,3 +253,40 @@ func Migrate(db *gorm.DB) error {
 	).Error
 
 }
+
+// Full text indexes over the memory text, FTS4 because FTS5 needs a
+// build tag on go-sqlite3. They point to the memory tables and are
+// kept in sync by triggers, so every writer is covered
+func migrateKeywordIndexes(db *gorm.DB) error {
+	for _, table := range []string{"long_term_memories", "short_term_memories"} {
+		ftsTable := "fts_" + table
+		exists := db.Migrator().HasTable(ftsTable)
+		statements := []string{
+			`CREATE VIRTUAL TABLE IF NOT EXISTS %[2]s USING fts4(
+				content="%[1]s", memory, tokenize=unicode61
+			)`,
+			`CREATE TRIGGER IF NOT EXISTS %[2]s_bu BEFORE UPDATE OF memory ON %[1]s BEGIN
+				DELETE FROM %[2]s WHERE docid = old.rowid;
+			END`,
+			`CREATE TRIGGER IF NOT EXISTS %[2]s_bd BEFORE DELETE ON %[1]s BEGIN
+				DELETE FROM %[2]s WHERE docid = old.rowid;
+			END`,
+			`CREATE TRIGGER IF NOT EXISTS %[2]s_au AFTER UPDATE OF memory ON %[1]s BEGIN
+				INSERT INTO %[2]s(docid, memory) VALUES (new.rowid, new.memory);
+			END`,
+			`CREATE TRIGGER IF NOT EXISTS %[2]s_ai AFTER INSERT ON %[1]s BEGIN
+				INSERT INTO %[2]s(docid, memory) VALUES (new.rowid, new.memory);
+			END`,
+		}
+		// Memories stored before the index existed
+		if !exists {
+			statements = append(statements, `INSERT INTO %[2]s(%[2]s) VALUES ('rebuild')`)
+		}
+		for _, statement := range statements {
+			if err := db.Exec(fmt.Sprintf(statement, table, ftsTable)).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/sqlite"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type ApiKeyRepository struct {
+	*gorm.DB
+}
+
+func NewApiKeyRepository() *ApiKeyRepository {
+	db := sqlite.GetDb()
+	return &ApiKeyRepository{
+		DB: db,
+	}
+}
+
+func (r *ApiKeyRepository) G() gorm.Interface[sqlite.ApiKey] {
+	return gorm.G[sqlite.ApiKey](r.DB)
+}
+
+func apiKeyDbModelToSchema(k *sqlite.ApiKey) *core.ApiKey {
+	var scopes []core.ApiKeyScope
+	for _, scope := range strings.Split(k.Scopes, ",") {
+		if scope != "" {
+			scopes = append(scopes, core.ApiKeyScope(scope))
+		}
+	}
+	return &core.ApiKey{
+		Id:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    scopes,
+		TenantId:  k.TenantID,
+		CreatedAt: k.CreatedAt,
+		Revoked:   k.Revoked,
+	}
+}
+
+// Create implements [repository.ApiKeyRepository]
+func (r *ApiKeyRepository) Create(ctx context.Context, apiKey *core.ApiKeyModel) (*core.ApiKey, error) {
+	var scopes []string
+	for _, scope := range apiKey.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	dbApiKey := sqlite.ApiKey{
+		Name:      apiKey.Name,
+		Prefix:    apiKey.Prefix,
+		Hash:      apiKey.Hash,
+		Scopes:    strings.Join(scopes, ","),
+		TenantID:  apiKey.TenantId,
+		CreatedAt: apiKey.CreatedAt,
+	}
+	if err := r.G().Create(ctx, &dbApiKey); err != nil {
+		return nil, err
+	}
+	return apiKeyDbModelToSchema(&dbApiKey), nil
+}
+
+// GetAll implements [repository.ApiKeyRepository]
+func (r *ApiKeyRepository) GetAll(ctx context.Context) ([]*core.ApiKey, error) {
+	dbApiKeys, err := r.G().Order("created_at").Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var apiKeys []*core.ApiKey
+	for _, k := range dbApiKeys {
+		apiKeys = append(apiKeys, apiKeyDbModelToSchema(&k))
+	}
+	return apiKeys, nil
+}
+
+// GetByHash implements [repository.ApiKeyRepository]
+func (r *ApiKeyRepository) GetByHash(ctx context.Context, hash string) (*core.ApiKey, error) {
+	dbApiKey, err := r.G().Where("hash = ? AND NOT revoked", hash).First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, core.InvalidApiKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	return apiKeyDbModelToSchema(&dbApiKey), nil
+}
+
+// Revoke implements [repository.ApiKeyRepository]
+func (r *ApiKeyRepository) Revoke(ctx context.Context, id string) error {
+	rowsAffected, err := r.G().Where("id = ?", id).Update(ctx, "revoked", true)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return core.ApiKeyNotFound
+	}
+	return nil
+}
+
+var _ repository.ApiKeyRepository = (*ApiKeyRepository)(nil)
@@ -33,9 +33,17 @@ func (r *ChatRepository) G() gorm.Interface[sqlite.Chat] {
 	return gorm.G[sqlite.Chat](r.DB)
 }
 
+// scoped restricts the query to the tenant carried by ctx
+func (r *ChatRepository) scoped(ctx context.Context) gorm.ChainInterface[sqlite.Chat] {
+	return r.G().Where("tenant_id = ?", core.TenantFromContext(ctx))
+}
+
 // Create implements repository.ChatRepository.
 func (r *ChatRepository) Create(ctx context.Context, chat *core.NewChat) error {
-	dbChat := sqlite.Chat{ExternalID: chat.ExternalId}
+	dbChat := sqlite.Chat{
+		TenantID:   core.TenantFromContext(ctx),
+		ExternalID: chat.ExternalId,
+	}
 	err := r.G().Create(ctx, &dbChat)
 	if errors.Is(err, gorm.ErrDuplicatedKey) {
 		return core.ChatExternalIdAlreadyExists
@@ -43,25 +51,41 @@ func (r *ChatRepository) Create(ctx context.Context, chat *core.NewChat) error {
 	if err != nil {
 		return err
 	}
-	slog.Info("Chat created", "id", dbChat.ID)
+	slog.Info("Chat created", "id", dbChat.ID, "tenant_id", dbChat.TenantID)
 	return nil
 }
 
 // GetByExternalID implements repository.ChatRepository.
 func (r *ChatRepository) GetByExternalID(ctx context.Context, externalID string) (*string, error) {
-	dbChat, err := r.G().Where("external_id = ?", externalID).First(ctx)
+	dbChat, err := r.scoped(ctx).Where("external_id = ?", externalID).First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, core.ChatNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
+	return &dbChat.ID, nil
+}
+
+// GetById implements repository.ChatRepository.
+func (r *ChatRepository) GetById(ctx context.Context, id string) (*core.Chat, error) {
+	dbChat, err := r.scoped(ctx).Where("id = ?", id).First(ctx)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, core.ChatNotFound
 	}
-	return &dbChat.ID, nil
+	if err != nil {
+		return nil, err
+	}
+	return &core.Chat{
+		ExternalId: dbChat.ExternalID,
+		ID:         dbChat.ID,
+		TenantId:   dbChat.TenantID,
+	}, nil
 }
 
 // GetAll implements repository.ChatRepository.
 func (r *ChatRepository) GetAll(ctx context.Context) ([]*core.Chat, error) {
-	dbChats, err := r.G().Find(ctx)
+	dbChats, err := r.scoped(ctx).Find(ctx)
 	if err != nil {
 		slog.Error("Error getting all chats", "error", err)
 		return nil, err
@@ -71,9 +95,19 @@ func (r *ChatRepository) GetAll(ctx context.Context) ([]*core.Chat, error) {
 		chats = append(chats, &core.Chat{
 			ExternalId: chat.ExternalID,
 			ID:         chat.ID,
+			TenantId:   chat.TenantID,
 		})
 	}
 	return chats, nil
 }
 
+// Count implements repository.ChatRepository.
+func (r *ChatRepository) Count(ctx context.Context) (int, error) {
+	count, err := r.scoped(ctx).Count(ctx, "*")
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
 var _ repository.ChatRepository = (*ChatRepository)(nil)
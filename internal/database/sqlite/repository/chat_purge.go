@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/sqlite"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Ids of the rows owned by the chat, @tenant and @chat are bound on use
+const (
+	chatShortTermMemoryIds = "SELECT id FROM short_term_memories WHERE tenant_id = @tenant AND chat_id = @chat"
+	chatLongTermMemoryIds  = "SELECT id FROM long_term_memories WHERE tenant_id = @tenant AND chat_id = @chat"
+	chatMessageIds         = "SELECT id FROM messages WHERE tenant_id = @tenant AND chat_id = @chat"
+	chatWebhookIds         = "SELECT id FROM webhooks WHERE tenant_id = @tenant AND chat_id = @chat"
+	chatMemoryMergeIds     = "SELECT id FROM memory_merges WHERE tenant_id = @tenant AND memory_id IN (" +
+		chatShortTermMemoryIds + " UNION ALL " + chatLongTermMemoryIds + ")"
+	chatRelatedContextIds = "SELECT related_context_content_id FROM related_content WHERE short_term_memory_id IN (" + chatShortTermMemoryIds + ")" +
+		" UNION SELECT related_context_content_id FROM long_term_related_content WHERE long_term_memory_id IN (" + chatLongTermMemoryIds + ")" +
+		" UNION SELECT related_context_content_id FROM message_related_content WHERE message_id IN (" + chatMessageIds + ")" +
+		" UNION SELECT related_context_content_id FROM memory_merge_related_content WHERE memory_merge_id IN (" + chatMemoryMergeIds + ")"
+)
+
+// A set of rows of the chat, the steps are in deletion order since
+// the later ones are what the earlier ones are found through
+type chatPurgeStep struct {
+	table string
+	where string
+	// Where the rows are counted, nil for the join tables
+	counter func(counts *core.ChatDataCounts) *int
+}
+
+var chatPurgeSteps = []chatPurgeStep{
+	{
+		table:   "related_context_contents",
+		where:   "id IN (" + chatRelatedContextIds + ")",
+		counter: func(c *core.ChatDataCounts) *int { return &c.RelatedContexts },
+	},
+	{table: "related_content", where: "short_term_memory_id IN (" + chatShortTermMemoryIds + ")"},
+	{table: "long_term_related_content", where: "long_term_memory_id IN (" + chatLongTermMemoryIds + ")"},
+	{table: "message_related_content", where: "message_id IN (" + chatMessageIds + ")"},
+	{table: "memory_merge_related_content", where: "memory_merge_id IN (" + chatMemoryMergeIds + ")"},
+	{
+		table:   "memory_merges",
+		where:   "id IN (" + chatMemoryMergeIds + ")",
+		counter: func(c *core.ChatDataCounts) *int { return &c.MemoryMerges },
+	},
+	{
+		table:   "short_term_memories",
+		where:   "tenant_id = @tenant AND chat_id = @chat",
+		counter: func(c *core.ChatDataCounts) *int { return &c.ShortTermMemories },
+	},
+	{
+		table:   "long_term_memories",
+		where:   "tenant_id = @tenant AND chat_id = @chat",
+		counter: func(c *core.ChatDataCounts) *int { return &c.LongTermMemories },
+	},
+	{
+		table:   "messages",
+		where:   "tenant_id = @tenant AND chat_id = @chat",
+		counter: func(c *core.ChatDataCounts) *int { return &c.Messages },
+	},
+	{
+		table:   "memory_events",
+		where:   "tenant_id = @tenant AND chat_id = @chat",
+		counter: func(c *core.ChatDataCounts) *int { return &c.MemoryEvents },
+	},
+	{
+		table:   "webhook_deliveries",
+		where:   "webhook_id IN (" + chatWebhookIds + ")",
+		counter: func(c *core.ChatDataCounts) *int { return &c.WebhookDeliveries },
+	},
+	{
+		table:   "webhooks",
+		where:   "tenant_id = @tenant AND chat_id = @chat",
+		counter: func(c *core.ChatDataCounts) *int { return &c.Webhooks },
+	},
+	{
+		table:   "chats",
+		where:   "tenant_id = @tenant AND id = @chat",
+		counter: func(c *core.ChatDataCounts) *int { return &c.Chats },
+	},
+}
+
+type ChatPurgeRepository struct {
+	*gorm.DB
+}
+
+func NewChatPurgeRepository() *ChatPurgeRepository {
+	db := sqlite.GetDb()
+	return &ChatPurgeRepository{
+		DB: db,
+	}
+}
+
+func chatPurgeArgs(ctx context.Context, chatId string) map[string]any {
+	return map[string]any{
+		"tenant": core.TenantFromContext(ctx),
+		"chat":   chatId,
+	}
+}
+
+// Purge implements [repository.ChatPurgeRepository]
+func (r *ChatPurgeRepository) Purge(ctx context.Context, chatId string) (*core.ChatDataCounts, error) {
+	args := chatPurgeArgs(ctx, chatId)
+	var deleted core.ChatDataCounts
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, step := range chatPurgeSteps {
+			result := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s", step.table, step.where), args)
+			if result.Error != nil {
+				return fmt.Errorf("purging %s: %w", step.table, result.Error)
+			}
+			if step.counter != nil {
+				*step.counter(&deleted) = int(result.RowsAffected)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &deleted, nil
+}
+
+// Count implements [repository.ChatPurgeRepository]
+func (r *ChatPurgeRepository) Count(ctx context.Context, chatId string) (*core.ChatDataCounts, error) {
+	args := chatPurgeArgs(ctx, chatId)
+	var counts core.ChatDataCounts
+	for _, step := range chatPurgeSteps {
+		if step.counter == nil {
+			continue
+		}
+		var count int64
+		err := r.DB.WithContext(ctx).
+			Raw(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", step.table, step.where), args).
+			Scan(&count).Error
+		if err != nil {
+			return nil, fmt.Errorf("counting %s: %w", step.table, err)
+		}
+		*step.counter(&counts) = int(count)
+	}
+	return &counts, nil
+}
+
+var _ repository.ChatPurgeRepository = (*ChatPurgeRepository)(nil)
@@ -4,6 +4,8 @@ import (
 	"github.com/Mateus-Lacerda/better-mem/pkg/core"
 	sqlite "github.com/Mateus-Lacerda/better-mem/internal/database/sqlite"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type ShortTermMemoryHelper struct{}
@@ -14,46 +16,15 @@ func (h *ShortTermMemoryHelper) SchemaToDbModel(
 	m *core.NewShortTermMemory,
 ) *sqlite.ShortTermMemory {
 	return &sqlite.ShortTermMemory{
-		Memory:      m.Memory,
-		ChatID:      m.ChatId,
-		AccessCount: m.AccessCount,
-		MergeCount:  m.MergeCount,
-		Merged:      m.Merged,
-		CreatedAt:   m.CreatedAt,
-		Active:      m.Active,
-	}
-}
-
-func (h *ShortTermMemoryHelper) GetMaxCounts(memories []core.ShortTermMemory) (int, int) {
-	maxAccessCount := 0
-	maxMergeCount := 0
-	for _, memory := range memories {
-		if memory.AccessCount > maxAccessCount {
-			maxAccessCount = memory.AccessCount
-		}
-		if memory.MergeCount > maxMergeCount {
-			maxMergeCount = memory.MergeCount
-		}
+		Memory:         m.Memory,
+		ChatID:         m.ChatId,
+		AccessCount:    m.AccessCount,
+		MergeCount:     m.MergeCount,
+		Merged:         m.Merged,
+		CreatedAt:      m.CreatedAt,
+		Active:         m.Active,
+		RelatedContext: RelatedContextToDbModel(m.RelatedContext),
 	}
-	return maxAccessCount, maxMergeCount
-}
-
-func (h *ShortTermMemoryHelper) GetTemporalScore(now int64, createdAt time.Time) float64 {
-	return max(1, time.Since(createdAt).Seconds()/(60*60))
-}
-
-func (h *ShortTermMemoryHelper) CalculateScore(
-	memory core.ShortTermMemory,
-	maxAccessCount int,
-	maxMergeCount int,
-	now int64,
-) (float32, error) {
-	relevancyScore :=
-		(memory.AccessCount + memory.MergeCount) / max((maxAccessCount)+(maxMergeCount), 1)
-
-	temporalScore := h.GetTemporalScore(now, memory.CreatedAt)
-	score := (float32(relevancyScore) + 1/float32(temporalScore)) / 2
-	return score, nil
 }
 
 func (h *ShortTermMemoryHelper) DbModelToSchema(
@@ -88,43 +59,13 @@ func (h *LongTermMemoryHelper) SchemaToDbModel(
 	m *core.NewLongTermMemory,
 ) *sqlite.LongTermMemory {
 	return &sqlite.LongTermMemory{
-		Memory:      m.Memory,
-		ChatID:      m.ChatId,
-		AccessCount: m.AccessCount,
-		CreatedAt:   m.CreatedAt,
-		Active:      m.Active,
-	}
-}
-
-func (h *LongTermMemoryHelper) GetMaxCounts(
-	memories []core.LongTermMemory,
-) (int, int, error) {
-	maxAge := 0
-	maxAccessCount := 0
-	for _, memory := range memories {
-		age := time.Since(memory.CreatedAt).Seconds() / (60 * 60)
-		if int(age) > maxAge {
-			maxAge = int(age)
-		}
-		if memory.AccessCount > maxAccessCount {
-			maxAccessCount = memory.AccessCount
-		}
-
+		Memory:         m.Memory,
+		ChatID:         m.ChatId,
+		AccessCount:    m.AccessCount,
+		CreatedAt:      m.CreatedAt,
+		Active:         m.Active,
+		RelatedContext: RelatedContextToDbModel(m.RelatedContext),
 	}
-	return maxAge, maxAccessCount, nil
-}
-
-func (h *LongTermMemoryHelper) CalculateScore(
-	memory core.LongTermMemory,
-	maxAge int,
-	maxAccessCount int,
-	now int64,
-) (float32, error) {
-	age := time.Since(memory.CreatedAt).Seconds() / (60 * 60)
-	relevancyScore := float32(memory.AccessCount) / float32(max(maxAccessCount, 1))
-	temporalScore := float32(age) / float32(max(maxAge, 1))
-	score := (relevancyScore + temporalScore) / 2
-	return score, nil
 }
 
 func (h *LongTermMemoryHelper) DbModelToSchema(
@@ -148,3 +89,25 @@ func (h *LongTermMemoryHelper) DbModelToSchema(
 	}
 	return memory
 }
+
+func RelatedContextToDbModel(
+	relatedContext []core.MessageRelatedContext,
+) []sqlite.RelatedContextContent {
+	var dbRelatedContext []sqlite.RelatedContextContent
+	for _, c := range relatedContext {
+		dbRelatedContext = append(
+			dbRelatedContext,
+			sqlite.RelatedContextContent{Context: c.Context, User: c.User},
+		)
+	}
+	return dbRelatedContext
+}
+
+// deactivation sets a memory inactive, keeping the first deactivation
+// time since the retention period counts from it
+func deactivation() map[string]any {
+	return map[string]any{
+		"active":         false,
+		"deactivated_at": gorm.Expr("COALESCE(deactivated_at, ?)", time.Now()),
+	}
+}
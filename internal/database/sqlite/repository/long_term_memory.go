@@ -5,8 +5,8 @@ import (
 	sqlite "github.com/Mateus-Lacerda/better-mem/internal/database/sqlite"
 	"github.com/Mateus-Lacerda/better-mem/internal/repository"
 	"context"
+	"errors"
 	"log/slog"
-	"time"
 
 	"gorm.io/gorm"
 )
@@ -35,9 +35,15 @@ func (l *LongTermMemoryRepository) G() gorm.Interface[sqlite.LongTermMemory] {
 	return gorm.G[sqlite.LongTermMemory](l.DB)
 }
 
+// scoped restricts the query to the tenant carried by ctx
+func (l *LongTermMemoryRepository) scoped(ctx context.Context) gorm.ChainInterface[sqlite.LongTermMemory] {
+	return l.G().Where("tenant_id = ?", core.TenantFromContext(ctx))
+}
+
 // Create implements [repository.LongTermMemoryRepository]
 func (l *LongTermMemoryRepository) Create(ctx context.Context, memory *core.NewLongTermMemory) (*core.LongTermMemory, error) {
 	dbMemory := l.helper.SchemaToDbModel(memory)
+	dbMemory.TenantID = core.TenantFromContext(ctx)
 	if err := l.G().Create(ctx, dbMemory); err != nil {
 		return nil, err
 	}
@@ -55,17 +61,15 @@ func (l *LongTermMemoryRepository) Create(ctx context.Context, memory *core.NewL
 
 // Deactivate implements [repository.LongTermMemoryRepository]
 func (l *LongTermMemoryRepository) Deactivate(ctx context.Context, chatId string, memoryId string) error {
-	if _, err := l.G().
-		Where("chat_id = ? AND id = ?", chatId, memoryId).
-		Update(ctx, "active", false); err != nil {
-		return err
-	}
-	return nil
+	return l.DB.WithContext(ctx).
+		Model(&sqlite.LongTermMemory{}).
+		Where("tenant_id = ? AND chat_id = ? AND id = ?", core.TenantFromContext(ctx), chatId, memoryId).
+		Updates(deactivation()).Error
 }
 
 // GetByChatId implements [repository.LongTermMemoryRepository]
 func (l *LongTermMemoryRepository) GetByChatId(ctx context.Context, chatId string, limit int, offset int) (*core.LongTermMemoryArray, error) {
-	dbMemories, err := l.G().
+	dbMemories, err := l.scoped(ctx).
 		Where("chat_id = ?", chatId).Find(ctx)
 	if err != nil {
 		return nil, err
@@ -83,69 +87,81 @@ func (l *LongTermMemoryRepository) GetByChatId(ctx context.Context, chatId strin
 
 // GetById implements [repository.LongTermMemoryRepository]
 func (l *LongTermMemoryRepository) GetById(ctx context.Context, chatId string, memoryId string) (*core.LongTermMemory, error) {
-	dbMemory, err := l.G().
+	dbMemory, err := l.scoped(ctx).
+		Preload("RelatedContext", nil).
 		Where("chat_id = ? AND id = ?", chatId, memoryId).
 		First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, core.MemoryNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 	return l.helper.DbModelToSchema(&dbMemory), nil
 }
 
-// GetScored implements [repository.LongTermMemoryRepository]
-func (l *LongTermMemoryRepository) GetScored(
+// Update implements [repository.LongTermMemoryRepository]
+func (l *LongTermMemoryRepository) Update(
 	ctx context.Context,
 	chatId string,
-	memoriesIds []string,
-) ([]*core.ScoredMemory, error) {
-	var scoredMemories []*core.ScoredMemory
-	if len(memoriesIds) == 0 {
-		return scoredMemories, nil
+	memoryId string,
+	memory string,
+	relatedContext []core.MessageRelatedContext,
+) (*core.LongTermMemory, error) {
+	dbMemory, err := l.scoped(ctx).
+		Where("chat_id = ? AND id = ?", chatId, memoryId).
+		First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, core.MemoryNotFound
 	}
-	dbMemories, err := l.G().Where("id IN ?", memoriesIds).Find(ctx)
 	if err != nil {
-		slog.Error("failed to get memories", "error", err)
 		return nil, err
 	}
-	var rawMemories []core.LongTermMemory
-	for _, m := range dbMemories {
-		rawMemories = append(rawMemories, *l.helper.DbModelToSchema(&m))
+	if memory != "" {
+		if _, err := l.scoped(ctx).
+			Where("chat_id = ? AND id = ?", chatId, memoryId).
+			Update(ctx, "memory", memory); err != nil {
+			return nil, err
+		}
 	}
+	if relatedContext != nil {
+		if err := l.DB.WithContext(ctx).
+			Model(&dbMemory).
+			Association("RelatedContext").
+			Replace(RelatedContextToDbModel(relatedContext)); err != nil {
+			return nil, err
+		}
+	}
+	return l.GetById(ctx, chatId, memoryId)
+}
 
-	maxAge, maxAccessCount, err := l.helper.GetMaxCounts(rawMemories)
+// GetByIds implements [repository.LongTermMemoryRepository]
+func (l *LongTermMemoryRepository) GetByIds(
+	ctx context.Context,
+	chatId string,
+	memoriesIds []string,
+) ([]*core.LongTermMemory, error) {
+	var memories []*core.LongTermMemory
+	if len(memoriesIds) == 0 {
+		return memories, nil
+	}
+	dbMemories, err := l.scoped(ctx).
+		Where("chat_id = ? AND id IN ?", chatId, memoriesIds).
+		Preload("RelatedContext", nil).
+		Find(ctx)
 	if err != nil {
-		slog.Error("failed to get max counts", "error", err)
+		slog.Error("failed to get memories", "error", err)
 		return nil, err
 	}
-	slog.Info("max age", "maxAge", maxAge, "maxAccessCount", maxAccessCount)
-	now := time.Now().Unix()
-	for _, memory := range rawMemories {
-		score, err := l.helper.CalculateScore(
-			memory,
-			maxAge,
-			maxAccessCount,
-			now,
-		)
-		if err != nil {
-			return nil, err
-		}
-		scoredMemories = append(
-			scoredMemories, &core.ScoredMemory{
-				Id:             memory.Id,
-				Text:           memory.Memory,
-				Score:          score,
-				MemoryType:     core.LongTerm,
-				CreatedAt:      memory.CreatedAt,
-				RelatedContext: memory.RelatedContext,
-			},
-		)
-	}
-	return scoredMemories, nil
+	for _, m := range dbMemories {
+		memories = append(memories, l.helper.DbModelToSchema(&m))
+	}
+	return memories, nil
 }
 
 // RegisterUsage implements [repository.LongTermMemoryRepository]
 func (l *LongTermMemoryRepository) RegisterUsage(ctx context.Context, chatId string, memoryId string) error {
-	if _, err := l.G().
+	if _, err := l.scoped(ctx).
 		Where("chat_id = ? AND id = ?", chatId, memoryId).
 		Update(ctx, "access_count", gorm.Expr("access_count + ?", 1)); err != nil {
 		return err
@@ -155,12 +171,36 @@ func (l *LongTermMemoryRepository) RegisterUsage(ctx context.Context, chatId str
 
 // DeactivateAll implements [repository.LongTermMemoryRepository]
 func (l *LongTermMemoryRepository) DeactivateAll(ctx context.Context, chatId string) error {
-	if _, err := l.G().
+	return l.DB.WithContext(ctx).
+		Model(&sqlite.LongTermMemory{}).
+		Where("tenant_id = ? AND chat_id = ?", core.TenantFromContext(ctx), chatId).
+		Updates(deactivation()).Error
+}
+
+// CountActive implements [repository.LongTermMemoryRepository]
+func (l *LongTermMemoryRepository) CountActive(ctx context.Context) (int, error) {
+	count, err := l.scoped(ctx).Where("active").Count(ctx, "*")
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// GetAllByChatId implements [repository.LongTermMemoryRepository]
+func (l *LongTermMemoryRepository) GetAllByChatId(ctx context.Context, chatId string) ([]*core.LongTermMemory, error) {
+	dbMemories, err := l.scoped(ctx).
+		Preload("RelatedContext", nil).
 		Where("chat_id = ?", chatId).
-		Update(ctx, "active", false); err != nil {
-		return err
+		Order("created_at").
+		Find(ctx)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	memories := make([]*core.LongTermMemory, 0, len(dbMemories))
+	for _, m := range dbMemories {
+		memories = append(memories, l.helper.DbModelToSchema(&m))
+	}
+	return memories, nil
 }
 
 var _ repository.LongTermMemoryRepository = (*LongTermMemoryRepository)(nil)
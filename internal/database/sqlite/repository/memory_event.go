@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/sqlite"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type MemoryEventRepository struct {
+	*gorm.DB
+}
+
+func NewMemoryEventRepository() *MemoryEventRepository {
+	db := sqlite.GetDb()
+	return &MemoryEventRepository{
+		DB: db,
+	}
+}
+
+func (r *MemoryEventRepository) G() gorm.Interface[sqlite.MemoryEvent] {
+	return gorm.G[sqlite.MemoryEvent](r.DB)
+}
+
+// scoped restricts the query to the chat, within the tenant carried by ctx
+func (r *MemoryEventRepository) scoped(ctx context.Context, chatId string) gorm.ChainInterface[sqlite.MemoryEvent] {
+	return r.G().Where("tenant_id = ? AND chat_id = ?", core.TenantFromContext(ctx), chatId)
+}
+
+func memoryEventDbModelToSchema(e *sqlite.MemoryEvent) *core.MemoryEvent {
+	return &core.MemoryEvent{
+		Id:           e.ID,
+		Cursor:       e.Seq,
+		Type:         core.MemoryEventType(e.Type),
+		TenantId:     e.TenantID,
+		MemoryId:     e.MemoryID,
+		MemoryType:   core.MemoryTypeEnum(e.MemoryType),
+		Memory:       e.Memory,
+		PromotedFrom: e.PromotedFrom,
+		OccurredAt:   e.OccurredAt,
+	}
+}
+
+// Create implements [repository.MemoryEventRepository]
+func (r *MemoryEventRepository) Create(
+	ctx context.Context, chatId string, event *core.MemoryEvent,
+) error {
+	dbEvent := sqlite.MemoryEvent{
+		ID:           event.Id,
+		TenantID:     core.TenantFromContext(ctx),
+		ChatID:       chatId,
+		Type:         string(event.Type),
+		MemoryID:     event.MemoryId,
+		MemoryType:   int(event.MemoryType),
+		Memory:       event.Memory,
+		PromotedFrom: event.PromotedFrom,
+		OccurredAt:   event.OccurredAt,
+	}
+	if err := r.G().Create(ctx, &dbEvent); err != nil {
+		return err
+	}
+	event.Cursor = dbEvent.Seq
+	return nil
+}
+
+// GetSince implements [repository.MemoryEventRepository]
+func (r *MemoryEventRepository) GetSince(
+	ctx context.Context, chatId string, cursor int64, limit int,
+) ([]*core.MemoryEvent, error) {
+	dbEvents, err := r.scoped(ctx, chatId).
+		Where("seq > ?", cursor).
+		Order("seq").
+		Limit(limit).
+		Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var events []*core.MemoryEvent
+	for _, e := range dbEvents {
+		events = append(events, memoryEventDbModelToSchema(&e))
+	}
+	return events, nil
+}
+
+// LastCursor implements [repository.MemoryEventRepository]
+func (r *MemoryEventRepository) LastCursor(ctx context.Context, chatId string) (int64, error) {
+	dbEvent, err := r.scoped(ctx, chatId).Order("seq DESC").First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return dbEvent.Seq, nil
+}
+
+var _ repository.MemoryEventRepository = (*MemoryEventRepository)(nil)
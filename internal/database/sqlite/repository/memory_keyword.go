@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/sqlite"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var keywordTokenPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+type MemoryKeywordRepository struct {
+	*gorm.DB
+}
+
+func NewMemoryKeywordRepository() *MemoryKeywordRepository {
+	db := sqlite.GetDb()
+	return &MemoryKeywordRepository{
+		DB: db,
+	}
+}
+
+type keywordMatch struct {
+	ID         string
+	MemoryType int
+	Offsets    string
+}
+
+// Builds a MATCH expression that accepts any of the query words,
+// quoting them so user input can not break the FTS syntax
+func keywordMatchExpression(query string) (string, int) {
+	seen := map[string]bool{}
+	var terms []string
+	for _, token := range keywordTokenPattern.FindAllString(strings.ToLower(query), -1) {
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+		terms = append(terms, fmt.Sprintf(`"%s"`, token))
+	}
+	return strings.Join(terms, " OR "), len(terms)
+}
+
+// Scores a match by the share of query terms it contains, offsets()
+// returns four integers per hit, the second being the term number.
+// The FTS tables are not aliased in the query since the auxiliary
+// functions take the hidden column named after the table
+func keywordMatchScore(offsets string, terms int) float32 {
+	fields := strings.Fields(offsets)
+	matched := map[string]bool{}
+	for i := 1; i < len(fields); i += 4 {
+		matched[fields[i]] = true
+	}
+	return float32(len(matched)) / float32(max(terms, 1))
+}
+
+// Search implements [repository.MemoryKeywordRepository]
+func (r *MemoryKeywordRepository) Search(
+	ctx context.Context, chatId string, query string, limit int,
+) ([]core.ScoredMemoryVector, error) {
+	expression, terms := keywordMatchExpression(query)
+	if terms == 0 {
+		return nil, nil
+	}
+	tenantId := core.TenantFromContext(ctx)
+	var matches []keywordMatch
+	if err := r.DB.WithContext(ctx).Raw(
+		`SELECT m.id AS id, ? AS memory_type, offsets(fts_long_term_memories) AS offsets
+		FROM fts_long_term_memories
+		JOIN long_term_memories m ON m.rowid = fts_long_term_memories.docid
+		WHERE fts_long_term_memories.memory MATCH ? AND m.tenant_id = ? AND m.chat_id = ? AND m.active = 1
+		UNION ALL
+		SELECT m.id AS id, ? AS memory_type, offsets(fts_short_term_memories) AS offsets
+		FROM fts_short_term_memories
+		JOIN short_term_memories m ON m.rowid = fts_short_term_memories.docid
+		WHERE fts_short_term_memories.memory MATCH ? AND m.tenant_id = ? AND m.chat_id = ? AND m.active = 1`,
+		int(core.LongTerm), expression, tenantId, chatId,
+		int(core.ShortTerm), expression, tenantId, chatId,
+	).Scan(&matches).Error; err != nil {
+		return nil, err
+	}
+
+	results := make([]core.ScoredMemoryVector, 0, len(matches))
+	for _, match := range matches {
+		results = append(results, core.ScoredMemoryVector{
+			Id:    match.ID,
+			Score: keywordMatchScore(match.Offsets, terms),
+			Payload: core.MemoryPayload{
+				TenantId:   tenantId,
+				ChatId:     chatId,
+				MemoryType: core.MemoryTypeEnum(match.MemoryType),
+				MemoryId:   match.ID,
+				Active:     true,
+			},
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if limit > 0 && limit < len(results) {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+var _ repository.MemoryKeywordRepository = (*MemoryKeywordRepository)(nil)
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/sqlite"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type MemoryMergeRepository struct {
+	*gorm.DB
+}
+
+func NewMemoryMergeRepository() *MemoryMergeRepository {
+	db := sqlite.GetDb()
+	return &MemoryMergeRepository{
+		DB: db,
+	}
+}
+
+func MemoryMergeRepositoryWithTransaction(db *gorm.DB) *MemoryMergeRepository {
+	return &MemoryMergeRepository{
+		DB: db,
+	}
+}
+
+func (r *MemoryMergeRepository) G() gorm.Interface[sqlite.MemoryMerge] {
+	return gorm.G[sqlite.MemoryMerge](r.DB)
+}
+
+// scoped restricts the query to the tenant carried by ctx
+func (r *MemoryMergeRepository) scoped(ctx context.Context) gorm.ChainInterface[sqlite.MemoryMerge] {
+	return r.G().Where("tenant_id = ?", core.TenantFromContext(ctx))
+}
+
+func memoryMergeDbModelToSchema(m *sqlite.MemoryMerge) *core.MemoryMerge {
+	previousRelatedContext := []core.MessageRelatedContext{}
+	for _, c := range m.PreviousRelatedContext {
+		previousRelatedContext = append(
+			previousRelatedContext,
+			core.MessageRelatedContext{Context: c.Context, User: c.User},
+		)
+	}
+	return &core.MemoryMerge{
+		Id:                     m.ID,
+		MemoryId:               m.MemoryID,
+		PreviousMemory:         m.PreviousMemory,
+		PreviousRelatedContext: previousRelatedContext,
+		IncomingMemory:         m.IncomingMemory,
+		Score:                  m.Score,
+		CreatedAt:              m.CreatedAt,
+	}
+}
+
+// Create implements [repository.MemoryMergeRepository]
+func (r *MemoryMergeRepository) Create(
+	ctx context.Context, merge *core.MemoryMerge,
+) (*core.MemoryMerge, error) {
+	dbMerge := sqlite.MemoryMerge{
+		TenantID:               core.TenantFromContext(ctx),
+		MemoryID:               merge.MemoryId,
+		PreviousMemory:         merge.PreviousMemory,
+		PreviousRelatedContext: RelatedContextToDbModel(merge.PreviousRelatedContext),
+		IncomingMemory:         merge.IncomingMemory,
+		Score:                  merge.Score,
+		CreatedAt:              merge.CreatedAt,
+	}
+	if err := r.G().Create(ctx, &dbMerge); err != nil {
+		return nil, err
+	}
+	return memoryMergeDbModelToSchema(&dbMerge), nil
+}
+
+// GetById implements [repository.MemoryMergeRepository]
+func (r *MemoryMergeRepository) GetById(
+	ctx context.Context, memoryId string, mergeId string,
+) (*core.MemoryMerge, error) {
+	dbMerge, err := r.scoped(ctx).
+		Preload("PreviousRelatedContext", nil).
+		Where("memory_id = ? AND id = ?", memoryId, mergeId).
+		First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, core.MemoryMergeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return memoryMergeDbModelToSchema(&dbMerge), nil
+}
+
+// GetByMemoryId implements [repository.MemoryMergeRepository]
+func (r *MemoryMergeRepository) GetByMemoryId(
+	ctx context.Context, memoryId string,
+) ([]*core.MemoryMerge, error) {
+	dbMerges, err := r.scoped(ctx).
+		Preload("PreviousRelatedContext", nil).
+		Where("memory_id = ?", memoryId).
+		Order("created_at").
+		Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	merges := []*core.MemoryMerge{}
+	for _, m := range dbMerges {
+		merges = append(merges, memoryMergeDbModelToSchema(&m))
+	}
+	return merges, nil
+}
+
+var _ repository.MemoryMergeRepository = (*MemoryMergeRepository)(nil)
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/sqlite"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Where a memory type is stored, with the join table of its related context
+type memoryTables struct {
+	table       string
+	joinTable   string
+	ownerColumn string
+}
+
+var memoryTablesByType = map[core.MemoryTypeEnum]memoryTables{
+	core.ShortTerm: {"short_term_memories", "related_content", "short_term_memory_id"},
+	core.LongTerm:  {"long_term_memories", "long_term_related_content", "long_term_memory_id"},
+}
+
+// Related context still attached to something
+const attachedRelatedContextIds = `SELECT related_context_content_id FROM related_content
+	UNION SELECT related_context_content_id FROM long_term_related_content
+	UNION SELECT related_context_content_id FROM message_related_content
+	UNION SELECT related_context_content_id FROM memory_merge_related_content`
+
+type MemoryRetentionRepository struct {
+	*gorm.DB
+}
+
+func NewMemoryRetentionRepository() *MemoryRetentionRepository {
+	db := sqlite.GetDb()
+	return &MemoryRetentionRepository{
+		DB: db,
+	}
+}
+
+func tablesOf(memoryType core.MemoryTypeEnum) (memoryTables, error) {
+	tables, ok := memoryTablesByType[memoryType]
+	if !ok {
+		return memoryTables{}, fmt.Errorf("no table for memory type %d", memoryType)
+	}
+	return tables, nil
+}
+
+// MarkDeactivated implements [repository.MemoryRetentionRepository]
+func (r *MemoryRetentionRepository) MarkDeactivated(ctx context.Context, at time.Time) (int, error) {
+	marked := 0
+	for _, model := range []any{&sqlite.ShortTermMemory{}, &sqlite.LongTermMemory{}} {
+		result := r.DB.WithContext(ctx).
+			Model(model).
+			Where("tenant_id = ? AND NOT active AND deactivated_at IS NULL", core.TenantFromContext(ctx)).
+			Update("deactivated_at", at)
+		if result.Error != nil {
+			return marked, result.Error
+		}
+		marked += int(result.RowsAffected)
+	}
+	return marked, nil
+}
+
+// GetExpired implements [repository.MemoryRetentionRepository]
+func (r *MemoryRetentionRepository) GetExpired(
+	ctx context.Context, memoryType core.MemoryTypeEnum, cutoff time.Time, limit int,
+) ([]string, error) {
+	tables, err := tablesOf(memoryType)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	err = r.DB.WithContext(ctx).
+		Table(tables.table).
+		Where("tenant_id = ? AND NOT active AND deactivated_at < ?", core.TenantFromContext(ctx), cutoff).
+		Order("deactivated_at").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Delete implements [repository.MemoryRetentionRepository]
+func (r *MemoryRetentionRepository) Delete(
+	ctx context.Context, memoryType core.MemoryTypeEnum, ids []string,
+) (*core.RetentionPurgeStats, error) {
+	tables, err := tablesOf(memoryType)
+	if err != nil {
+		return nil, err
+	}
+	var deleted core.RetentionPurgeStats
+	tenantId := core.TenantFromContext(ctx)
+	err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		merges := tx.Table("memory_merges").
+			Select("id").
+			Where("tenant_id = ? AND memory_id IN ?", tenantId, ids)
+		if err := tx.Exec(
+			"DELETE FROM memory_merge_related_content WHERE memory_merge_id IN (?)", merges,
+		).Error; err != nil {
+			return err
+		}
+		result := tx.Exec("DELETE FROM memory_merges WHERE tenant_id = ? AND memory_id IN ?", tenantId, ids)
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted.MemoryMerges = int(result.RowsAffected)
+		if err := tx.Exec(
+			fmt.Sprintf("DELETE FROM %s WHERE %s IN ?", tables.joinTable, tables.ownerColumn), ids,
+		).Error; err != nil {
+			return err
+		}
+		// Only the memories of the tenant that are still inactive
+		result = tx.Exec(
+			fmt.Sprintf("DELETE FROM %s WHERE tenant_id = ? AND NOT active AND id IN ?", tables.table),
+			tenantId, ids,
+		)
+		if result.Error != nil {
+			return result.Error
+		}
+		if memoryType == core.ShortTerm {
+			deleted.ShortTermMemories = int(result.RowsAffected)
+		} else {
+			deleted.LongTermMemories = int(result.RowsAffected)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &deleted, nil
+}
+
+// DeleteOrphanedRelatedContext implements [repository.MemoryRetentionRepository]
+func (r *MemoryRetentionRepository) DeleteOrphanedRelatedContext(ctx context.Context) (int, error) {
+	result := r.DB.WithContext(ctx).Exec(
+		"DELETE FROM related_context_contents WHERE id NOT IN (" + attachedRelatedContextIds + ")",
+	)
+	return int(result.RowsAffected), result.Error
+}
+
+var _ repository.MemoryRetentionRepository = (*MemoryRetentionRepository)(nil)
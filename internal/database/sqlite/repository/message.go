@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/sqlite"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MessageRepository struct {
+	*gorm.DB
+}
+
+func NewMessageRepository() *MessageRepository {
+	db := sqlite.GetDb()
+	return &MessageRepository{
+		DB: db,
+	}
+}
+
+func MessageRepositoryWithTransaction(db *gorm.DB) *MessageRepository {
+	return &MessageRepository{
+		DB: db,
+	}
+}
+
+func (r *MessageRepository) G() gorm.Interface[sqlite.Message] {
+	return gorm.G[sqlite.Message](r.DB)
+}
+
+// scoped restricts the query to the tenant carried by ctx
+func (r *MessageRepository) scoped(ctx context.Context) gorm.ChainInterface[sqlite.Message] {
+	return r.G().Where("tenant_id = ?", core.TenantFromContext(ctx))
+}
+
+func messageDbModelToSchema(m *sqlite.Message) *core.Message {
+	var relatedContext []core.MessageRelatedContext
+	for _, c := range m.RelatedContext {
+		relatedContext = append(
+			relatedContext,
+			core.MessageRelatedContext{Context: c.Context, User: c.User},
+		)
+	}
+	status := core.MessageTaskStatus(m.Status)
+	return &core.Message{
+		Id:             m.ID,
+		ChatId:         m.ChatID,
+		Message:        m.Message,
+		RelatedContext: relatedContext,
+		Status:         status,
+		Label:          core.MemoryTypeEnum(m.Label),
+		Merged:         status == core.MessageMerged,
+		MemoryId:       m.MemoryID,
+		Error:          m.Error,
+		Attempts:       m.Attempts,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+// Create implements [repository.MessageRepository]
+func (r *MessageRepository) Create(ctx context.Context, message *core.Message) error {
+	return r.G().Create(ctx, &sqlite.Message{
+		ID:             message.Id,
+		TenantID:       core.TenantFromContext(ctx),
+		ChatID:         message.ChatId,
+		Message:        message.Message,
+		RelatedContext: RelatedContextToDbModel(message.RelatedContext),
+		Status:         string(message.Status),
+		CreatedAt:      message.CreatedAt,
+		UpdatedAt:      message.CreatedAt,
+	})
+}
+
+// GetById implements [repository.MessageRepository]
+func (r *MessageRepository) GetById(ctx context.Context, id string) (*core.Message, error) {
+	dbMessage, err := r.scoped(ctx).
+		Preload("RelatedContext", nil).
+		Where("id = ?", id).
+		First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, core.MessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return messageDbModelToSchema(&dbMessage), nil
+}
+
+// GetByChatId implements [repository.MessageRepository]
+func (r *MessageRepository) GetByChatId(
+	ctx context.Context, chatId string, limit int, offset int,
+) (*core.MessageArray, error) {
+	total, err := r.scoped(ctx).Where("chat_id = ?", chatId).Count(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	dbMessages, err := r.scoped(ctx).
+		Preload("RelatedContext", nil).
+		Where("chat_id = ?", chatId).
+		Order("created_at").
+		Limit(limit).
+		Offset(offset).
+		Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	messages := []*core.Message{}
+	for _, m := range dbMessages {
+		messages = append(messages, messageDbModelToSchema(&m))
+	}
+	return &core.MessageArray{
+		Messages: messages,
+		Total:    int(total),
+	}, nil
+}
+
+// GetByMemoryId implements [repository.MessageRepository]
+func (r *MessageRepository) GetByMemoryId(ctx context.Context, memoryId string) ([]*core.Message, error) {
+	dbMessages, err := r.scoped(ctx).
+		Preload("RelatedContext", nil).
+		Where("memory_id = ?", memoryId).
+		Order("created_at").
+		Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	messages := []*core.Message{}
+	for _, m := range dbMessages {
+		messages = append(messages, messageDbModelToSchema(&m))
+	}
+	return messages, nil
+}
+
+// ReassignMemory implements [repository.MessageRepository]
+func (r *MessageRepository) ReassignMemory(
+	ctx context.Context, memoryId string, newMemoryId string,
+) error {
+	_, err := r.scoped(ctx).
+		Where("memory_id = ?", memoryId).
+		Update(ctx, "memory_id", newMemoryId)
+	return err
+}
+
+// StartAttempt implements [repository.MessageRepository]
+func (r *MessageRepository) StartAttempt(ctx context.Context, id string) error {
+	result := r.DB.WithContext(ctx).
+		Model(&sqlite.Message{}).
+		Where("tenant_id = ? AND id = ?", core.TenantFromContext(ctx), id).
+		Updates(map[string]any{
+			"status":     string(core.MessageClassifying),
+			"error":      "",
+			"attempts":   gorm.Expr("attempts + ?", 1),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return core.MessageNotFound
+	}
+	return nil
+}
+
+// SetResult implements [repository.MessageRepository]
+func (r *MessageRepository) SetResult(
+	ctx context.Context, id string, result *core.MessageTaskResult,
+) error {
+	rowsAffected, err := r.scoped(ctx).
+		Where("id = ?", id).
+		Select("status", "label", "memory_id", "error", "updated_at").
+		Updates(ctx, sqlite.Message{
+			Status:    string(result.Status),
+			Label:     int(result.Label),
+			MemoryID:  result.MemoryId,
+			Error:     result.Error,
+			UpdatedAt: time.Now(),
+		})
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return core.MessageNotFound
+	}
+	return nil
+}
+
+var _ repository.MessageRepository = (*MessageRepository)(nil)
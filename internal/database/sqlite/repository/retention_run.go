@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/sqlite"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type RetentionRunRepository struct {
+	*gorm.DB
+}
+
+func NewRetentionRunRepository() *RetentionRunRepository {
+	db := sqlite.GetDb()
+	return &RetentionRunRepository{
+		DB: db,
+	}
+}
+
+func (r *RetentionRunRepository) G() gorm.Interface[sqlite.RetentionRun] {
+	return gorm.G[sqlite.RetentionRun](r.DB)
+}
+
+func retentionRunDbModelToSchema(r *sqlite.RetentionRun) *core.RetentionRun {
+	return &core.RetentionRun{
+		Id:            r.ID,
+		RetentionDays: r.RetentionDays,
+		Cutoff:        r.Cutoff,
+		Tenants:       r.Tenants,
+		Deleted: core.RetentionPurgeStats{
+			ShortTermMemories:       r.ShortTermMemories,
+			LongTermMemories:        r.LongTermMemories,
+			Vectors:                 r.Vectors,
+			MemoryMerges:            r.MemoryMerges,
+			OrphanedRelatedContexts: r.OrphanedRelatedContexts,
+		},
+		Error:       r.Error,
+		StartedAt:   r.StartedAt,
+		CompletedAt: r.CompletedAt,
+	}
+}
+
+// Create implements [repository.RetentionRunRepository]
+func (r *RetentionRunRepository) Create(ctx context.Context, run *core.RetentionRun) error {
+	dbRun := sqlite.RetentionRun{
+		RetentionDays:           run.RetentionDays,
+		Cutoff:                  run.Cutoff,
+		Tenants:                 run.Tenants,
+		ShortTermMemories:       run.Deleted.ShortTermMemories,
+		LongTermMemories:        run.Deleted.LongTermMemories,
+		Vectors:                 run.Deleted.Vectors,
+		MemoryMerges:            run.Deleted.MemoryMerges,
+		OrphanedRelatedContexts: run.Deleted.OrphanedRelatedContexts,
+		Error:                   run.Error,
+		StartedAt:               run.StartedAt,
+		CompletedAt:             run.CompletedAt,
+	}
+	if err := r.G().Create(ctx, &dbRun); err != nil {
+		return err
+	}
+	run.Id = dbRun.ID
+	return nil
+}
+
+// GetAll implements [repository.RetentionRunRepository]
+func (r *RetentionRunRepository) GetAll(ctx context.Context, limit int, offset int) ([]*core.RetentionRun, error) {
+	dbRuns, err := r.G().
+		Order("started_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	runs := []*core.RetentionRun{}
+	for _, run := range dbRuns {
+		runs = append(runs, retentionRunDbModelToSchema(&run))
+	}
+	return runs, nil
+}
+
+var _ repository.RetentionRunRepository = (*RetentionRunRepository)(nil)
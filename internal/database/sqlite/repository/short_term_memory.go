@@ -5,6 +5,7 @@ import (
 	"github.com/Mateus-Lacerda/better-mem/internal/database/sqlite"
 	"github.com/Mateus-Lacerda/better-mem/internal/repository"
 	"context"
+	"errors"
 	"log/slog"
 	"time"
 
@@ -35,9 +36,15 @@ func (s *ShortTermMemoryRepository) G() gorm.Interface[sqlite.ShortTermMemory] {
 	return gorm.G[sqlite.ShortTermMemory](s.DB)
 }
 
+// scoped restricts the query to the tenant carried by ctx
+func (s *ShortTermMemoryRepository) scoped(ctx context.Context) gorm.ChainInterface[sqlite.ShortTermMemory] {
+	return s.G().Where("tenant_id = ?", core.TenantFromContext(ctx))
+}
+
 // Create implements [repository.ShortTermMemoryRepository]
 func (s ShortTermMemoryRepository) Create(ctx context.Context, memory *core.NewShortTermMemory) (*core.ShortTermMemory, error) {
 	dbMemory := s.helper.SchemaToDbModel(memory)
+	dbMemory.TenantID = core.TenantFromContext(ctx)
 	if err := s.G().Create(ctx, dbMemory); err != nil {
 		return nil, err
 	}
@@ -56,17 +63,15 @@ func (s ShortTermMemoryRepository) Create(ctx context.Context, memory *core.NewS
 
 // Deactivate implements [repository.ShortTermMemoryRepository]
 func (s ShortTermMemoryRepository) Deactivate(ctx context.Context, chatId string, memoryId string) error {
-	if _, err := s.G().
-		Where("chat_id = ? AND id = ?", chatId, memoryId).
-		Update(ctx, "active", false); err != nil {
-		return err
-	}
-	return nil
+	return s.DB.WithContext(ctx).
+		Model(&sqlite.ShortTermMemory{}).
+		Where("tenant_id = ? AND chat_id = ? AND id = ?", core.TenantFromContext(ctx), chatId, memoryId).
+		Updates(deactivation()).Error
 }
 
 // GetByChatId implements [repository.ShortTermMemoryRepository]
 func (s ShortTermMemoryRepository) GetByChatId(ctx context.Context, chatId string, limit int, offset int) (*core.ShortTermMemoryArray, error) {
-	dbMemories, err := s.G().
+	dbMemories, err := s.scoped(ctx).
 		Where("chat_id = ?", chatId).Find(ctx)
 	if err != nil {
 		return nil, err
@@ -84,52 +89,74 @@ func (s ShortTermMemoryRepository) GetByChatId(ctx context.Context, chatId strin
 
 // GetById implements [repository.ShortTermMemoryRepository]
 func (s ShortTermMemoryRepository) GetById(ctx context.Context, chatId string, memoryId string) (*core.ShortTermMemory, error) {
-	dbMemory, err := s.G().
+	dbMemory, err := s.scoped(ctx).
+		Preload("RelatedContext", nil).
 		Where("chat_id = ? AND id = ?", chatId, memoryId).
 		First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, core.MemoryNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 	return s.helper.DbModelToSchema(&dbMemory), nil
 }
 
-// GetScored implements [repository.ShortTermMemoryRepository]
-func (s ShortTermMemoryRepository) GetScored(
+// Update implements [repository.ShortTermMemoryRepository]
+func (s ShortTermMemoryRepository) Update(
+	ctx context.Context,
+	chatId string,
+	memoryId string,
+	memory string,
+	relatedContext []core.MessageRelatedContext,
+) (*core.ShortTermMemory, error) {
+	dbMemory, err := s.scoped(ctx).
+		Where("chat_id = ? AND id = ?", chatId, memoryId).
+		First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, core.MemoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if memory != "" {
+		if _, err := s.scoped(ctx).
+			Where("chat_id = ? AND id = ?", chatId, memoryId).
+			Update(ctx, "memory", memory); err != nil {
+			return nil, err
+		}
+	}
+	if relatedContext != nil {
+		if err := s.DB.WithContext(ctx).
+			Model(&dbMemory).
+			Association("RelatedContext").
+			Replace(RelatedContextToDbModel(relatedContext)); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetById(ctx, chatId, memoryId)
+}
+
+// GetByIds implements [repository.ShortTermMemoryRepository]
+func (s ShortTermMemoryRepository) GetByIds(
 	ctx context.Context,
 	chatId string,
 	memoriesIds []string,
-) ([]*core.ScoredMemory, error) {
-	var memories []*core.ScoredMemory
+) ([]*core.ShortTermMemory, error) {
+	var memories []*core.ShortTermMemory
 	if len(memoriesIds) == 0 {
 		return memories, nil
 	}
-	dbMemories, err := s.G().Where("id IN ?", memoriesIds).Find(ctx)
+	dbMemories, err := s.scoped(ctx).
+		Where("chat_id = ? AND id IN ?", chatId, memoriesIds).
+		Preload("RelatedContext", nil).
+		Find(ctx)
 	if err != nil {
 		slog.Error("failed to get memories", "error", err)
 		return nil, err
 	}
-	var rawMemories []core.ShortTermMemory
 	for _, m := range dbMemories {
-		rawMemories = append(rawMemories, *s.helper.DbModelToSchema(&m))
-	}
-
-	maxAccessCount, maxMergeCount := s.helper.GetMaxCounts(rawMemories)
-	now := time.Now().Unix()
-	for _, memory := range rawMemories {
-		score, err := s.helper.CalculateScore(
-			memory, maxAccessCount, maxMergeCount, now,
-		)
-		if err != nil {
-			return nil, err
-		}
-		memories = append(memories, &core.ScoredMemory{
-			Id:             memory.Id,
-			Score:          score,
-			Text:           memory.Memory,
-			MemoryType:     core.ShortTerm,
-			CreatedAt:      memory.CreatedAt,
-			RelatedContext: memory.RelatedContext,
-		})
+		memories = append(memories, s.helper.DbModelToSchema(&m))
 	}
 	return memories, nil
 }
@@ -143,11 +170,14 @@ func (s ShortTermMemoryRepository) Merge(
 	otherMemoryRelatedContext []core.MessageRelatedContext,
 ) (*core.ShortTermMemory, error) {
 	// We will just use the newest memory text, and increment the merge count
-	// TODO: Store merges in a separate collection for data analysis
+	// The previous text is kept by the merge history
 	var updatedMemory sqlite.ShortTermMemory
 	result := s.DB.Model(&updatedMemory).
 		Clauses(clause.Returning{}).
-		Where("chat_id = ? AND id = ?", chatId, memoryId).
+		Where(
+			"tenant_id = ? AND chat_id = ? AND id = ?",
+			core.TenantFromContext(ctx), chatId, memoryId,
+		).
 		Updates(map[string]any{
 			"memory":          otherMemory,
 			"related_context": otherMemoryRelatedContext,
@@ -161,7 +191,7 @@ func (s ShortTermMemoryRepository) Merge(
 
 // RegisterUsage implements [repository.ShortTermMemoryRepository]
 func (s ShortTermMemoryRepository) RegisterUsage(ctx context.Context, chatId string, memoryId string) error {
-	if _, err := s.G().
+	if _, err := s.scoped(ctx).
 		Where("chat_id = ? AND id = ?", chatId, memoryId).
 		Update(ctx, "access_count", gorm.Expr("access_count + ?", 1)); err != nil {
 		return err
@@ -176,7 +206,7 @@ func (s ShortTermMemoryRepository) GetElligibleForDeactivation(
 	window time.Duration,
 	minimalRelevance int,
 ) ([]*core.ShortTermMemory, error) {
-	dbMemories, err := s.G().
+	dbMemories, err := s.scoped(ctx).
 		Where(
 			"chat_id = ? AND active AND created_at >= ? AND access_count + merge_count < ?",
 			chatId, time.Now().Add(-window), minimalRelevance,
@@ -196,7 +226,7 @@ func (s ShortTermMemoryRepository) GetElligibleForDeactivation(
 func (s ShortTermMemoryRepository) GetElligibleForPromotion(
 	ctx context.Context, chatId string, minimalRelevance int,
 ) ([]*core.ShortTermMemory, error) {
-	dbMemories, err := s.G().
+	dbMemories, err := s.scoped(ctx).
 		Where(
 			"chat_id = ? AND active AND access_count + merge_count >= ?",
 			chatId, minimalRelevance,
@@ -214,12 +244,36 @@ func (s ShortTermMemoryRepository) GetElligibleForPromotion(
 
 // DeactivateAll implements [repository.ShortTermMemoryRepository]
 func (s ShortTermMemoryRepository) DeactivateAll(ctx context.Context, chatId string) error {
-	if _, err := s.G().
+	return s.DB.WithContext(ctx).
+		Model(&sqlite.ShortTermMemory{}).
+		Where("tenant_id = ? AND chat_id = ?", core.TenantFromContext(ctx), chatId).
+		Updates(deactivation()).Error
+}
+
+// CountActive implements [repository.ShortTermMemoryRepository]
+func (s ShortTermMemoryRepository) CountActive(ctx context.Context) (int, error) {
+	count, err := s.scoped(ctx).Where("active").Count(ctx, "*")
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// GetAllByChatId implements [repository.ShortTermMemoryRepository]
+func (s ShortTermMemoryRepository) GetAllByChatId(ctx context.Context, chatId string) ([]*core.ShortTermMemory, error) {
+	dbMemories, err := s.scoped(ctx).
+		Preload("RelatedContext", nil).
 		Where("chat_id = ?", chatId).
-		Update(ctx, "active", false); err != nil {
-		return err
+		Order("created_at").
+		Find(ctx)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	memories := make([]*core.ShortTermMemory, 0, len(dbMemories))
+	for _, m := range dbMemories {
+		memories = append(memories, s.helper.DbModelToSchema(&m))
+	}
+	return memories, nil
 }
 
 var _ repository.ShortTermMemoryRepository = (*ShortTermMemoryRepository)(nil)
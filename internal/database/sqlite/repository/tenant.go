@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/sqlite"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type TenantRepository struct {
+	*gorm.DB
+}
+
+func NewTenantRepository() *TenantRepository {
+	db := sqlite.GetDb()
+	return &TenantRepository{
+		DB: db,
+	}
+}
+
+func (r *TenantRepository) G() gorm.Interface[sqlite.Tenant] {
+	return gorm.G[sqlite.Tenant](r.DB)
+}
+
+func tenantDbModelToSchema(t *sqlite.Tenant) *core.Tenant {
+	return &core.Tenant{
+		Id:   t.ID,
+		Name: t.Name,
+		Quota: core.TenantQuota{
+			MaxChats:    t.MaxChats,
+			MaxMemories: t.MaxMemories,
+		},
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+// Create implements [repository.TenantRepository]
+func (r *TenantRepository) Create(ctx context.Context, tenant *core.Tenant) (*core.Tenant, error) {
+	if _, err := r.G().Where("id = ?", tenant.Id).First(ctx); err == nil {
+		return nil, core.TenantAlreadyExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	dbTenant := sqlite.Tenant{
+		ID:          tenant.Id,
+		Name:        tenant.Name,
+		MaxChats:    tenant.Quota.MaxChats,
+		MaxMemories: tenant.Quota.MaxMemories,
+		CreatedAt:   tenant.CreatedAt,
+	}
+	if err := r.G().Create(ctx, &dbTenant); err != nil {
+		return nil, err
+	}
+	return tenantDbModelToSchema(&dbTenant), nil
+}
+
+// GetAll implements [repository.TenantRepository]
+func (r *TenantRepository) GetAll(ctx context.Context) ([]*core.Tenant, error) {
+	dbTenants, err := r.G().Order("created_at").Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var tenants []*core.Tenant
+	for _, t := range dbTenants {
+		tenants = append(tenants, tenantDbModelToSchema(&t))
+	}
+	return tenants, nil
+}
+
+// GetById implements [repository.TenantRepository]
+func (r *TenantRepository) GetById(ctx context.Context, id string) (*core.Tenant, error) {
+	dbTenant, err := r.G().Where("id = ?", id).First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, core.TenantNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tenantDbModelToSchema(&dbTenant), nil
+}
+
+// UpdateQuota implements [repository.TenantRepository]
+func (r *TenantRepository) UpdateQuota(
+	ctx context.Context, id string, quota core.TenantQuota,
+) (*core.Tenant, error) {
+	rowsAffected, err := r.G().
+		Where("id = ?", id).
+		Select("max_chats", "max_memories").
+		Updates(ctx, sqlite.Tenant{
+			MaxChats:    quota.MaxChats,
+			MaxMemories: quota.MaxMemories,
+		})
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, core.TenantNotFound
+	}
+	return r.GetById(ctx, id)
+}
+
+var _ repository.TenantRepository = (*TenantRepository)(nil)
@@ -8,6 +8,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"log/slog"
+	"strings"
+	"time"
 
 	sqlite_vec "github.com/asg017/sqlite-vec-go-bindings/cgo"
 )
@@ -54,6 +56,44 @@ func (m *MemoryRepository) Create(
 	return err
 }
 
+// Update implements [vector.MemoryVectorRepository]
+func (m *MemoryRepository) Update(
+	ctx context.Context,
+	chatId string,
+	vectors []float32,
+	memoryId string,
+) error {
+	blob, err := sqlite_vec.SerializeFloat32(vectors)
+	if err != nil {
+		return err
+	}
+
+	vectorsJSON, err := json.Marshal(vectors)
+	if err != nil {
+		return err
+	}
+
+	tenantId := core.TenantFromContext(ctx)
+	result, err := m.db.ExecContext(
+		ctx,
+		`UPDATE vec_memories SET embedding = ?, vectors_json = ?
+		WHERE id = ? AND `+chatVectorsFilter,
+		blob, string(vectorsJSON), memoryId, tenantId, chatId, tenantId, chatId,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	slog.Info("MemoryRepository.Update", "rowsAffected", rowsAffected)
+	if rowsAffected == 0 {
+		return core.MemoryNotFound
+	}
+	return nil
+}
+
 // Search implements [vector.MemoryVectorRepository]
 func (m *MemoryRepository) Search(
 	ctx context.Context,
@@ -87,16 +127,18 @@ func (m *MemoryRepository) Search(
 			m.active
 		FROM knn_matches knn
 		LEFT JOIN (
-			SELECT id, chat_id, active FROM long_term_memories
+			SELECT id, tenant_id, chat_id, active FROM long_term_memories
 			UNION ALL
-			SELECT id, chat_id, active FROM short_term_memories
+			SELECT id, tenant_id, chat_id, active FROM short_term_memories
 		) m ON knn.id = m.id
-		WHERE m.chat_id = ?
+		WHERE m.tenant_id = ?
+		  AND m.chat_id = ?
 		  AND m.active = 1
 		ORDER BY knn.distance
 	`
 
-	rows, err := m.db.QueryContext(ctx, query, blob, limit, chatId)
+	tenantId := core.TenantFromContext(ctx)
+	rows, err := m.db.QueryContext(ctx, query, blob, limit, tenantId, chatId)
 	if err != nil {
 		slog.Error("Query error", "error", err)
 		return nil, err
@@ -146,6 +188,7 @@ func (m *MemoryRepository) Search(
 			Vectors: vectors,
 			Score:   score,
 			Payload: core.MemoryPayload{
+				TenantId:   tenantId,
 				ChatId:     dbChatId,
 				MemoryType: core.MemoryTypeEnum(memoryType),
 				MemoryId:   id,
@@ -174,14 +217,18 @@ func (m *MemoryRepository) DeactivateAll(ctx context.Context, chatId string) err
 	}
 	if _, err := tx.ExecContext(
 		ctx,
-		"UPDATE long_term_memories SET memory = false WHERE chat_id = ?",
+		"UPDATE long_term_memories SET active = false, deactivated_at = COALESCE(deactivated_at, ?) WHERE tenant_id = ? AND chat_id = ?",
+		time.Now(),
+		core.TenantFromContext(ctx),
 		chatId,
 	); err != nil {
 		return err
 	}
 	if _, err := tx.ExecContext(
 		ctx,
-		"UPDATE short_term_memories SET memory = false WHERE chat_id = ?",
+		"UPDATE short_term_memories SET active = false, deactivated_at = COALESCE(deactivated_at, ?) WHERE tenant_id = ? AND chat_id = ?",
+		time.Now(),
+		core.TenantFromContext(ctx),
 		chatId,
 	); err != nil {
 		return err
@@ -200,7 +247,9 @@ func (m *MemoryRepository) Deactivate(ctx context.Context, chatId string, id str
 	}
 	if _, err := tx.ExecContext(
 		ctx,
-		"UPDATE long_term_memories SET memory = false WHERE chat_id = ? AND memory_id = ?",
+		"UPDATE long_term_memories SET active = false, deactivated_at = COALESCE(deactivated_at, ?) WHERE tenant_id = ? AND chat_id = ? AND id = ?",
+		time.Now(),
+		core.TenantFromContext(ctx),
 		chatId,
 		id,
 	); err != nil {
@@ -208,7 +257,9 @@ func (m *MemoryRepository) Deactivate(ctx context.Context, chatId string, id str
 	}
 	if _, err := tx.ExecContext(
 		ctx,
-		"UPDATE short_term_memories SET memory = false WHERE chat_id = ? AND memory_id = ?",
+		"UPDATE short_term_memories SET active = false, deactivated_at = COALESCE(deactivated_at, ?) WHERE tenant_id = ? AND chat_id = ? AND id = ?",
+		time.Now(),
+		core.TenantFromContext(ctx),
 		chatId,
 		id,
 	); err != nil {
@@ -217,4 +268,99 @@ func (m *MemoryRepository) Deactivate(ctx context.Context, chatId string, id str
 	return tx.Commit()
 }
 
+// The vectors only carry the memory id, the chat comes from the
+// memory tables, so they must go before the memories do
+const chatVectorsFilter = `id IN (
+	SELECT id FROM long_term_memories WHERE tenant_id = ? AND chat_id = ?
+	UNION ALL
+	SELECT id FROM short_term_memories WHERE tenant_id = ? AND chat_id = ?
+)`
+
+// DeleteAll implements [vector.MemoryVectorRepository]
+func (m *MemoryRepository) DeleteAll(ctx context.Context, chatId string) (int, error) {
+	tenantId := core.TenantFromContext(ctx)
+	result, err := m.db.ExecContext(
+		ctx,
+		"DELETE FROM vec_memories WHERE "+chatVectorsFilter,
+		tenantId, chatId, tenantId, chatId,
+	)
+	if err != nil {
+		return 0, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rowsAffected), nil
+}
+
+// Count implements [vector.MemoryVectorRepository]
+func (m *MemoryRepository) Count(ctx context.Context, chatId string) (int, error) {
+	tenantId := core.TenantFromContext(ctx)
+	var count int
+	err := m.db.QueryRowContext(
+		ctx,
+		"SELECT COUNT(*) FROM vec_memories WHERE "+chatVectorsFilter,
+		tenantId, chatId, tenantId, chatId,
+	).Scan(&count)
+	return count, err
+}
+
+// DeleteByMemoryIds implements [vector.MemoryVectorRepository]
+func (m *MemoryRepository) DeleteByMemoryIds(ctx context.Context, memoryIds []string) (int, error) {
+	if len(memoryIds) == 0 {
+		return 0, nil
+	}
+	args := make([]any, len(memoryIds))
+	for i, id := range memoryIds {
+		args[i] = id
+	}
+	result, err := m.db.ExecContext(
+		ctx,
+		"DELETE FROM vec_memories WHERE id IN (?"+strings.Repeat(", ?", len(memoryIds)-1)+")",
+		args...,
+	)
+	if err != nil {
+		return 0, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rowsAffected), nil
+}
+
+// GetByMemoryIds implements [vector.MemoryVectorRepository]
+func (m *MemoryRepository) GetByMemoryIds(ctx context.Context, memoryIds []string) (map[string][]float32, error) {
+	vectors := make(map[string][]float32, len(memoryIds))
+	if len(memoryIds) == 0 {
+		return vectors, nil
+	}
+	args := make([]any, len(memoryIds))
+	for i, id := range memoryIds {
+		args[i] = id
+	}
+	rows, err := m.db.QueryContext(
+		ctx,
+		"SELECT id, vectors_json FROM vec_memories WHERE id IN (?"+strings.Repeat(", ?", len(memoryIds)-1)+")",
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, vectorsJSON string
+		if err := rows.Scan(&id, &vectorsJSON); err != nil {
+			return nil, err
+		}
+		var memoryVectors []float32
+		if err := json.Unmarshal([]byte(vectorsJSON), &memoryVectors); err != nil {
+			return nil, err
+		}
+		vectors[id] = memoryVectors
+	}
+	return vectors, rows.Err()
+}
+
 var _ vector.MemoryVectorRepository = (*MemoryRepository)(nil)
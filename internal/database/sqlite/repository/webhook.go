@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/sqlite"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type WebhookRepository struct {
+	*gorm.DB
+}
+
+func NewWebhookRepository() *WebhookRepository {
+	db := sqlite.GetDb()
+	return &WebhookRepository{
+		DB: db,
+	}
+}
+
+func (r *WebhookRepository) G() gorm.Interface[sqlite.Webhook] {
+	return gorm.G[sqlite.Webhook](r.DB)
+}
+
+// scoped restricts the query to the tenant carried by ctx
+func (r *WebhookRepository) scoped(ctx context.Context) gorm.ChainInterface[sqlite.Webhook] {
+	return r.G().Where("tenant_id = ?", core.TenantFromContext(ctx))
+}
+
+func webhookDbModelToSchema(w *sqlite.Webhook) *core.Webhook {
+	events := []core.MemoryEventType{}
+	for _, event := range strings.Split(w.Events, ",") {
+		if event != "" {
+			events = append(events, core.MemoryEventType(event))
+		}
+	}
+	return &core.Webhook{
+		Id:        w.ID,
+		TenantId:  w.TenantID,
+		ChatId:    w.ChatID,
+		Url:       w.Url,
+		Events:    events,
+		CreatedAt: w.CreatedAt,
+		Secret:    w.Secret,
+	}
+}
+
+// Create implements [repository.WebhookRepository]
+func (r *WebhookRepository) Create(ctx context.Context, webhook *core.Webhook) (*core.Webhook, error) {
+	var events []string
+	for _, event := range webhook.Events {
+		events = append(events, string(event))
+	}
+	dbWebhook := sqlite.Webhook{
+		TenantID:  core.TenantFromContext(ctx),
+		ChatID:    webhook.ChatId,
+		Url:       webhook.Url,
+		Secret:    webhook.Secret,
+		Events:    strings.Join(events, ","),
+		CreatedAt: webhook.CreatedAt,
+	}
+	if err := r.G().Create(ctx, &dbWebhook); err != nil {
+		return nil, err
+	}
+	return webhookDbModelToSchema(&dbWebhook), nil
+}
+
+// GetAll implements [repository.WebhookRepository]
+func (r *WebhookRepository) GetAll(ctx context.Context) ([]*core.Webhook, error) {
+	dbWebhooks, err := r.scoped(ctx).Order("created_at").Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var webhooks []*core.Webhook
+	for _, w := range dbWebhooks {
+		webhooks = append(webhooks, webhookDbModelToSchema(&w))
+	}
+	return webhooks, nil
+}
+
+// GetById implements [repository.WebhookRepository]
+func (r *WebhookRepository) GetById(ctx context.Context, id string) (*core.Webhook, error) {
+	dbWebhook, err := r.scoped(ctx).Where("id = ?", id).First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, core.WebhookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return webhookDbModelToSchema(&dbWebhook), nil
+}
+
+// GetByChatId implements [repository.WebhookRepository]
+func (r *WebhookRepository) GetByChatId(ctx context.Context, chatId string) ([]*core.Webhook, error) {
+	dbWebhooks, err := r.scoped(ctx).
+		Where("chat_id = ? OR chat_id = ''", chatId).
+		Order("created_at").
+		Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var webhooks []*core.Webhook
+	for _, w := range dbWebhooks {
+		webhooks = append(webhooks, webhookDbModelToSchema(&w))
+	}
+	return webhooks, nil
+}
+
+// Delete implements [repository.WebhookRepository]
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	rowsAffected, err := r.scoped(ctx).Where("id = ?", id).Delete(ctx)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return core.WebhookNotFound
+	}
+	return nil
+}
+
+var _ repository.WebhookRepository = (*WebhookRepository)(nil)
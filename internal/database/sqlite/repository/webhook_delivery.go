@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/database/sqlite"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type WebhookDeliveryRepository struct {
+	*gorm.DB
+}
+
+func NewWebhookDeliveryRepository() *WebhookDeliveryRepository {
+	db := sqlite.GetDb()
+	return &WebhookDeliveryRepository{
+		DB: db,
+	}
+}
+
+func (r *WebhookDeliveryRepository) G() gorm.Interface[sqlite.WebhookDelivery] {
+	return gorm.G[sqlite.WebhookDelivery](r.DB)
+}
+
+// scoped restricts the query to the tenant carried by ctx
+func (r *WebhookDeliveryRepository) scoped(ctx context.Context) gorm.ChainInterface[sqlite.WebhookDelivery] {
+	return r.G().Where("tenant_id = ?", core.TenantFromContext(ctx))
+}
+
+func webhookDeliveryDbModelToSchema(d *sqlite.WebhookDelivery) *core.WebhookDelivery {
+	return &core.WebhookDelivery{
+		Id:         d.ID,
+		WebhookId:  d.WebhookID,
+		EventId:    d.EventID,
+		EventType:  core.MemoryEventType(d.EventType),
+		Attempt:    d.Attempt,
+		StatusCode: d.StatusCode,
+		Success:    d.Success,
+		Error:      d.Error,
+		Duration:   d.Duration,
+		CreatedAt:  d.CreatedAt,
+	}
+}
+
+// Create implements [repository.WebhookDeliveryRepository]
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *core.WebhookDelivery) error {
+	dbDelivery := sqlite.WebhookDelivery{
+		TenantID:   core.TenantFromContext(ctx),
+		WebhookID:  delivery.WebhookId,
+		EventID:    delivery.EventId,
+		EventType:  string(delivery.EventType),
+		Attempt:    delivery.Attempt,
+		StatusCode: delivery.StatusCode,
+		Success:    delivery.Success,
+		Error:      delivery.Error,
+		Duration:   delivery.Duration,
+		CreatedAt:  delivery.CreatedAt,
+	}
+	if err := r.G().Create(ctx, &dbDelivery); err != nil {
+		return err
+	}
+	delivery.Id = dbDelivery.ID
+	return nil
+}
+
+// GetByWebhookId implements [repository.WebhookDeliveryRepository]
+func (r *WebhookDeliveryRepository) GetByWebhookId(
+	ctx context.Context, webhookId string, limit int, offset int,
+) ([]*core.WebhookDelivery, error) {
+	dbDeliveries, err := r.scoped(ctx).
+		Where("webhook_id = ?", webhookId).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deliveries := []*core.WebhookDelivery{}
+	for _, d := range dbDeliveries {
+		deliveries = append(deliveries, webhookDeliveryDbModelToSchema(&d))
+	}
+	return deliveries, nil
+}
+
+var _ repository.WebhookDeliveryRepository = (*WebhookDeliveryRepository)(nil)
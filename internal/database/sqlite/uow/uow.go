@@ -15,13 +15,13 @@ type SQLiteUnitOfWork[T any, C any] struct {
 
 // Do implements uow.UnitOfWork.
 func (s *SQLiteUnitOfWork[T, C]) Do(
-	ctx context.Context, fn func(repos repository.AllRepositories) (T, error),
+	ctx context.Context, fn func(ctx context.Context, repos repository.AllRepositories) (T, error),
 ) (T, error) {
 	var result T
 
 	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var err error
-		result, err = fn(s.Repositories(tx))
+		result, err = fn(ctx, s.Repositories(tx))
 		return err
 	})
 
@@ -38,6 +38,8 @@ func (m *SQLiteUnitOfWork[T, C]) Repositories(tx any) repository.AllRepositories
 		Chat:            sqliteRepository.ChatRepositoryWithTransaction(gormTx),
 		ShortTermMemory: sqliteRepository.ShortTermMemoryRepositoryWithTransaction(gormTx),
 		LongTermMemory:  sqliteRepository.LongTermMemoryRepositoryWithTransaction(gormTx),
+		Message:         sqliteRepository.MessageRepositoryWithTransaction(gormTx),
+		MemoryMerge:     sqliteRepository.MemoryMergeRepositoryWithTransaction(gormTx),
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"github.com/Mateus-Lacerda/better-mem/internal/config"
 	"github.com/Mateus-Lacerda/better-mem/pkg/core"
 	"context"
+	"fmt"
 	"log/slog"
 	"sync"
 	"time"
@@ -92,3 +93,59 @@ func Embed(message string) ([]float32, error) {
 
 	return response.Embedding, nil
 }
+
+// Embeds every message in a single call,
+// the embeddings are in the same order as the messages
+func EmbedBatch(messages []string) ([][]float32, error) {
+	if len(messages) == 0 {
+		return nil, nil
+	}
+	predictionClient := NewPredictionClient(GetPredictClient().client)
+	ctx, cancel := context.WithTimeout(context.Background(), predictionTimeout*time.Second)
+
+	defer cancel()
+
+	response, err := predictionClient.EmbedBatch(ctx, &EmbedBatchRequest{Messages: messages})
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Embeddings) != len(messages) {
+		return nil, fmt.Errorf(
+			"embedder returned %d embeddings for %d messages",
+			len(response.Embeddings), len(messages),
+		)
+	}
+	embeddings := make([][]float32, len(messages))
+	for i, embedding := range response.Embeddings {
+		embeddings[i] = embedding.Embedding
+	}
+
+	return embeddings, nil
+}
+
+// Scores each candidate against the query with the cross-encoder,
+// the scores are in the same order as the candidates
+func Rerank(query string, candidates []string) ([]float32, error) {
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	predictionClient := NewPredictionClient(GetPredictClient().client)
+	ctx, cancel := context.WithTimeout(context.Background(), predictionTimeout*time.Second)
+
+	defer cancel()
+
+	response, err := predictionClient.Rerank(ctx, &RerankRequest{
+		Query: query, Candidates: candidates,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Scores) != len(candidates) {
+		return nil, fmt.Errorf(
+			"reranker returned %d scores for %d candidates",
+			len(response.Scores), len(candidates),
+		)
+	}
+
+	return response.Scores, nil
+}
@@ -213,6 +213,192 @@ func (x *EmbedResponse) GetEmbedding() []float32 {
 	return nil
 }
 
+type EmbedBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []string               `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmbedBatchRequest) Reset() {
+	*x = EmbedBatchRequest{}
+	mi := &file_prediction_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmbedBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmbedBatchRequest) ProtoMessage() {}
+
+func (x *EmbedBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prediction_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmbedBatchRequest.ProtoReflect.Descriptor instead.
+func (*EmbedBatchRequest) Descriptor() ([]byte, []int) {
+	return file_prediction_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EmbedBatchRequest) GetMessages() []string {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type EmbedBatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One embedding per message, in the same order.
+	Embeddings    []*EmbedResponse `protobuf:"bytes,1,rep,name=embeddings,proto3" json:"embeddings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmbedBatchResponse) Reset() {
+	*x = EmbedBatchResponse{}
+	mi := &file_prediction_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmbedBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmbedBatchResponse) ProtoMessage() {}
+
+func (x *EmbedBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prediction_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmbedBatchResponse.ProtoReflect.Descriptor instead.
+func (*EmbedBatchResponse) Descriptor() ([]byte, []int) {
+	return file_prediction_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *EmbedBatchResponse) GetEmbeddings() []*EmbedResponse {
+	if x != nil {
+		return x.Embeddings
+	}
+	return nil
+}
+
+type RerankRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Candidates    []string               `protobuf:"bytes,2,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RerankRequest) Reset() {
+	*x = RerankRequest{}
+	mi := &file_prediction_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RerankRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RerankRequest) ProtoMessage() {}
+
+func (x *RerankRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prediction_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RerankRequest.ProtoReflect.Descriptor instead.
+func (*RerankRequest) Descriptor() ([]byte, []int) {
+	return file_prediction_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RerankRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *RerankRequest) GetCandidates() []string {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+type RerankResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One score per candidate, in the same order.
+	Scores        []float32 `protobuf:"fixed32,1,rep,packed,name=scores,proto3" json:"scores,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RerankResponse) Reset() {
+	*x = RerankResponse{}
+	mi := &file_prediction_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RerankResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RerankResponse) ProtoMessage() {}
+
+func (x *RerankResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prediction_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RerankResponse.ProtoReflect.Descriptor instead.
+func (*RerankResponse) Descriptor() ([]byte, []int) {
+	return file_prediction_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RerankResponse) GetScores() []float32 {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
 var File_prediction_proto protoreflect.FileDescriptor
 
 const file_prediction_proto_rawDesc = "" +
@@ -227,11 +413,27 @@ const file_prediction_proto_rawDesc = "" +
 	"\fEmbedRequest\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\"-\n" +
 	"\rEmbedResponse\x12\x1c\n" +
-	"\tembedding\x18\x01 \x03(\x02R\tembedding2l\n" +
+	"\tembedding\x18\x01 \x03(\x02R\tembedding\"/\n" +
+	"\x11EmbedBatchRequest\x12\x1a\n" +
+	"\bmessages\x18\x01 \x03(\tR\bmessages\"D\n" +
+	"\x12EmbedBatchResponse\x12.\n" +
+	"\n" +
+	"embeddings\x18\x01 \x03(\v2\x0e.EmbedResponseR\n" +
+	"embeddings\"E\n" +
+	"\rRerankRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1e\n" +
+	"\n" +
+	"candidates\x18\x02 \x03(\tR\n" +
+	"candidates\"(\n" +
+	"\x0eRerankResponse\x12\x16\n" +
+	"\x06scores\x18\x01 \x03(\x02R\x06scores2\xd2\x01\n" +
 	"\n" +
 	"Prediction\x124\n" +
 	"\aPredict\x12\x12.PredictionRequest\x1a\x13.PredictionResponse\"\x00\x12(\n" +
-	"\x05Embed\x12\r.EmbedRequest\x1a\x0e.EmbedResponse\"\x00B\x1cZ\x1abetter-mem/internal/protosb\x06proto3"
+	"\x05Embed\x12\r.EmbedRequest\x1a\x0e.EmbedResponse\"\x00\x127\n" +
+	"\n" +
+	"EmbedBatch\x12\x12.EmbedBatchRequest\x1a\x13.EmbedBatchResponse\"\x00\x12+\n" +
+	"\x06Rerank\x12\x0e.RerankRequest\x1a\x0f.RerankResponse\"\x00B\x1cZ\x1abetter-mem/internal/protosb\x06proto3"
 
 var (
 	file_prediction_proto_rawDescOnce sync.Once
@@ -245,23 +447,32 @@ func file_prediction_proto_rawDescGZIP() []byte {
 	return file_prediction_proto_rawDescData
 }
 
-var file_prediction_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_prediction_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_prediction_proto_goTypes = []any{
 	(*PredictionRequest)(nil),  // 0: PredictionRequest
 	(*PredictionResponse)(nil), // 1: PredictionResponse
 	(*EmbedRequest)(nil),       // 2: EmbedRequest
 	(*EmbedResponse)(nil),      // 3: EmbedResponse
+	(*EmbedBatchRequest)(nil),  // 4: EmbedBatchRequest
+	(*EmbedBatchResponse)(nil), // 5: EmbedBatchResponse
+	(*RerankRequest)(nil),      // 6: RerankRequest
+	(*RerankResponse)(nil),     // 7: RerankResponse
 }
 var file_prediction_proto_depIdxs = []int32{
-	0, // 0: Prediction.Predict:input_type -> PredictionRequest
-	2, // 1: Prediction.Embed:input_type -> EmbedRequest
-	1, // 2: Prediction.Predict:output_type -> PredictionResponse
-	3, // 3: Prediction.Embed:output_type -> EmbedResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	3, // 0: EmbedBatchResponse.embeddings:type_name -> EmbedResponse
+	0, // 1: Prediction.Predict:input_type -> PredictionRequest
+	2, // 2: Prediction.Embed:input_type -> EmbedRequest
+	4, // 3: Prediction.EmbedBatch:input_type -> EmbedBatchRequest
+	6, // 4: Prediction.Rerank:input_type -> RerankRequest
+	1, // 5: Prediction.Predict:output_type -> PredictionResponse
+	3, // 6: Prediction.Embed:output_type -> EmbedResponse
+	5, // 7: Prediction.EmbedBatch:output_type -> EmbedBatchResponse
+	7, // 8: Prediction.Rerank:output_type -> RerankResponse
+	5, // [5:9] is the sub-list for method output_type
+	1, // [1:5] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_prediction_proto_init() }
@@ -275,7 +486,7 @@ func file_prediction_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_prediction_proto_rawDesc), len(file_prediction_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
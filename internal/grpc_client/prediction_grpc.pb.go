@@ -19,8 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Prediction_Predict_FullMethodName = "/Prediction/Predict"
-	Prediction_Embed_FullMethodName   = "/Prediction/Embed"
+	Prediction_Predict_FullMethodName    = "/Prediction/Predict"
+	Prediction_Embed_FullMethodName      = "/Prediction/Embed"
+	Prediction_EmbedBatch_FullMethodName = "/Prediction/EmbedBatch"
+	Prediction_Rerank_FullMethodName     = "/Prediction/Rerank"
 )
 
 // PredictionClient is the client API for Prediction service.
@@ -31,6 +33,10 @@ type PredictionClient interface {
 	Predict(ctx context.Context, in *PredictionRequest, opts ...grpc.CallOption) (*PredictionResponse, error)
 	// Embeds a given message.
 	Embed(ctx context.Context, in *EmbedRequest, opts ...grpc.CallOption) (*EmbedResponse, error)
+	// Embeds several messages in one call.
+	EmbedBatch(ctx context.Context, in *EmbedBatchRequest, opts ...grpc.CallOption) (*EmbedBatchResponse, error)
+	// Scores how relevant each candidate is to the query with a cross-encoder.
+	Rerank(ctx context.Context, in *RerankRequest, opts ...grpc.CallOption) (*RerankResponse, error)
 }
 
 type predictionClient struct {
@@ -61,6 +67,26 @@ func (c *predictionClient) Embed(ctx context.Context, in *EmbedRequest, opts ...
 	return out, nil
 }
 
+func (c *predictionClient) EmbedBatch(ctx context.Context, in *EmbedBatchRequest, opts ...grpc.CallOption) (*EmbedBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmbedBatchResponse)
+	err := c.cc.Invoke(ctx, Prediction_EmbedBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *predictionClient) Rerank(ctx context.Context, in *RerankRequest, opts ...grpc.CallOption) (*RerankResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RerankResponse)
+	err := c.cc.Invoke(ctx, Prediction_Rerank_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PredictionServer is the server API for Prediction service.
 // All implementations must embed UnimplementedPredictionServer
 // for forward compatibility.
@@ -69,6 +95,10 @@ type PredictionServer interface {
 	Predict(context.Context, *PredictionRequest) (*PredictionResponse, error)
 	// Embeds a given message.
 	Embed(context.Context, *EmbedRequest) (*EmbedResponse, error)
+	// Embeds several messages in one call.
+	EmbedBatch(context.Context, *EmbedBatchRequest) (*EmbedBatchResponse, error)
+	// Scores how relevant each candidate is to the query with a cross-encoder.
+	Rerank(context.Context, *RerankRequest) (*RerankResponse, error)
 	mustEmbedUnimplementedPredictionServer()
 }
 
@@ -85,6 +115,12 @@ func (UnimplementedPredictionServer) Predict(context.Context, *PredictionRequest
 func (UnimplementedPredictionServer) Embed(context.Context, *EmbedRequest) (*EmbedResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Embed not implemented")
 }
+func (UnimplementedPredictionServer) EmbedBatch(context.Context, *EmbedBatchRequest) (*EmbedBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EmbedBatch not implemented")
+}
+func (UnimplementedPredictionServer) Rerank(context.Context, *RerankRequest) (*RerankResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rerank not implemented")
+}
 func (UnimplementedPredictionServer) mustEmbedUnimplementedPredictionServer() {}
 func (UnimplementedPredictionServer) testEmbeddedByValue()                    {}
 
@@ -142,6 +178,42 @@ func _Prediction_Embed_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Prediction_EmbedBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmbedBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PredictionServer).EmbedBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Prediction_EmbedBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PredictionServer).EmbedBatch(ctx, req.(*EmbedBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Prediction_Rerank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RerankRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PredictionServer).Rerank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Prediction_Rerank_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PredictionServer).Rerank(ctx, req.(*RerankRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Prediction_ServiceDesc is the grpc.ServiceDesc for Prediction service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -157,6 +229,14 @@ var Prediction_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Embed",
 			Handler:    _Prediction_Embed_Handler,
 		},
+		{
+			MethodName: "EmbedBatch",
+			Handler:    _Prediction_EmbedBatch_Handler,
+		},
+		{
+			MethodName: "Rerank",
+			Handler:    _Prediction_Rerank_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "prediction.proto",
@@ -6,3 +6,20 @@ Your task is to enhance this message's content to make it more relevant and self
 Do **NOT** change the meaning or add content that is not on the message.
 Expected Ouput:
 Solely the enhanced version of the message, without any confirmation messages.`
+
+const QueryRewritePrompt string = `The following is the end of a conversation:
+%v
+The last message is:
+%v
+Your task is to rewrite the last message as a standalone search query to find what is remembered about the conversation.
+Resolve pronouns and references such as "the other one" using the conversation.
+Do **NOT** answer the message or add content that is not on the conversation.
+Expected Ouput:
+Solely the search query, in a single line, without any confirmation messages.`
+
+const HypotheticalMemoryPrompt string = `The following question was asked about a person:
+%v
+Your task is to write a short memory about the person that would answer it, as a single declarative sentence.
+The memory does not need to be true, it is only used to search for similar memories.
+Expected Ouput:
+Solely the memory, in a single line, without any confirmation messages.`
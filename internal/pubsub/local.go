@@ -0,0 +1,61 @@
+//go:build local
+
+package pubsub
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
+	"context"
+	"sync"
+	"time"
+)
+
+// The api and the worker are separate processes sharing the database,
+// events published in this process wake its subscribers right away and
+// the rest are found by polling the event log
+var (
+	lock        = &sync.Mutex{}
+	subscribers = map[string]map[chan struct{}]struct{}{}
+)
+
+// Announces that the event with the cursor was written to the log of the chat
+func Publish(ctx context.Context, tenantId string, chatId string, cursor int64) error {
+	lock.Lock()
+	defer lock.Unlock()
+	for wake := range subscribers[channelName(tenantId, chatId)] {
+		wakeUp(wake)
+	}
+	return nil
+}
+
+// Returns a channel that receives whenever an event of the chat is
+// published and every EVENTS_POLL_INTERVAL_MS, it is closed once ctx is done
+func Subscribe(ctx context.Context, tenantId string, chatId string) (<-chan struct{}, error) {
+	channel := channelName(tenantId, chatId)
+	wake := make(chan struct{}, 1)
+	lock.Lock()
+	if subscribers[channel] == nil {
+		subscribers[channel] = map[chan struct{}]struct{}{}
+	}
+	subscribers[channel][wake] = struct{}{}
+	lock.Unlock()
+	go func() {
+		ticker := time.NewTicker(time.Duration(config.Events.PollInterval) * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				lock.Lock()
+				delete(subscribers[channel], wake)
+				if len(subscribers[channel]) == 0 {
+					delete(subscribers, channel)
+				}
+				close(wake)
+				lock.Unlock()
+				return
+			case <-ticker.C:
+				wakeUp(wake)
+			}
+		}
+	}()
+	return wake, nil
+}
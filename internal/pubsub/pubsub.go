@@ -0,0 +1,19 @@
+// Package pubsub wakes the event streams of a chat up when one of its
+// memory events is written to the event log. It carries no events, the
+// streams read them from the log, so a missed notification only delays them
+package pubsub
+
+import "fmt"
+
+// The channel the events of a chat are announced on
+func channelName(tenantId string, chatId string) string {
+	return fmt.Sprintf("better-mem:events:%s:%s", tenantId, chatId)
+}
+
+// Wakes the subscriber up, unless it already has a pending wake up
+func wakeUp(wake chan<- struct{}) {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,52 @@
+//go:build server
+
+package pubsub
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var getClient = sync.OnceValue(func() *redis.Client {
+	return redis.NewClient(&redis.Options{Addr: config.Database.RedisAddress})
+})
+
+// Announces that the event with the cursor was written to the log of the chat
+func Publish(ctx context.Context, tenantId string, chatId string, cursor int64) error {
+	return getClient().Publish(
+		ctx, channelName(tenantId, chatId), strconv.FormatInt(cursor, 10),
+	).Err()
+}
+
+// Returns a channel that receives whenever an event of the chat is
+// published, it is closed once ctx is done
+func Subscribe(ctx context.Context, tenantId string, chatId string) (<-chan struct{}, error) {
+	subscription := getClient().Subscribe(ctx, channelName(tenantId, chatId))
+	// Waits for the subscription, so nothing published after returning is missed
+	if _, err := subscription.Receive(ctx); err != nil {
+		subscription.Close()
+		return nil, err
+	}
+	wake := make(chan struct{}, 1)
+	go func() {
+		defer close(wake)
+		defer subscription.Close()
+		messages := subscription.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-messages:
+				if !ok {
+					return
+				}
+				wakeUp(wake)
+			}
+		}
+	}()
+	return wake, nil
+}
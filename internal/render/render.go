@@ -0,0 +1,152 @@
+package render
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+const (
+	// Markdown list, the format the demo prompt always used
+	BulletTemplate = "bullet"
+	// Tagged memories, easier for models trained on XML prompts to tell apart
+	XmlTemplate = "xml"
+	// The memories as a JSON array
+	JsonTemplate = "json"
+)
+
+// What a template is executed with
+type Data struct {
+	// Who the memories are about, e.g. the user name, may be empty
+	Subject  string
+	Memories []*core.ScoredMemory
+}
+
+var funcs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"xml": func(s string) (string, error) {
+		var buffer bytes.Buffer
+		err := xml.EscapeText(&buffer, []byte(s))
+		return buffer.String(), err
+	},
+	"memoryType": func(memoryType core.MemoryTypeEnum) string {
+		if memoryType == core.LongTerm {
+			return "long_term"
+		}
+		return "short_term"
+	},
+	"trim": strings.TrimSpace,
+}
+
+const bullet = `{{if .Memories}}These are memories you have from past conversations{{with .Subject}} with {{.}}{{end}}:
+{{range .Memories}}- {{.Text}} (relevance: {{printf "%.2f" .Score}}, created at: {{.CreatedAt.Format "2006-01-02 15:04"}})
+{{if .RelatedContext}}  Related context:
+{{range .RelatedContext}}  - From: {{.User}}
+    {{.Context}}
+{{end}}{{end}}{{end}}{{end}}`
+
+const xmlMemories = `{{if .Memories}}<memories{{with .Subject}} subject="{{xml .}}"{{end}}>
+{{range .Memories}}  <memory type="{{memoryType .MemoryType}}" relevance="{{printf "%.2f" .Score}}" created_at="{{.CreatedAt.Format "2006-01-02T15:04:05Z07:00"}}">
+    <text>{{xml .Text}}</text>
+{{range .RelatedContext}}    <related_context from="{{xml .User}}">{{xml .Context}}</related_context>
+{{end}}  </memory>
+{{end}}</memories>
+{{end}}`
+
+const jsonMemories = `{{json .Memories}}`
+
+var (
+	mu        sync.RWMutex
+	templates = map[string]*template.Template{}
+)
+
+func init() {
+	for name, text := range map[string]string{
+		BulletTemplate: bullet,
+		XmlTemplate:    xmlMemories,
+		JsonTemplate:   jsonMemories,
+	} {
+		if err := Register(name, text); err != nil {
+			panic(err)
+		}
+	}
+	if config.Context.TemplatesDir != "" {
+		if err := RegisterDir(config.Context.TemplatesDir); err != nil {
+			slog.Error("Error loading context templates", "dir", config.Context.TemplatesDir, "error", err)
+		}
+	}
+}
+
+// Parses a template and makes it available under the name, replacing any
+// template with the same name. Besides the text/template builtins, templates
+// can use json, xml (escapes text), memoryType and trim
+func Register(name, text string) error {
+	parsed, err := template.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("parsing template %s: %w", name, err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	templates[name] = parsed
+	return nil
+}
+
+// Registers every *.tmpl file of a directory under its name without the extension
+func RegisterDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		text, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(filepath.Base(path), ".tmpl")
+		if err := Register(name, string(text)); err != nil {
+			return err
+		}
+		slog.Info("Registered context template", "name", name, "path", path)
+	}
+	return nil
+}
+
+// A registered template
+type Template struct {
+	parsed *template.Template
+}
+
+// Returns the named template, an empty name picks the configured default
+func Lookup(name string) (*Template, error) {
+	if name == "" {
+		name = config.Context.DefaultTemplate
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	parsed, ok := templates[name]
+	if !ok {
+		return nil, core.TemplateNotFound
+	}
+	return &Template{parsed: parsed}, nil
+}
+
+// Executes the template over the memories
+func (t *Template) Render(data Data) (string, error) {
+	var buffer strings.Builder
+	if err := t.parsed.Execute(&buffer, data); err != nil {
+		return "", fmt.Errorf("rendering template %s: %w", t.parsed.Name(), err)
+	}
+	return buffer.String(), nil
+}
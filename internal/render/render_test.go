@@ -0,0 +1,184 @@
+package render
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var createdAt = time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+
+func memories() []*core.ScoredMemory {
+	return []*core.ScoredMemory{
+		{
+			Id:         "1",
+			Text:       "Likes <b>bold</b> & italics",
+			Score:      0.876,
+			CreatedAt:  createdAt,
+			MemoryType: core.LongTerm,
+			RelatedContext: []core.MessageRelatedContext{
+				{User: "ana", Context: "Talking about fonts"},
+			},
+		},
+		{
+			Id:         "2",
+			Text:       "Lives in Recife",
+			Score:      0.5,
+			CreatedAt:  createdAt,
+			MemoryType: core.ShortTerm,
+		},
+	}
+}
+
+func TestBuiltinTemplates(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		data     Data
+		want     string
+	}{
+		{
+			name:     "bullet",
+			template: BulletTemplate,
+			data:     Data{Subject: "Ana", Memories: memories()},
+			want: "These are memories you have from past conversations with Ana:\n" +
+				"- Likes <b>bold</b> & italics (relevance: 0.88, created at: 2025-03-04 05:06)\n" +
+				"  Related context:\n" +
+				"  - From: ana\n" +
+				"    Talking about fonts\n" +
+				"- Lives in Recife (relevance: 0.50, created at: 2025-03-04 05:06)\n",
+		},
+		{
+			name:     "bullet without subject",
+			template: BulletTemplate,
+			data:     Data{Memories: memories()[1:]},
+			want: "These are memories you have from past conversations:\n" +
+				"- Lives in Recife (relevance: 0.50, created at: 2025-03-04 05:06)\n",
+		},
+		{
+			name:     "bullet without memories",
+			template: BulletTemplate,
+			data:     Data{Subject: "Ana"},
+			want:     "",
+		},
+		{
+			name:     "xml escapes the text",
+			template: XmlTemplate,
+			data:     Data{Subject: "Ana & co", Memories: memories()},
+			want: "<memories subject=\"Ana &amp; co\">\n" +
+				"  <memory type=\"long_term\" relevance=\"0.88\" created_at=\"2025-03-04T05:06:07Z\">\n" +
+				"    <text>Likes &lt;b&gt;bold&lt;/b&gt; &amp; italics</text>\n" +
+				"    <related_context from=\"ana\">Talking about fonts</related_context>\n" +
+				"  </memory>\n" +
+				"  <memory type=\"short_term\" relevance=\"0.50\" created_at=\"2025-03-04T05:06:07Z\">\n" +
+				"    <text>Lives in Recife</text>\n" +
+				"  </memory>\n" +
+				"</memories>\n",
+		},
+		{
+			name:     "xml without memories",
+			template: XmlTemplate,
+			data:     Data{},
+			want:     "",
+		},
+		{
+			name:     "json",
+			template: JsonTemplate,
+			data:     Data{Memories: memories()[1:]},
+			want: `[{"id":"2","text":"Lives in Recife","score":0.5,` +
+				`"created_at":"2025-03-04T05:06:07Z","memory_type":1,"related_context":null}]`,
+		},
+		{
+			name:     "json without memories",
+			template: JsonTemplate,
+			data:     Data{},
+			want:     "null",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := Lookup(tt.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := template.Render(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupUnknown(t *testing.T) {
+	if _, err := Lookup("yaml"); !errors.Is(err, core.TemplateNotFound) {
+		t.Errorf("got error %v, want %v", err, core.TemplateNotFound)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+		want    string
+	}{
+		{
+			name: "funcs are available",
+			text: `{{range .Memories}}[{{memoryType .MemoryType}}] {{trim .Text}};{{end}}`,
+			want: "[long_term] Likes <b>bold</b> & italics;[short_term] Lives in Recife;",
+		},
+		{
+			name:    "invalid template",
+			text:    `{{range .Memories}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Register("test", tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			template, err := Lookup("test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := template.Render(Data{Memories: memories()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "short.tmpl"), []byte(`{{len .Memories}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ignored.txt"), []byte(`{{`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	template, err := Lookup("short")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := template.Render(Data{Memories: memories()}); err != nil || got != "2" {
+		t.Errorf("got %q, %v, want \"2\"", got, err)
+	}
+}
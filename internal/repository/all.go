@@ -4,4 +4,6 @@ type AllRepositories struct {
 	Chat            ChatRepository
 	ShortTermMemory ShortTermMemoryRepository
 	LongTermMemory  LongTermMemoryRepository
+	Message         MessageRepository
+	MemoryMerge     MemoryMergeRepository
 }
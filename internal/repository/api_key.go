@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"context"
+)
+
+type ApiKeyRepository interface {
+	Create(ctx context.Context, apiKey *core.ApiKeyModel) (*core.ApiKey, error)
+	GetAll(ctx context.Context) ([]*core.ApiKey, error)
+	GetByHash(ctx context.Context, hash string) (*core.ApiKey, error)
+	Revoke(ctx context.Context, id string) error
+}
@@ -9,4 +9,6 @@ type ChatRepository interface {
 	Create(ctx context.Context, chat *core.NewChat) error
 	GetAll(ctx context.Context) ([]*core.Chat, error)
 	GetByExternalID(ctx context.Context, externalID string) (*string, error)
+	GetById(ctx context.Context, id string) (*core.Chat, error)
+	Count(ctx context.Context) (int, error)
 }
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"context"
+)
+
+// Permanently removes everything stored for a chat, vectors and
+// queued jobs live elsewhere and are removed on their own
+type ChatPurgeRepository interface {
+	// Deletes the chat with its memories, related context, messages,
+	// merges, events and webhooks, returns how much was deleted
+	Purge(ctx context.Context, chatId string) (*core.ChatDataCounts, error)
+	// Counts what is stored for the chat, without vectors and jobs
+	Count(ctx context.Context, chatId string) (*core.ChatDataCounts, error)
+}
@@ -14,8 +14,12 @@ type LongTermMemoryRepository interface {
 		offset int,
 	) (*core.LongTermMemoryArray, error)
 	GetById(ctx context.Context, chatId string, memoryId string) (*core.LongTermMemory, error)
-	GetScored(ctx context.Context, chatId string, memoriesIds []string) ([]*core.ScoredMemory, error)
+	GetByIds(ctx context.Context, chatId string, memoriesIds []string) ([]*core.LongTermMemory, error)
+	Update(ctx context.Context, chatId string, memoryId string, memory string, relatedContext []core.MessageRelatedContext) (*core.LongTermMemory, error)
 	RegisterUsage(ctx context.Context, chatId string, memoryId string) error
 	Deactivate(ctx context.Context, chatId string, memoryId string) error
 	DeactivateAll(ctx context.Context, chatId string) error
+	CountActive(ctx context.Context) (int, error)
+	// Returns every memory of the chat, active or not, oldest first
+	GetAllByChatId(ctx context.Context, chatId string) ([]*core.LongTermMemory, error)
 }
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"context"
+)
+
+type MemoryEventRepository interface {
+	// Appends the event to the log of the chat and sets its cursor
+	Create(ctx context.Context, chatId string, event *core.MemoryEvent) error
+	// Returns the events of the chat after the cursor, the oldest first
+	GetSince(ctx context.Context, chatId string, cursor int64, limit int) ([]*core.MemoryEvent, error)
+	// Returns the cursor of the latest event of the chat, 0 when there is none
+	LastCursor(ctx context.Context, chatId string) (int64, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"context"
+)
+
+// Lexical search over the memory text, for what embeddings
+// tend to miss, like names, numbers and product codes
+type MemoryKeywordRepository interface {
+	// Returns the best matches first, the score is only
+	// comparable between results of the same backend
+	Search(ctx context.Context, chatId string, query string, limit int) ([]core.ScoredMemoryVector, error)
+}
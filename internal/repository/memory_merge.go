@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"context"
+)
+
+type MemoryMergeRepository interface {
+	Create(ctx context.Context, merge *core.MemoryMerge) (*core.MemoryMerge, error)
+	GetById(ctx context.Context, memoryId string, mergeId string) (*core.MemoryMerge, error)
+	GetByMemoryId(ctx context.Context, memoryId string) ([]*core.MemoryMerge, error)
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"context"
+	"time"
+)
+
+// Permanently deletes the memories that stayed inactive past the
+// retention period, vectors live elsewhere and are deleted on their own
+type MemoryRetentionRepository interface {
+	// Stamps the inactive memories deactivated before the time was
+	// tracked, their retention starts now
+	MarkDeactivated(ctx context.Context, at time.Time) (int, error)
+	// Returns the ids of the memories of the type deactivated before
+	// the cutoff, at most limit of them
+	GetExpired(ctx context.Context, memoryType core.MemoryTypeEnum, cutoff time.Time, limit int) ([]string, error)
+	// Deletes the memories along with their merges
+	Delete(ctx context.Context, memoryType core.MemoryTypeEnum, ids []string) (*core.RetentionPurgeStats, error)
+	// Deletes the related context no longer attached to anything,
+	// across every tenant
+	DeleteOrphanedRelatedContext(ctx context.Context) (int, error)
+}
+
+// The log of the retention purges, shared by every tenant
+type RetentionRunRepository interface {
+	Create(ctx context.Context, run *core.RetentionRun) error
+	// Returns the latest runs first
+	GetAll(ctx context.Context, limit int, offset int) ([]*core.RetentionRun, error)
+}
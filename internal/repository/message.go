@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"context"
+)
+
+type MessageRepository interface {
+	Create(ctx context.Context, message *core.Message) error
+	GetById(ctx context.Context, id string) (*core.Message, error)
+	GetByChatId(ctx context.Context, chatId string, limit int, offset int) (*core.MessageArray, error)
+	// Returns the messages that produced or were merged into the memory
+	GetByMemoryId(ctx context.Context, memoryId string) ([]*core.Message, error)
+	// Points the messages of a memory to another one, used when it is promoted
+	ReassignMemory(ctx context.Context, memoryId string, newMemoryId string) error
+	// Marks the message as being classified and counts the attempt
+	StartAttempt(ctx context.Context, id string) error
+	SetResult(ctx context.Context, id string, result *core.MessageTaskResult) error
+}
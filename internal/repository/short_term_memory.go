@@ -15,11 +15,15 @@ type ShortTermMemoryRepository interface {
 		offset int,
 	) (*core.ShortTermMemoryArray, error)
 	GetById(ctx context.Context, chatId string, memoryId string) (*core.ShortTermMemory, error)
-	GetScored(ctx context.Context, chatId string, memoriesIds []string) ([]*core.ScoredMemory, error)
+	GetByIds(ctx context.Context, chatId string, memoriesIds []string) ([]*core.ShortTermMemory, error)
+	Update(ctx context.Context, chatId string, memoryId string, memory string, relatedContext []core.MessageRelatedContext) (*core.ShortTermMemory, error)
 	RegisterUsage(ctx context.Context, chatId string, memoryId string) error
 	Deactivate(ctx context.Context, chatId string, memoryId string) error
 	Merge(ctx context.Context, chatId string, memoryId string, otherMemory string, otherMemoryRelatedContext []core.MessageRelatedContext) (*core.ShortTermMemory, error)
 	GetElligibleForDeactivation(ctx context.Context, chatId string, window time.Duration, minimalRelevance int) ([]*core.ShortTermMemory, error)
 	GetElligibleForPromotion(ctx context.Context, chatId string, minimalRelevance int) ([]*core.ShortTermMemory, error)
 	DeactivateAll(ctx context.Context, chatId string) error
+	CountActive(ctx context.Context) (int, error)
+	// Returns every memory of the chat, active or not, oldest first
+	GetAllByChatId(ctx context.Context, chatId string) ([]*core.ShortTermMemory, error)
 }
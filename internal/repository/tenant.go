@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"context"
+)
+
+type TenantRepository interface {
+	Create(ctx context.Context, tenant *core.Tenant) (*core.Tenant, error)
+	GetAll(ctx context.Context) ([]*core.Tenant, error)
+	GetById(ctx context.Context, id string) (*core.Tenant, error)
+	UpdateQuota(ctx context.Context, id string, quota core.TenantQuota) (*core.Tenant, error)
+}
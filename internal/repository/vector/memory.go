@@ -13,6 +13,12 @@ type MemoryVectorRepository interface {
 		memoryType core.MemoryTypeEnum,
 		memoryId string,
 	) error
+	Update(
+		ctx context.Context,
+		chatId string,
+		vectors []float32,
+		memoryId string,
+	) error
 	Search(
 		ctx context.Context,
 		chatId string,
@@ -22,4 +28,14 @@ type MemoryVectorRepository interface {
 	) (*[]core.ScoredMemoryVector, error)
 	Deactivate(ctx context.Context, chatId string, id string) error
 	DeactivateAll(ctx context.Context, chatId string) error
+	// Permanently deletes the vectors of the chat, returns how many
+	// were deleted
+	DeleteAll(ctx context.Context, chatId string) (int, error)
+	Count(ctx context.Context, chatId string) (int, error)
+	// Permanently deletes the vectors of the memories, returns how many
+	// were deleted
+	DeleteByMemoryIds(ctx context.Context, memoryIds []string) (int, error)
+	// Returns the vectors of the memories by memory id, the memories
+	// without one are left out
+	GetByMemoryIds(ctx context.Context, memoryIds []string) (map[string][]float32, error)
 }
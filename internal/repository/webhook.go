@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"context"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *core.Webhook) (*core.Webhook, error)
+	GetAll(ctx context.Context) ([]*core.Webhook, error)
+	GetById(ctx context.Context, id string) (*core.Webhook, error)
+	// Returns the webhooks of the chat and the ones listening to every chat
+	GetByChatId(ctx context.Context, chatId string) ([]*core.Webhook, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *core.WebhookDelivery) error
+	// Returns the deliveries of the webhook, the latest first
+	GetByWebhookId(ctx context.Context, webhookId string, limit int, offset int) ([]*core.WebhookDelivery, error)
+}
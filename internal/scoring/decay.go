@@ -0,0 +1,58 @@
+package scoring
+
+import (
+	"math"
+	"time"
+)
+
+// Recency that halves every halfLifeHours, one for a brand new memory
+func decay(createdAt time.Time, now time.Time, halfLifeHours float32) float32 {
+	age := max(ageHours(createdAt, now), 0)
+	return float32(math.Exp2(-age / float64(halfLifeHours)))
+}
+
+// Scores the similarity decayed by the age of the memory,
+// so an old memory needs a closer match to beat a recent one
+type HalfLife struct {
+	HalfLifeHours float32
+}
+
+func (h *HalfLife) Score(candidates []Candidate, now time.Time) []Result {
+	usage := usageScores(candidates)
+	results := make([]Result, len(candidates))
+	for i, candidate := range candidates {
+		recency := decay(candidate.CreatedAt, now, h.HalfLifeHours)
+		results[i] = Result{
+			Score:   candidate.Similarity * recency,
+			Recency: recency,
+			Usage:   usage[i],
+		}
+	}
+	return results
+}
+
+// Blends similarity, half life recency and usage by their weights
+type Weighted struct {
+	SimilarityWeight float32
+	RecencyWeight    float32
+	UsageWeight      float32
+	HalfLifeHours    float32
+}
+
+func (w *Weighted) Score(candidates []Candidate, now time.Time) []Result {
+	usage := usageScores(candidates)
+	total := w.SimilarityWeight + w.RecencyWeight + w.UsageWeight
+	results := make([]Result, len(candidates))
+	for i, candidate := range candidates {
+		recency := decay(candidate.CreatedAt, now, w.HalfLifeHours)
+		score := w.SimilarityWeight*candidate.Similarity +
+			w.RecencyWeight*recency +
+			w.UsageWeight*usage[i]
+		results[i] = Result{
+			Score:   score / total,
+			Recency: recency,
+			Usage:   usage[i],
+		}
+	}
+	return results
+}
@@ -0,0 +1,48 @@
+package scoring
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"time"
+)
+
+// Averages usage and recency, ignoring the similarity. Short term memories
+// use the inverse of their age in hours as recency, long term memories
+// their age over the oldest one, and usage is relative to the batch
+type Relevancy struct{}
+
+func (r *Relevancy) Score(candidates []Candidate, now time.Time) []Result {
+	// Maxima are taken per memory type, like each store used to
+	maxAccessCount, maxMergeCount := 0, 0
+	maxLongTermAccessCount, maxAge := 0, 0
+	for _, candidate := range candidates {
+		switch candidate.MemoryType {
+		case core.ShortTerm:
+			maxAccessCount = max(maxAccessCount, candidate.AccessCount)
+			maxMergeCount = max(maxMergeCount, candidate.MergeCount)
+		case core.LongTerm:
+			maxLongTermAccessCount = max(maxLongTermAccessCount, candidate.AccessCount)
+			maxAge = max(maxAge, int(ageHours(candidate.CreatedAt, now)))
+		}
+	}
+
+	results := make([]Result, len(candidates))
+	for i, candidate := range candidates {
+		var usage, recency float32
+		age := ageHours(candidate.CreatedAt, now)
+		switch candidate.MemoryType {
+		case core.ShortTerm:
+			usage = float32(candidate.AccessCount+candidate.MergeCount) /
+				float32(max(maxAccessCount+maxMergeCount, 1))
+			recency = 1 / float32(max(1, age))
+		case core.LongTerm:
+			usage = float32(candidate.AccessCount) / float32(max(maxLongTermAccessCount, 1))
+			recency = float32(age) / float32(max(maxAge, 1))
+		}
+		results[i] = Result{
+			Score:   (usage + recency) / 2,
+			Recency: recency,
+			Usage:   usage,
+		}
+	}
+	return results
+}
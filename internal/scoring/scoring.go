@@ -0,0 +1,112 @@
+package scoring
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"time"
+)
+
+const (
+	// Usage and recency, as memories were always scored
+	RelevancyScorer = "relevancy"
+	// Search similarity decayed by the age of the memory
+	HalfLifeScorer = "half_life"
+	// Weighted blend of similarity, recency and usage
+	WeightedScorer = "weighted"
+)
+
+// What a scorer knows about a fetched memory
+type Candidate struct {
+	MemoryType core.MemoryTypeEnum
+	// Similarity to the query given by the search
+	Similarity  float32
+	AccessCount int
+	MergeCount  int
+	CreatedAt   time.Time
+}
+
+// A score and the parts it was made of
+type Result struct {
+	Score   float32
+	Recency float32
+	Usage   float32
+}
+
+// Scores the memories of a fetch. Candidates are scored together since
+// some scorers normalize by the whole batch, the results keep their order
+type Scorer interface {
+	Score(candidates []Candidate, now time.Time) []Result
+}
+
+type weights struct {
+	similarity    float32
+	recency       float32
+	usage         float32
+	halfLifeHours float32
+}
+
+// Returns the named scorer with the configured weights and the given overrides,
+// an empty name picks the configured default
+func New(name string, overrides *core.ScoringWeights) (Scorer, error) {
+	if name == "" {
+		name = config.Scoring.DefaultScorer
+	}
+	w := weights{
+		similarity:    config.Scoring.SimilarityWeight,
+		recency:       config.Scoring.RecencyWeight,
+		usage:         config.Scoring.UsageWeight,
+		halfLifeHours: config.Scoring.HalfLifeHours,
+	}
+	if overrides != nil {
+		if overrides.Similarity != nil {
+			w.similarity = *overrides.Similarity
+		}
+		if overrides.Recency != nil {
+			w.recency = *overrides.Recency
+		}
+		if overrides.Usage != nil {
+			w.usage = *overrides.Usage
+		}
+		if overrides.HalfLifeHours != nil {
+			w.halfLifeHours = *overrides.HalfLifeHours
+		}
+	}
+	switch name {
+	case RelevancyScorer:
+		return &Relevancy{}, nil
+	case HalfLifeScorer:
+		if w.halfLifeHours <= 0 {
+			return nil, core.InvalidScoringWeights
+		}
+		return &HalfLife{HalfLifeHours: w.halfLifeHours}, nil
+	case WeightedScorer:
+		if w.similarity < 0 || w.recency < 0 || w.usage < 0 ||
+			w.similarity+w.recency+w.usage == 0 || w.halfLifeHours <= 0 {
+			return nil, core.InvalidScoringWeights
+		}
+		return &Weighted{
+			SimilarityWeight: w.similarity,
+			RecencyWeight:    w.recency,
+			UsageWeight:      w.usage,
+			HalfLifeHours:    w.halfLifeHours,
+		}, nil
+	}
+	return nil, core.InvalidScorer
+}
+
+func ageHours(createdAt time.Time, now time.Time) float64 {
+	return now.Sub(createdAt).Hours()
+}
+
+// Access and merge counts of each candidate over the highest of the batch
+func usageScores(candidates []Candidate) []float32 {
+	maxUsage := 0
+	for _, candidate := range candidates {
+		maxUsage = max(maxUsage, candidate.AccessCount+candidate.MergeCount)
+	}
+	scores := make([]float32, len(candidates))
+	for i, candidate := range candidates {
+		scores[i] = float32(candidate.AccessCount+candidate.MergeCount) / float32(max(maxUsage, 1))
+	}
+	return scores
+}
@@ -0,0 +1,211 @@
+package scoring
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func hoursAgo(hours float64) time.Time {
+	return now.Add(-time.Duration(hours * float64(time.Hour)))
+}
+
+func weight(w float32) *float32 { return &w }
+
+func assertResults(t *testing.T, got, want []Result) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d results, want %d", len(got), len(want))
+	}
+	near := func(a, b float32) bool { return math.Abs(float64(a-b)) < 1e-4 }
+	for i := range got {
+		if !near(got[i].Score, want[i].Score) ||
+			!near(got[i].Recency, want[i].Recency) ||
+			!near(got[i].Usage, want[i].Usage) {
+			t.Errorf("result %d is %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDecay(t *testing.T) {
+	tests := []struct {
+		name      string
+		createdAt time.Time
+		halfLife  float32
+		want      float32
+	}{
+		{name: "brand new", createdAt: now, halfLife: 24, want: 1},
+		{name: "one half life", createdAt: hoursAgo(24), halfLife: 24, want: 0.5},
+		{name: "two half lives", createdAt: hoursAgo(48), halfLife: 24, want: 0.25},
+		{name: "created in the future", createdAt: now.Add(time.Hour), halfLife: 24, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decay(tt.createdAt, now, tt.halfLife)
+			if math.Abs(float64(got-tt.want)) > 1e-6 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScorers(t *testing.T) {
+	tests := []struct {
+		name       string
+		scorer     Scorer
+		candidates []Candidate
+		want       []Result
+	}{
+		{
+			name:   "half life decays the similarity",
+			scorer: &HalfLife{HalfLifeHours: 24},
+			candidates: []Candidate{
+				{Similarity: 0.8, CreatedAt: now, AccessCount: 2},
+				{Similarity: 0.8, CreatedAt: hoursAgo(24), AccessCount: 1, MergeCount: 1},
+				{Similarity: 0.8, CreatedAt: hoursAgo(48)},
+			},
+			want: []Result{
+				{Score: 0.8, Recency: 1, Usage: 1},
+				{Score: 0.4, Recency: 0.5, Usage: 1},
+				{Score: 0.2, Recency: 0.25, Usage: 0},
+			},
+		},
+		{
+			name: "weighted blends by the weights",
+			scorer: &Weighted{
+				SimilarityWeight: 2, RecencyWeight: 1, UsageWeight: 1, HalfLifeHours: 24,
+			},
+			candidates: []Candidate{
+				{Similarity: 1, CreatedAt: now, AccessCount: 4},
+				{Similarity: 0.5, CreatedAt: hoursAgo(24), AccessCount: 2},
+			},
+			want: []Result{
+				{Score: 1, Recency: 1, Usage: 1},
+				{Score: (2*0.5 + 0.5 + 0.5) / 4, Recency: 0.5, Usage: 0.5},
+			},
+		},
+		{
+			name:   "relevancy of short term memories",
+			scorer: &Relevancy{},
+			candidates: []Candidate{
+				{MemoryType: core.ShortTerm, CreatedAt: hoursAgo(0.5), AccessCount: 3, MergeCount: 1},
+				{MemoryType: core.ShortTerm, CreatedAt: hoursAgo(4), AccessCount: 1},
+			},
+			want: []Result{
+				{Score: (1 + 1) / 2, Recency: 1, Usage: 1},
+				{Score: (0.25 + 0.25) / 2, Recency: 0.25, Usage: 0.25},
+			},
+		},
+		{
+			name:   "relevancy of long term memories",
+			scorer: &Relevancy{},
+			candidates: []Candidate{
+				{MemoryType: core.LongTerm, CreatedAt: hoursAgo(10), AccessCount: 2},
+				{MemoryType: core.LongTerm, CreatedAt: hoursAgo(5)},
+			},
+			want: []Result{
+				{Score: (1 + 1) / 2, Recency: 1, Usage: 1},
+				{Score: (0 + 0.5) / 2, Recency: 0.5, Usage: 0},
+			},
+		},
+		{
+			name:       "no candidates",
+			scorer:     &Weighted{SimilarityWeight: 1, HalfLifeHours: 24},
+			candidates: nil,
+			want:       []Result{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertResults(t, tt.scorer.Score(tt.candidates, now), tt.want)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		scorer    string
+		overrides *core.ScoringWeights
+		want      Scorer
+		err       error
+	}{
+		{
+			name:   "relevancy",
+			scorer: RelevancyScorer,
+			want:   &Relevancy{},
+		},
+		{
+			name:      "half life with an overridden half life",
+			scorer:    HalfLifeScorer,
+			overrides: &core.ScoringWeights{HalfLifeHours: weight(12)},
+			want:      &HalfLife{HalfLifeHours: 12},
+		},
+		{
+			name:   "weighted with every weight overridden",
+			scorer: WeightedScorer,
+			overrides: &core.ScoringWeights{
+				Similarity: weight(3), Recency: weight(2), Usage: weight(1), HalfLifeHours: weight(6),
+			},
+			want: &Weighted{
+				SimilarityWeight: 3, RecencyWeight: 2, UsageWeight: 1, HalfLifeHours: 6,
+			},
+		},
+		{
+			name:      "half life without a half life",
+			scorer:    HalfLifeScorer,
+			overrides: &core.ScoringWeights{HalfLifeHours: weight(0)},
+			err:       core.InvalidScoringWeights,
+		},
+		{
+			name:   "weighted with a negative weight",
+			scorer: WeightedScorer,
+			overrides: &core.ScoringWeights{
+				Similarity: weight(-1), HalfLifeHours: weight(6),
+			},
+			err: core.InvalidScoringWeights,
+		},
+		{
+			name:   "weighted with no weight at all",
+			scorer: WeightedScorer,
+			overrides: &core.ScoringWeights{
+				Similarity: weight(0), Recency: weight(0), Usage: weight(0), HalfLifeHours: weight(6),
+			},
+			err: core.InvalidScoringWeights,
+		},
+		{
+			name:   "unknown scorer",
+			scorer: "bm25",
+			err:    core.InvalidScorer,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scorer, err := New(tt.scorer, tt.overrides)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			switch want := tt.want.(type) {
+			case *Relevancy:
+				if _, ok := scorer.(*Relevancy); !ok {
+					t.Errorf("got %T, want %T", scorer, want)
+				}
+			case *HalfLife:
+				if got, ok := scorer.(*HalfLife); !ok || *got != *want {
+					t.Errorf("got %+v, want %+v", scorer, want)
+				}
+			case *Weighted:
+				if got, ok := scorer.(*Weighted); !ok || *got != *want {
+					t.Errorf("got %+v, want %+v", scorer, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"log/slog"
+	"time"
+)
+
+const (
+	apiKeyPrefix      = "bm_"
+	apiKeyPrefixChars = 8
+)
+
+type ApiKeyService struct {
+	repo       repository.ApiKeyRepository
+	tenantRepo repository.TenantRepository
+}
+
+func NewApiKeyService(
+	repo repository.ApiKeyRepository,
+	tenantRepo repository.TenantRepository,
+) *ApiKeyService {
+	return &ApiKeyService{repo: repo, tenantRepo: tenantRepo}
+}
+
+func hashApiKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// Issues a new api key, the key itself is only returned here
+func (s *ApiKeyService) Issue(
+	ctx context.Context, request *core.NewApiKey,
+) (*core.IssuedApiKey, error) {
+	if len(request.Scopes) == 0 {
+		return nil, core.InvalidApiKeyScope
+	}
+	for _, scope := range request.Scopes {
+		if !core.IsValidApiKeyScope(scope) {
+			return nil, core.InvalidApiKeyScope
+		}
+	}
+	tenantId := request.TenantId
+	if tenantId == "" {
+		tenantId = core.DefaultTenantId
+	}
+	if tenantId != core.DefaultTenantId {
+		if _, err := s.tenantRepo.GetById(ctx, tenantId); err != nil {
+			return nil, err
+		}
+	}
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	key := apiKeyPrefix + hex.EncodeToString(secret)
+	apiKey, err := s.repo.Create(ctx, &core.ApiKeyModel{
+		Name:      request.Name,
+		Prefix:    key[:len(apiKeyPrefix)+apiKeyPrefixChars],
+		Hash:      hashApiKey(key),
+		Scopes:    request.Scopes,
+		TenantId:  tenantId,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		slog.Error("error issuing api key", "error", err)
+		return nil, err
+	}
+	slog.Info(
+		"api key issued",
+		"id", apiKey.Id,
+		"scopes", apiKey.Scopes,
+		"tenant_id", apiKey.TenantId,
+	)
+	return &core.IssuedApiKey{ApiKey: *apiKey, Key: key}, nil
+}
+
+// Returns the api key matching the given key, the root key
+// from the configuration is always accepted with the admin scope
+func (s *ApiKeyService) Authenticate(
+	ctx context.Context, key string,
+) (*core.ApiKey, error) {
+	if key == "" {
+		return nil, core.InvalidApiKey
+	}
+	if config.Auth.RootKey != "" &&
+		subtle.ConstantTimeCompare([]byte(key), []byte(config.Auth.RootKey)) == 1 {
+		return &core.ApiKey{
+			Id:       "root",
+			Name:     "root",
+			Scopes:   []core.ApiKeyScope{core.AdminScope},
+			TenantId: core.DefaultTenantId,
+		}, nil
+	}
+	return s.repo.GetByHash(ctx, hashApiKey(key))
+}
+
+func (s *ApiKeyService) GetAll(ctx context.Context) ([]*core.ApiKey, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *ApiKeyService) Revoke(ctx context.Context, id string) error {
+	return s.repo.Revoke(ctx, id)
+}
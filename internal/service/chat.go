@@ -8,17 +8,21 @@ import (
 )
 
 type ChatService struct {
-	repo repository.ChatRepository
+	repo          repository.ChatRepository
+	tenantService *TenantService
 }
 
-func NewChatService(repo repository.ChatRepository) *ChatService {
-	return &ChatService{repo: repo}
+func NewChatService(repo repository.ChatRepository, tenantService *TenantService) *ChatService {
+	return &ChatService{repo: repo, tenantService: tenantService}
 }
 
 func (s *ChatService) Create(
 	ctx context.Context,
 	externalId string,
 ) error {
+	if err := s.tenantService.CheckChatQuota(ctx); err != nil {
+		return err
+	}
 	chat := &core.NewChat{
 		ExternalId: externalId,
 	}
@@ -37,3 +41,7 @@ func (s *ChatService) GetByExternalId(ctx context.Context, externalId string) (*
 func (s *ChatService) GetAll(ctx context.Context) ([]*core.Chat, error) {
 	return s.repo.GetAll(ctx)
 }
+
+func (s *ChatService) GetById(ctx context.Context, id string) (*core.Chat, error) {
+	return s.repo.GetById(ctx, id)
+}
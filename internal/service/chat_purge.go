@@ -0,0 +1,88 @@
+package service
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository/vector"
+	"github.com/Mateus-Lacerda/better-mem/internal/task"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Permanently deletes chats, unlike deactivating their memories
+type ChatPurgeService struct {
+	repo       repository.ChatPurgeRepository
+	chatRepo   repository.ChatRepository
+	vectorRepo vector.MemoryVectorRepository
+}
+
+func NewChatPurgeService(
+	repo repository.ChatPurgeRepository,
+	chatRepo repository.ChatRepository,
+	vectorRepo vector.MemoryVectorRepository,
+) *ChatPurgeService {
+	return &ChatPurgeService{
+		repo:       repo,
+		chatRepo:   chatRepo,
+		vectorRepo: vectorRepo,
+	}
+}
+
+// Deletes the chat and everything stored for it, then counts again to
+// verify nothing is left. The chat row goes last, so a purge that fails
+// midway can be run again
+func (s *ChatPurgeService) Purge(ctx context.Context, externalId string) (*core.ChatDeletionReport, error) {
+	chatId, err := s.chatRepo.GetByExternalID(ctx, externalId)
+	if err != nil {
+		return nil, err
+	}
+	tenantId := core.TenantFromContext(ctx)
+	report := &core.ChatDeletionReport{
+		ChatId:    externalId,
+		TenantId:  tenantId,
+		StartedAt: time.Now(),
+	}
+	// The queue goes first so the worker does not bring anything back
+	queuedJobs, err := task.PurgeChatTasks(ctx, tenantId, *chatId, externalId)
+	if err != nil {
+		slog.Error("error purging chat tasks", "chat_id", *chatId, "error", err)
+		return nil, err
+	}
+	// The vectors are found through the memories, so they go before them
+	vectors, err := s.vectorRepo.DeleteAll(ctx, *chatId)
+	if err != nil {
+		slog.Error("error purging chat vectors", "chat_id", *chatId, "error", err)
+		return nil, err
+	}
+	deleted, err := s.repo.Purge(ctx, *chatId)
+	if err != nil {
+		slog.Error("error purging chat", "chat_id", *chatId, "error", err)
+		return nil, err
+	}
+	deleted.Vectors = vectors
+	deleted.QueuedJobs = queuedJobs
+	report.Deleted = *deleted
+
+	remaining, err := s.repo.Count(ctx, *chatId)
+	if err != nil {
+		return nil, err
+	}
+	if remaining.Vectors, err = s.vectorRepo.Count(ctx, *chatId); err != nil {
+		return nil, err
+	}
+	if remaining.QueuedJobs, err = task.CountChatTasks(ctx, tenantId, *chatId, externalId); err != nil {
+		return nil, err
+	}
+	report.Remaining = *remaining
+	report.Verified = remaining.IsEmpty()
+	report.CompletedAt = time.Now()
+	slog.Info(
+		"chat purged",
+		"chat_id", *chatId,
+		"tenant_id", tenantId,
+		"deleted", report.Deleted,
+		"verified", report.Verified,
+	)
+	return report, nil
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
+	protos "github.com/Mateus-Lacerda/better-mem/internal/grpc_client"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository/vector"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Exports chats to versioned JSONL and imports them back, for backups,
+// moving chats between environments and reproducing bug reports
+type ChatTransferService struct {
+	shortTermRepo    repository.ShortTermMemoryRepository
+	longTermRepo     repository.LongTermMemoryRepository
+	vectorRepo       vector.MemoryVectorRepository
+	chatService      *ChatService
+	tenantService    *TenantService
+	chatPurgeService *ChatPurgeService
+}
+
+func NewChatTransferService(
+	shortTermRepo repository.ShortTermMemoryRepository,
+	longTermRepo repository.LongTermMemoryRepository,
+	vectorRepo vector.MemoryVectorRepository,
+	chatService *ChatService,
+	tenantService *TenantService,
+	chatPurgeService *ChatPurgeService,
+) *ChatTransferService {
+	return &ChatTransferService{
+		shortTermRepo:    shortTermRepo,
+		longTermRepo:     longTermRepo,
+		vectorRepo:       vectorRepo,
+		chatService:      chatService,
+		tenantService:    tenantService,
+		chatPurgeService: chatPurgeService,
+	}
+}
+
+// Returns the lines of the export of the chat: the header, the chat and
+// every memory, active or not. The memories keep the ids they had here,
+// an import gives them new ones
+func (s *ChatTransferService) Export(
+	ctx context.Context, externalId string, withEmbeddings bool,
+) ([]*core.ChatExportLine, error) {
+	chatId, err := s.chatService.GetByExternalId(ctx, externalId)
+	if err != nil {
+		return nil, err
+	}
+	shortTermMemories, err := s.shortTermRepo.GetAllByChatId(ctx, *chatId)
+	if err != nil {
+		return nil, err
+	}
+	longTermMemories, err := s.longTermRepo.GetAllByChatId(ctx, *chatId)
+	if err != nil {
+		return nil, err
+	}
+	header := &core.ChatExportHeader{
+		Version:    core.ChatExportVersion,
+		ExportedAt: time.Now(),
+		Embeddings: withEmbeddings,
+	}
+	vectors := map[string][]float32{}
+	if withEmbeddings {
+		header.Dimension = int(config.Database.DefaultVectorSize)
+		memoryIds := make([]string, 0, len(shortTermMemories)+len(longTermMemories))
+		for _, memory := range shortTermMemories {
+			memoryIds = append(memoryIds, memory.Id)
+		}
+		for _, memory := range longTermMemories {
+			memoryIds = append(memoryIds, memory.Id)
+		}
+		if vectors, err = s.vectorRepo.GetByMemoryIds(ctx, memoryIds); err != nil {
+			return nil, err
+		}
+	}
+	lines := []*core.ChatExportLine{
+		{Type: core.ChatExportHeaderLine, Header: header},
+		{Type: core.ChatExportChatLine, Chat: &core.NewChat{ExternalId: externalId}},
+	}
+	// The internal chat id means nothing anywhere else
+	for _, memory := range shortTermMemories {
+		memory.ChatId = ""
+		lines = append(lines, &core.ChatExportLine{
+			Type:            core.ChatExportShortTermMemoryLine,
+			ShortTermMemory: memory,
+			Embedding:       vectors[memory.Id],
+		})
+	}
+	for _, memory := range longTermMemories {
+		memory.ChatId = ""
+		lines = append(lines, &core.ChatExportLine{
+			Type:           core.ChatExportLongTermMemoryLine,
+			LongTermMemory: memory,
+			Embedding:      vectors[memory.Id],
+		})
+	}
+	slog.Info(
+		"chat exported",
+		"chat_id", *chatId,
+		"short_term_memories", len(shortTermMemories),
+		"long_term_memories", len(longTermMemories),
+		"embeddings", withEmbeddings,
+	)
+	return lines, nil
+}
+
+// Reads an export and creates the chat with its memories, under
+// externalId when given and under the exported external id otherwise.
+// The memories whose embedding is missing or of another size than the
+// vector store's are embedded again. Everything is read and embedded
+// before anything is stored, and a chat that fails midway is purged
+func (s *ChatTransferService) Import(
+	ctx context.Context, externalId string, export io.Reader,
+) (*core.ChatImportReport, error) {
+	chat, memories, err := readChatExport(export)
+	if err != nil {
+		return nil, err
+	}
+	if externalId == "" {
+		externalId = chat.ExternalId
+	}
+	if externalId == "" {
+		return nil, fmt.Errorf("%w: the chat has no external id", core.InvalidChatExport)
+	}
+	// Checked before embedding, not every backend reports the conflict
+	// on create
+	if _, err := s.chatService.GetByExternalId(ctx, externalId); err == nil {
+		return nil, core.ChatExternalIdAlreadyExists
+	} else if !errors.Is(err, core.ChatNotFound) {
+		return nil, err
+	}
+	// The whole file has to fit, checking memory by memory would leave
+	// a half imported chat behind
+	active := 0
+	for _, memory := range memories {
+		if exportLineActive(memory) {
+			active++
+		}
+	}
+	if active > 0 {
+		if err := s.tenantService.CheckMemoryQuotaFor(ctx, active); err != nil {
+			return nil, err
+		}
+	}
+	report := &core.ChatImportReport{ChatId: externalId}
+	if report.Reembedded, err = reembed(memories); err != nil {
+		return nil, err
+	}
+	if err := s.chatService.Create(ctx, externalId); err != nil {
+		return nil, err
+	}
+	chatId, err := s.chatService.GetByExternalId(ctx, externalId)
+	if err != nil {
+		return nil, err
+	}
+	for _, memory := range memories {
+		if err := s.importMemory(ctx, *chatId, memory, report); err != nil {
+			slog.Error("error importing chat, purging it", "chat_id", *chatId, "error", err)
+			if _, purgeErr := s.chatPurgeService.Purge(ctx, externalId); purgeErr != nil {
+				slog.Error("error purging partially imported chat", "chat_id", *chatId, "error", purgeErr)
+			}
+			return nil, err
+		}
+	}
+	slog.Info(
+		"chat imported",
+		"chat_id", *chatId,
+		"short_term_memories", report.ShortTermMemories,
+		"long_term_memories", report.LongTermMemories,
+		"reembedded", report.Reembedded,
+	)
+	return report, nil
+}
+
+// Stores a memory of an export with its vector, the vectors are stored
+// active so the ones of inactive memories are deactivated after
+func (s *ChatTransferService) importMemory(
+	ctx context.Context, chatId string, line *core.ChatExportLine, report *core.ChatImportReport,
+) error {
+	var memoryId string
+	var memoryType core.MemoryTypeEnum
+	switch line.Type {
+	case core.ChatExportShortTermMemoryLine:
+		memory := line.ShortTermMemory
+		created, err := s.shortTermRepo.Create(ctx, &core.NewShortTermMemory{
+			Memory:         memory.Memory,
+			ChatId:         chatId,
+			AccessCount:    memory.AccessCount,
+			MergeCount:     memory.MergeCount,
+			Merged:         memory.Merged,
+			CreatedAt:      memory.CreatedAt,
+			Active:         memory.Active,
+			RelatedContext: memory.RelatedContext,
+		})
+		if err != nil {
+			return err
+		}
+		memoryId, memoryType = created.Id, core.ShortTerm
+		report.ShortTermMemories++
+	case core.ChatExportLongTermMemoryLine:
+		memory := line.LongTermMemory
+		created, err := s.longTermRepo.Create(ctx, &core.NewLongTermMemory{
+			Memory:         memory.Memory,
+			ChatId:         chatId,
+			AccessCount:    memory.AccessCount,
+			CreatedAt:      memory.CreatedAt,
+			Active:         memory.Active,
+			RelatedContext: memory.RelatedContext,
+		})
+		if err != nil {
+			return err
+		}
+		memoryId, memoryType = created.Id, core.LongTerm
+		report.LongTermMemories++
+	}
+	if err := s.vectorRepo.Create(ctx, chatId, line.Embedding, memoryType, memoryId); err != nil {
+		return err
+	}
+	if !exportLineActive(line) {
+		return s.vectorRepo.Deactivate(ctx, chatId, memoryId)
+	}
+	return nil
+}
+
+// Reads the header, the chat and the memory lines of an export
+func readChatExport(export io.Reader) (*core.NewChat, []*core.ChatExportLine, error) {
+	scanner := bufio.NewScanner(export)
+	scanner.Buffer(make([]byte, 0, 64*1024), config.ChatTransfer.ImportMaxLineBytes)
+	var header *core.ChatExportHeader
+	var chat *core.NewChat
+	var memories []*core.ChatExportLine
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var line core.ChatExportLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, nil, fmt.Errorf("%w: line %d: %s", core.InvalidChatExport, lineNumber, err)
+		}
+		switch {
+		case header == nil:
+			if line.Type != core.ChatExportHeaderLine || line.Header == nil {
+				return nil, nil, fmt.Errorf("%w: line %d: expected the header", core.InvalidChatExport, lineNumber)
+			}
+			if line.Header.Version < 1 || line.Header.Version > core.ChatExportVersion {
+				return nil, nil, fmt.Errorf(
+					"%w: unsupported version %d, up to %d is supported",
+					core.InvalidChatExport, line.Header.Version, core.ChatExportVersion,
+				)
+			}
+			header = line.Header
+		case chat == nil:
+			if line.Type != core.ChatExportChatLine || line.Chat == nil {
+				return nil, nil, fmt.Errorf("%w: line %d: expected the chat", core.InvalidChatExport, lineNumber)
+			}
+			chat = line.Chat
+		default:
+			if err := validateMemoryLine(&line); err != nil {
+				return nil, nil, fmt.Errorf("%w: line %d: %s", core.InvalidChatExport, lineNumber, err)
+			}
+			memories = append(memories, &line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%w: line %d: %s", core.InvalidChatExport, lineNumber+1, err)
+	}
+	if chat == nil {
+		return nil, nil, fmt.Errorf("%w: missing the header or the chat", core.InvalidChatExport)
+	}
+	return chat, memories, nil
+}
+
+func validateMemoryLine(line *core.ChatExportLine) error {
+	switch line.Type {
+	case core.ChatExportShortTermMemoryLine:
+		if line.ShortTermMemory == nil {
+			return fmt.Errorf("missing the short term memory")
+		}
+	case core.ChatExportLongTermMemoryLine:
+		if line.LongTermMemory == nil {
+			return fmt.Errorf("missing the long term memory")
+		}
+	default:
+		return fmt.Errorf("unexpected line type %q", line.Type)
+	}
+	if exportLineText(line) == "" {
+		return core.EmptyMemory
+	}
+	return nil
+}
+
+func exportLineText(line *core.ChatExportLine) string {
+	if line.ShortTermMemory != nil {
+		return line.ShortTermMemory.Memory
+	}
+	return line.LongTermMemory.Memory
+}
+
+func exportLineActive(line *core.ChatExportLine) bool {
+	if line.ShortTermMemory != nil {
+		return line.ShortTermMemory.Active
+	}
+	return line.LongTermMemory.Active
+}
+
+// Embeds the memories whose embedding the vector store can not take,
+// in batches, returns how many were embedded. Inference services without
+// batch embedding get one call per memory
+func reembed(memories []*core.ChatExportLine) (int, error) {
+	var pending []*core.ChatExportLine
+	for _, memory := range memories {
+		if len(memory.Embedding) != int(config.Database.DefaultVectorSize) {
+			pending = append(pending, memory)
+		}
+	}
+	for start := 0; start < len(pending); start += config.ChatTransfer.ImportEmbedBatchSize {
+		batch := pending[start:min(start+config.ChatTransfer.ImportEmbedBatchSize, len(pending))]
+		texts := make([]string, len(batch))
+		for i, memory := range batch {
+			texts[i] = exportLineText(memory)
+		}
+		embeddings, err := protos.EmbedBatch(texts)
+		switch status.Code(err) {
+		case codes.OK:
+		case codes.Unimplemented:
+			slog.Warn("Batch embedding unavailable, embedding each memory", "error", err)
+			return len(pending), embedEach(pending[start:])
+		default:
+			return 0, err
+		}
+		if len(embeddings) != len(texts) {
+			return 0, fmt.Errorf(
+				"batch embedding returned %d embeddings for %d memories",
+				len(embeddings), len(texts),
+			)
+		}
+		for i, memory := range batch {
+			memory.Embedding = embeddings[i]
+		}
+	}
+	return len(pending), nil
+}
+
+func embedEach(memories []*core.ChatExportLine) error {
+	for _, memory := range memories {
+		embedding, err := protos.Embed(exportLineText(memory))
+		if err != nil {
+			return err
+		}
+		memory.Embedding = embedding
+	}
+	return nil
+}
@@ -58,12 +58,6 @@ func (s *LongTermMemoryService) GetById(
 	return s.repo.GetById(ctx, chatId, memoryId)
 }
 
-func (s *LongTermMemoryService) GetScored(
-	ctx context.Context, chatId string, memoriesIds []string,
-) ([]*core.ScoredMemory, error) {
-	return s.repo.GetScored(ctx, chatId, memoriesIds)
-}
-
 func (s *LongTermMemoryService) RegisterUsage(
 	ctx context.Context, chatId string, memoryId string,
 ) error {
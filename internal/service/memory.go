@@ -2,101 +2,317 @@ package service
 
 import (
 	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
 	protos "github.com/Mateus-Lacerda/better-mem/internal/grpc_client"
 	"github.com/Mateus-Lacerda/better-mem/internal/repository"
 	"github.com/Mateus-Lacerda/better-mem/internal/repository/vector"
+	"github.com/Mateus-Lacerda/better-mem/internal/render"
+	"github.com/Mateus-Lacerda/better-mem/internal/scoring"
+	"github.com/Mateus-Lacerda/better-mem/internal/tokens"
+	"github.com/Mateus-Lacerda/better-mem/internal/uow"
 	"context"
+	"fmt"
 	"log/slog"
+	"math"
 	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type MemoryService struct {
-	shortTermRepo repository.ShortTermMemoryRepository
-	longTermRepo  repository.LongTermMemoryRepository
-	vectorRepo    vector.MemoryVectorRepository
+	shortTermRepo       repository.ShortTermMemoryRepository
+	longTermRepo        repository.LongTermMemoryRepository
+	vectorRepo          vector.MemoryVectorRepository
+	mergeRepo           repository.MemoryMergeRepository
+	keywordRepo         repository.MemoryKeywordRepository
+	tenantService       *TenantService
+	queryRewriteService *QueryRewriteService
+	enhancementService  *MemoryEnhancementService
+	eventService        *MemoryEventService
+	uow                 uow.UnitOfWork[int, any]
 }
 
 func NewMemoryService(
 	shortTermRepo repository.ShortTermMemoryRepository,
 	longTermRepo repository.LongTermMemoryRepository,
 	vectorRepo vector.MemoryVectorRepository,
+	mergeRepo repository.MemoryMergeRepository,
+	keywordRepo repository.MemoryKeywordRepository,
+	tenantService *TenantService,
+	queryRewriteService *QueryRewriteService,
+	enhancementService *MemoryEnhancementService,
+	eventService *MemoryEventService,
+	uow uow.UnitOfWork[int, any],
 ) *MemoryService {
 	return &MemoryService{
-		shortTermRepo: shortTermRepo,
-		longTermRepo:  longTermRepo,
-		vectorRepo:    vectorRepo,
+		shortTermRepo:       shortTermRepo,
+		longTermRepo:        longTermRepo,
+		vectorRepo:          vectorRepo,
+		mergeRepo:           mergeRepo,
+		keywordRepo:         keywordRepo,
+		tenantService:       tenantService,
+		queryRewriteService: queryRewriteService,
+		enhancementService:  enhancementService,
+		eventService:        eventService,
+		uow:                 uow,
 	}
 }
 
+// Constant of the reciprocal rank fusion, dampens the weight of the top ranks
+const rrfK = 60
+
+// Applied to fetches that leave the limits out, as documented in
+// [core.MemoryFetchRequest]
+const (
+	defaultFetchLimit        = 2
+	defaultVectorSearchLimit = 10
+)
+
+// Lowest cosine similarity, used as the vector search threshold in explain
+// mode so the candidates under the requested one can be reported
+const minimumSimilarity float32 = -1
+
+// Identifies a memory across the short and long term stores
+type memoryKey struct {
+	memoryType core.MemoryTypeEnum
+	memoryId   string
+}
+
+func keyOf(payload core.MemoryPayload) memoryKey {
+	return memoryKey{payload.MemoryType, payload.MemoryId}
+}
+
+// Candidates of a fetch and the search signals behind them
+type fetchCandidates struct {
+	query *searchQuery
+	hits  []core.ScoredMemoryVector
+	// Vector hits dropped by the thresholds, only kept in explain mode
+	rejected             []core.ScoredMemoryVector
+	vectorScores         map[memoryKey]float32
+	keywordScores        map[memoryKey]float32
+	belowVectorThreshold map[memoryKey]bool
+	filteredByLongTerm   map[memoryKey]bool
+	// Best keyword score, unbounded on the backends that score by relevance
+	maxKeywordScore float32
+}
+
+// Similarity to the query given by the search, the vector one when there is
+// one. The scorers expect it within [0, 1], so keyword scores above it are
+// scaled by the best one of the fetch
+func (c *fetchCandidates) similarity(memoryType core.MemoryTypeEnum, memoryId string) float32 {
+	key := memoryKey{memoryType, memoryId}
+	if score, ok := c.vectorScores[key]; ok {
+		return score
+	}
+	if c.maxKeywordScore > 1 {
+		return c.keywordScores[key] / c.maxKeywordScore
+	}
+	return c.keywordScores[key]
+}
+
+// Adds the search signals to the scorer part of the explanation
+func (c *fetchCandidates) explain(memory *core.ScoredMemory) {
+	if memory.Explanation == nil {
+		memory.Explanation = &core.MemoryScoreExplanation{}
+	}
+	key := memoryKey{memory.MemoryType, memory.Id}
+	if score, ok := c.vectorScores[key]; ok {
+		memory.Explanation.VectorSimilarity = &score
+	}
+	if score, ok := c.keywordScores[key]; ok {
+		memory.Explanation.KeywordScore = &score
+	}
+	memory.Explanation.BelowVectorSearchThreshold = c.belowVectorThreshold[key]
+	memory.Explanation.FilteredByLongTermThreshold = c.filteredByLongTerm[key]
+}
+
+// Embeds a text for a fetch, batches look up the texts embedded beforehand
+type embedFunc func(text string) ([]float32, error)
+
 func (s *MemoryService) Fetch(
 	ctx context.Context,
 	chatId string,
-	query string,
-	limit int,
-	vectorSearchLimit int,
-	threshold float32,
-	longTermThreshold float32,
-) ([]*core.ScoredMemory, error) {
-	var memories []*core.ScoredMemory
-	vectorService := NewMemoryVectorService(s.vectorRepo)
-	embeddings, err := protos.Embed(query)
-	if err != nil {
-		return memories, err
+	request *core.MemoryFetchRequest,
+) (*core.MemoryFetchResult, error) {
+	return s.fetch(ctx, chatId, request, protos.Embed)
+}
+
+// Checks the size of a fetch batch
+func (s *MemoryService) CheckBatchSize(size int) error {
+	if size == 0 {
+		return core.EmptyFetchBatch
 	}
-	similarMemories, err := vectorService.SearchMemoryVector(
-		ctx,
-		chatId,
-		embeddings,
-		vectorSearchLimit,
-		threshold,
-	)
-	if err != nil {
-		slog.Error("Error searching memory vector", "error", err)
-		return memories, err
+	if size > config.Fetch.BatchMaxSize {
+		return core.FetchBatchTooLarge
 	}
-	if similarMemories == nil {
-		slog.Info("No similar memories found")
-		return memories, nil
+	return nil
+}
+
+// Runs several fetches at once, the chat ids must already be resolved.
+// The texts are embedded in a single call and the fetches run concurrently,
+// a fetch that fails is reported in its item without failing the others
+func (s *MemoryService) FetchBatch(
+	ctx context.Context,
+	fetches []core.MemoryFetchBatchEntry,
+) ([]core.MemoryFetchBatchItem, error) {
+	if err := s.CheckBatchSize(len(fetches)); err != nil {
+		return nil, err
 	}
-	var shortTermMemories []string
-	var longTermMemories []string
-	for _, memory := range *similarMemories {
-		switch memory.Payload.MemoryType {
-		case core.ShortTerm:
-			shortTermMemories = append(
-				shortTermMemories,
-				memory.Payload.MemoryId,
-			)
-		case core.LongTerm:
-			if memory.Score >= longTermThreshold {
-				longTermMemories = append(
-					longTermMemories,
-					memory.Payload.MemoryId,
-				)
+	embed, err := embedAhead(fetches)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]core.MemoryFetchBatchItem, len(fetches))
+	concurrency := max(config.Fetch.BatchConcurrency, 1)
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range fetches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			items[i].ChatId = fetches[i].ChatId
+			result, err := s.fetch(ctx, fetches[i].ChatId, &fetches[i].MemoryFetchRequest, embed)
+			if err != nil {
+				slog.Error("Error fetching memories", "chat_id", fetches[i].ChatId, "error", err)
+				items[i].Error = err.Error()
+				return
 			}
+			items[i].MemoryFetchResult = result
+		}()
+	}
+	wg.Wait()
+	return items, nil
+}
+
+// Embeds in one call every text the fetches are known to search with, texts
+// only known later, like rewritten queries, are embedded when they show up
+func embedAhead(fetches []core.MemoryFetchBatchEntry) (embedFunc, error) {
+	var texts []string
+	seen := make(map[string]bool)
+	add := func(text string) {
+		if text != "" && !seen[text] {
+			seen[text] = true
+			texts = append(texts, text)
 		}
 	}
-	scoredSTMemories, err := s.shortTermRepo.GetScored(
-		ctx, chatId, shortTermMemories,
-	)
+	for _, fetch := range fetches {
+		mode, err := fetchMode(&fetch.MemoryFetchRequest)
+		if err != nil || mode == core.FetchModeKeyword {
+			continue
+		}
+		add(fetch.Text)
+		strategy, err := queryStrategy(&fetch.MemoryFetchRequest)
+		if err != nil || strategy != core.QueryStrategyEmbedding {
+			continue
+		}
+		for _, turn := range recentTurns(fetch.Turns) {
+			add(turn.Content)
+		}
+	}
+	embeddings, err := protos.EmbedBatch(texts)
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.Unimplemented:
+		slog.Warn("Batch embedding unavailable, embedding each text", "error", err)
+		return protos.Embed, nil
+	default:
+		return nil, err
+	}
+	if len(embeddings) != len(texts) {
+		return nil, fmt.Errorf(
+			"batch embedding returned %d embeddings for %d texts",
+			len(embeddings), len(texts),
+		)
+	}
+	embedded := make(map[string][]float32, len(texts))
+	for i, text := range texts {
+		embedded[text] = embeddings[i]
+	}
+	return func(text string) ([]float32, error) {
+		if embedding, ok := embedded[text]; ok {
+			return embedding, nil
+		}
+		return protos.Embed(text)
+	}, nil
+}
+
+func (s *MemoryService) fetch(
+	ctx context.Context,
+	chatId string,
+	request *core.MemoryFetchRequest,
+	embed embedFunc,
+) (*core.MemoryFetchResult, error) {
+	result := &core.MemoryFetchResult{}
+	if request.Limit <= 0 {
+		request.Limit = defaultFetchLimit
+	}
+	if request.VectorSearchLimit <= 0 {
+		request.VectorSearchLimit = defaultVectorSearchLimit
+	}
+	limit := request.Limit
+	if request.Diversity < 0 || request.Diversity > 1 {
+		return nil, core.InvalidDiversity
+	}
+	if request.MaxTokens < 0 {
+		return nil, core.InvalidMaxTokens
+	}
+	scorer, err := scoring.New(request.Scorer, request.Weights)
 	if err != nil {
-		slog.Error("Error getting short term memories", "error", err)
-		return memories, err
+		return nil, err
 	}
-	scoredLTMemories, err := s.longTermRepo.GetScored(
-		ctx, chatId, longTermMemories,
-	)
+	counter, err := tokens.New(request.Tokenizer)
 	if err != nil {
-		slog.Error("Error getting long term memories", "error", err)
-		return memories, err
+		return nil, err
+	}
+	candidates, err := s.searchCandidates(ctx, chatId, request, embed)
+	if err != nil {
+		return nil, err
+	}
+	if candidates.query.rewritten {
+		result.Query = candidates.query.text
+	}
+	result.HypotheticalMemory = candidates.query.hypotheticalMemory
+	if request.Explain {
+		result.Rejected, err = s.getScored(
+			ctx, chatId, candidates.rejected, candidates, scorer,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, memory := range result.Rejected {
+			candidates.explain(memory)
+		}
+		// Closest misses first, they are the ones a threshold change would let in
+		sort.SliceStable(result.Rejected, func(i, j int) bool {
+			return *result.Rejected[i].Explanation.VectorSimilarity >
+				*result.Rejected[j].Explanation.VectorSimilarity
+		})
+	}
+	if len(candidates.hits) == 0 {
+		slog.Info("No similar memories found")
+		return result, nil
+	}
+	memories, err := s.getScored(ctx, chatId, candidates.hits, candidates, scorer)
+	if err != nil {
+		return nil, err
 	}
-	memories = append(scoredSTMemories, scoredLTMemories...)
 	if len(memories) == 0 {
-		return memories, nil
+		return result, nil
 	}
-	if limit > len(memories) {
+	// A token budget ranks every candidate and packs as many as fit
+	if limit > len(memories) || request.MaxTokens > 0 {
 		limit = len(memories)
 	}
+	if request.Rerank {
+		if err := rerankMemories(candidates.query.text, memories); err != nil {
+			return nil, err
+		}
+	}
 	// Sort by score
 	sort.SliceStable(
 		memories[:],
@@ -105,6 +321,16 @@ func (s *MemoryService) Fetch(
 		},
 	)
 	finalMemories := memories[:limit]
+	if request.Diversity > 0 {
+		finalMemories = maximalMarginalRelevance(
+			memories, candidates.hits, request.Diversity, limit,
+		)
+	}
+	if request.MaxTokens > 0 {
+		finalMemories, result.TokensUsed = packMemories(
+			finalMemories, counter, request.MaxTokens, request.IncludeRelatedContext,
+		)
+	}
 	for _, memory := range finalMemories {
 		switch memory.MemoryType {
 		case core.ShortTerm:
@@ -113,10 +339,793 @@ func (s *MemoryService) Fetch(
 			s.longTermRepo.RegisterUsage(ctx, chatId, memory.Id)
 
 		}
+		if request.Explain {
+			candidates.explain(memory)
+		} else {
+			memory.Explanation = nil
+		}
+	}
+	result.Memories = finalMemories
+	return result, nil
+}
+
+// Fetches the memories and renders them with a context template
+func (s *MemoryService) RenderContext(
+	ctx context.Context,
+	chatId string,
+	request *core.MemoryContextRequest,
+) (*core.MemoryContext, error) {
+	contextTemplate, err := render.Lookup(request.Template)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.Fetch(ctx, chatId, &request.MemoryFetchRequest)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := contextTemplate.Render(render.Data{
+		Subject:  request.Subject,
+		Memories: result.Memories,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &core.MemoryContext{
+		Context:    rendered,
+		Memories:   result.Memories,
+		TokensUsed: result.TokensUsed,
+	}, nil
+}
+
+// Loads the memories behind the search hits and scores them
+func (s *MemoryService) getScored(
+	ctx context.Context,
+	chatId string,
+	hits []core.ScoredMemoryVector,
+	candidates *fetchCandidates,
+	scorer scoring.Scorer,
+) ([]*core.ScoredMemory, error) {
+	var shortTermIds []string
+	var longTermIds []string
+	for _, memory := range hits {
+		switch memory.Payload.MemoryType {
+		case core.ShortTerm:
+			shortTermIds = append(shortTermIds, memory.Payload.MemoryId)
+		case core.LongTerm:
+			longTermIds = append(longTermIds, memory.Payload.MemoryId)
+		}
+	}
+	shortTermMemories, err := s.shortTermRepo.GetByIds(ctx, chatId, shortTermIds)
+	if err != nil {
+		slog.Error("Error getting short term memories", "error", err)
+		return nil, err
+	}
+	longTermMemories, err := s.longTermRepo.GetByIds(ctx, chatId, longTermIds)
+	if err != nil {
+		slog.Error("Error getting long term memories", "error", err)
+		return nil, err
+	}
+
+	var memories []*core.ScoredMemory
+	var scoringCandidates []scoring.Candidate
+	for _, memory := range shortTermMemories {
+		memories = append(memories, &core.ScoredMemory{
+			Id:             memory.Id,
+			Text:           memory.Memory,
+			MemoryType:     core.ShortTerm,
+			CreatedAt:      memory.CreatedAt,
+			RelatedContext: memory.RelatedContext,
+		})
+		scoringCandidates = append(scoringCandidates, scoring.Candidate{
+			MemoryType:  core.ShortTerm,
+			Similarity:  candidates.similarity(core.ShortTerm, memory.Id),
+			AccessCount: memory.AccessCount,
+			MergeCount:  memory.MergeCount,
+			CreatedAt:   memory.CreatedAt,
+		})
+	}
+	for _, memory := range longTermMemories {
+		memories = append(memories, &core.ScoredMemory{
+			Id:             memory.Id,
+			Text:           memory.Memory,
+			MemoryType:     core.LongTerm,
+			CreatedAt:      memory.CreatedAt,
+			RelatedContext: memory.RelatedContext,
+		})
+		scoringCandidates = append(scoringCandidates, scoring.Candidate{
+			MemoryType:  core.LongTerm,
+			Similarity:  candidates.similarity(core.LongTerm, memory.Id),
+			AccessCount: memory.AccessCount,
+			CreatedAt:   memory.CreatedAt,
+		})
+	}
+	for i, result := range scorer.Score(scoringCandidates, time.Now()) {
+		memories[i].Score = result.Score
+		memories[i].Explanation = &core.MemoryScoreExplanation{
+			ScorerScore:  result.Score,
+			AccessCount:  scoringCandidates[i].AccessCount,
+			MergeCount:   scoringCandidates[i].MergeCount,
+			RecencyScore: result.Recency,
+			UsageScore:   result.Usage,
+		}
+	}
+	return memories, nil
+}
+
+// Finds the candidate memories with the requested search mode.
+// Vector hits are filtered by the thresholds before any fusion,
+// keyword hits have no threshold since their scores are not comparable
+func (s *MemoryService) searchCandidates(
+	ctx context.Context,
+	chatId string,
+	request *core.MemoryFetchRequest,
+	embed embedFunc,
+) (*fetchCandidates, error) {
+	mode, err := fetchMode(request)
+	if err != nil {
+		return nil, err
+	}
+	query, err := s.buildQuery(request, mode != core.FetchModeKeyword, embed)
+	if err != nil {
+		return nil, err
+	}
+	candidates := &fetchCandidates{
+		query:                query,
+		vectorScores:         make(map[memoryKey]float32),
+		keywordScores:        make(map[memoryKey]float32),
+		belowVectorThreshold: make(map[memoryKey]bool),
+		filteredByLongTerm:   make(map[memoryKey]bool),
+	}
+	var vectorHits, keywordHits []core.ScoredMemoryVector
+	if mode != core.FetchModeKeyword {
+		threshold := request.VectorSearchThreshold
+		if request.Explain {
+			threshold = minimumSimilarity
+		}
+		similarMemories, err := s.searchVectors(
+			ctx, chatId, query, request.VectorSearchLimit, threshold,
+		)
+		if err != nil {
+			slog.Error("Error searching memory vector", "error", err)
+			return nil, err
+		}
+		if similarMemories != nil {
+			for _, memory := range similarMemories {
+				key := keyOf(memory.Payload)
+				candidates.vectorScores[key] = memory.Score
+				belowThreshold := memory.Score < request.VectorSearchThreshold
+				filteredByLongTerm := memory.Payload.MemoryType == core.LongTerm &&
+					memory.Score < request.LongTermThreshold
+				if belowThreshold || filteredByLongTerm {
+					candidates.belowVectorThreshold[key] = belowThreshold
+					candidates.filteredByLongTerm[key] = filteredByLongTerm
+					candidates.rejected = append(candidates.rejected, memory)
+					continue
+				}
+				vectorHits = append(vectorHits, memory)
+			}
+		}
+	}
+	if mode != core.FetchModeVector {
+		keywordHits, err = s.keywordRepo.Search(
+			ctx, chatId, query.text, request.VectorSearchLimit,
+		)
+		if err != nil {
+			slog.Error("Error searching memory keywords", "error", err)
+			return nil, err
+		}
+		for _, memory := range keywordHits {
+			candidates.keywordScores[keyOf(memory.Payload)] = memory.Score
+			candidates.maxKeywordScore = max(candidates.maxKeywordScore, memory.Score)
+		}
 	}
-	return finalMemories, nil
+	switch mode {
+	case core.FetchModeKeyword:
+		candidates.hits = keywordHits
+	case core.FetchModeHybrid:
+		fused := reciprocalRankFusion(vectorHits, keywordHits)
+		if request.VectorSearchLimit > 0 && len(fused) > request.VectorSearchLimit {
+			fused = fused[:request.VectorSearchLimit]
+		}
+		candidates.hits = fused
+	default:
+		candidates.hits = vectorHits
+	}
+	return candidates, nil
+}
+
+// What a fetch searches with
+type searchQuery struct {
+	text       string
+	embeddings []float32
+	// Whether the text was rewritten by the llm from the turns
+	rewritten bool
+	// Written by the llm to answer the text when fetching with hyde
+	hypotheticalMemory     string
+	hypotheticalEmbeddings []float32
+}
+
+// The search mode of a fetch, vector by default
+func fetchMode(request *core.MemoryFetchRequest) (core.FetchMode, error) {
+	mode := request.Mode
+	if mode == "" {
+		mode = core.FetchModeVector
+	}
+	if mode != core.FetchModeVector &&
+		mode != core.FetchModeKeyword &&
+		mode != core.FetchModeHybrid {
+		return "", core.InvalidFetchMode
+	}
+	return mode, nil
+}
+
+// The query strategy of a fetch, the configured one by default
+func queryStrategy(request *core.MemoryFetchRequest) (core.QueryStrategy, error) {
+	strategy := request.QueryStrategy
+	if strategy == "" {
+		strategy = core.QueryStrategy(config.Conversation.QueryStrategy)
+	}
+	if strategy != core.QueryStrategyEmbedding && strategy != core.QueryStrategyRewrite {
+		return "", core.InvalidQueryStrategy
+	}
+	return strategy, nil
+}
+
+// The turns a query is built from, older ones are dropped
+func recentTurns(turns []core.ConversationTurn) []core.ConversationTurn {
+	if maxTurns := config.Conversation.MaxTurns; len(turns) > maxTurns {
+		return turns[len(turns)-maxTurns:]
+	}
+	return turns
+}
+
+// Builds the query of a fetch. Without turns it is the text, otherwise the turns
+// are folded in by the query strategy. Embeddings are skipped for keyword searches
+func (s *MemoryService) buildQuery(
+	request *core.MemoryFetchRequest,
+	withEmbeddings bool,
+	embed embedFunc,
+) (*searchQuery, error) {
+	strategy, err := queryStrategy(request)
+	if err != nil {
+		return nil, err
+	}
+	query := &searchQuery{text: request.Text}
+	turns := recentTurns(request.Turns)
+	if len(turns) > 0 && strategy == core.QueryStrategyRewrite {
+		if !s.queryRewriteService.IsWorking() {
+			slog.Warn("No llm provider to rewrite the query, using the embedding strategy")
+		} else if rewritten, err := s.queryRewriteService.Rewrite(request.Text, turns); err != nil {
+			slog.Error("Error rewriting query, using the embedding strategy", "error", err)
+		} else {
+			query.text = rewritten
+			query.rewritten = true
+			turns = nil
+		}
+	}
+	if !withEmbeddings {
+		return query, nil
+	}
+	query.embeddings, err = weightedEmbedding(embed, query.text, turns)
+	if err != nil {
+		return nil, err
+	}
+	if request.Hyde {
+		if !s.enhancementService.IsWorking() {
+			slog.Warn("No llm provider to write a hypothetical memory, searching with the query only")
+		} else if memory, err := s.enhancementService.HypotheticalMemory(query.text); err != nil {
+			slog.Error("Error writing hypothetical memory, searching with the query only", "error", err)
+		} else if query.hypotheticalEmbeddings, err = embed(memory); err != nil {
+			return nil, err
+		} else {
+			query.hypotheticalMemory = memory
+		}
+	}
+	return query, nil
+}
+
+// Searches the vectors with the query and, with hyde, the hypothetical memory.
+// Both are cosine similarities so each memory keeps the best of its two scores
+func (s *MemoryService) searchVectors(
+	ctx context.Context,
+	chatId string,
+	query *searchQuery,
+	limit int,
+	threshold float32,
+) ([]core.ScoredMemoryVector, error) {
+	vectorService := NewMemoryVectorService(s.vectorRepo)
+	similarMemories, err := vectorService.SearchMemoryVector(
+		ctx, chatId, query.embeddings, limit, threshold,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var merged []core.ScoredMemoryVector
+	if similarMemories != nil {
+		merged = *similarMemories
+	}
+	if query.hypotheticalEmbeddings == nil {
+		return merged, nil
+	}
+	hypotheticalMemories, err := vectorService.SearchMemoryVector(
+		ctx, chatId, query.hypotheticalEmbeddings, limit, threshold,
+	)
+	if err != nil {
+		return nil, err
+	}
+	positions := make(map[memoryKey]int, len(merged))
+	for i, memory := range merged {
+		positions[keyOf(memory.Payload)] = i
+	}
+	if hypotheticalMemories != nil {
+		for _, memory := range *hypotheticalMemories {
+			i, ok := positions[keyOf(memory.Payload)]
+			if !ok {
+				positions[keyOf(memory.Payload)] = len(merged)
+				merged = append(merged, memory)
+				continue
+			}
+			if memory.Score > merged[i].Score {
+				merged[i] = memory
+			}
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Score > merged[j].Score
+	})
+	if limit > 0 && len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged, nil
+}
+
+// Embedding of the text blended with the ones of the turns, each turn weighing
+// TurnDecay times the one after it, normalized back to unit length
+func weightedEmbedding(
+	embed embedFunc,
+	text string,
+	turns []core.ConversationTurn,
+) ([]float32, error) {
+	textEmbeddings, err := embed(text)
+	if err != nil || len(turns) == 0 {
+		return textEmbeddings, err
+	}
+	// Blended into a copy, the text embeddings may be shared by a batch
+	embeddings := append([]float32(nil), textEmbeddings...)
+	weight := float32(1)
+	for i := len(turns) - 1; i >= 0; i-- {
+		weight *= config.Conversation.TurnDecay
+		if turns[i].Content == "" {
+			continue
+		}
+		turnEmbeddings, err := embed(turns[i].Content)
+		if err != nil {
+			return nil, err
+		}
+		if len(turnEmbeddings) != len(embeddings) {
+			return nil, fmt.Errorf(
+				"turn embedding has %d dimensions, expected %d",
+				len(turnEmbeddings), len(embeddings),
+			)
+		}
+		for j := range embeddings {
+			embeddings[j] += weight * turnEmbeddings[j]
+		}
+	}
+	var norm float64
+	for _, value := range embeddings {
+		norm += float64(value) * float64(value)
+	}
+	if norm == 0 {
+		return embeddings, nil
+	}
+	norm = math.Sqrt(norm)
+	for j := range embeddings {
+		embeddings[j] = float32(float64(embeddings[j]) / norm)
+	}
+	return embeddings, nil
+}
+
+// Merges ranked lists into one, scoring each memory by the sum
+// of 1 / (k + rank) over the lists it appears in
+func reciprocalRankFusion(rankings ...[]core.ScoredMemoryVector) []core.ScoredMemoryVector {
+	var fused []core.ScoredMemoryVector
+	positions := make(map[memoryKey]int)
+	for _, ranking := range rankings {
+		for rank, memory := range ranking {
+			score := 1 / float32(rrfK+rank+1)
+			key := keyOf(memory.Payload)
+			if position, ok := positions[key]; ok {
+				fused[position].Score += score
+				if fused[position].Vectors == nil {
+					fused[position].Vectors = memory.Vectors
+				}
+				continue
+			}
+			positions[key] = len(fused)
+			memory.Score = score
+			fused = append(fused, memory)
+		}
+	}
+	sort.SliceStable(fused, func(i, j int) bool {
+		return fused[i].Score > fused[j].Score
+	})
+	return fused
+}
+
+// Takes the memories in order while they fit in the budget, skipping the ones
+// that are too big for what is left so smaller ones further down can still fit.
+// Related context left out of the budget is left out of the memories too
+func packMemories(
+	memories []*core.ScoredMemory,
+	counter tokens.Counter,
+	maxTokens int,
+	withRelatedContext bool,
+) ([]*core.ScoredMemory, int) {
+	var packed []*core.ScoredMemory
+	used := 0
+	for _, memory := range memories {
+		count := tokens.CountMemory(counter, memory, withRelatedContext)
+		if used+count > maxTokens {
+			continue
+		}
+		if !withRelatedContext {
+			memory.RelatedContext = nil
+		}
+		memory.Tokens = count
+		used += count
+		packed = append(packed, memory)
+	}
+	return packed, used
+}
+
+// Replaces the scores with the cross-encoder ones. An inference
+// service without a reranker model keeps the scores as they are
+func rerankMemories(query string, memories []*core.ScoredMemory) error {
+	candidates := make([]string, len(memories))
+	for i, memory := range memories {
+		candidates[i] = memory.Text
+	}
+	scores, err := protos.Rerank(query, candidates)
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.FailedPrecondition, codes.Unimplemented:
+		slog.Warn("Reranker unavailable, keeping the relevance scores", "error", err)
+		return nil
+	default:
+		slog.Error("Error reranking memories", "error", err)
+		return err
+	}
+	for i, memory := range memories {
+		memory.Score = scores[i]
+		if memory.Explanation != nil {
+			memory.Explanation.RerankScore = &scores[i]
+		}
+	}
+	return nil
+}
+
+// Picks limit memories one at a time, each maximizing
+// (1 - diversity) * score - diversity * (highest similarity to the picked ones).
+// Similarity is the cosine between the vectors the candidates were found with,
+// a memory found only by keyword has no vector and is never penalized
+func maximalMarginalRelevance(
+	memories []*core.ScoredMemory,
+	candidates []core.ScoredMemoryVector,
+	diversity float32,
+	limit int,
+) []*core.ScoredMemory {
+	vectors := make(map[memoryKey][]float32)
+	for _, candidate := range candidates {
+		if len(candidate.Vectors) > 0 {
+			vectors[keyOf(candidate.Payload)] = candidate.Vectors
+		}
+	}
+	remaining := append([]*core.ScoredMemory{}, memories...)
+	selected := make([]*core.ScoredMemory, 0, limit)
+	for len(selected) < limit && len(remaining) > 0 {
+		best, bestScore := 0, float32(0)
+		for i, memory := range remaining {
+			var redundancy float32
+			vector := vectors[memoryKey{memory.MemoryType, memory.Id}]
+			for _, picked := range selected {
+				similarity := cosineSimilarity(
+					vector, vectors[memoryKey{picked.MemoryType, picked.Id}],
+				)
+				redundancy = max(redundancy, similarity)
+			}
+			score := (1-diversity)*memory.Score - diversity*redundancy
+			if i == 0 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		selected = append(selected, remaining[best])
+		remaining = append(remaining[:best], remaining[best+1:]...)
+	}
+	return selected
+}
+
+// Zero when either vector is missing or their sizes differ
+func cosineSimilarity(a, b []float32) float32 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB)))
+}
+
+// Stores a memory directly, skipping the classifier.
+// Like the classification task, a short term memory is merged into a similar
+// short term memory and a memory similar to an existing one is not duplicated
+func (s *MemoryService) Create(
+	ctx context.Context,
+	chatId string,
+	request *core.NewMemory,
+) (*core.MemoryStoreResult, error) {
+	if request.Memory == "" {
+		return nil, core.EmptyMemory
+	}
+	if request.MemoryType != core.ShortTerm && request.MemoryType != core.LongTerm {
+		return nil, core.InvalidMemoryType
+	}
+	embeddings, err := protos.Embed(request.Memory)
+	if err != nil {
+		return nil, err
+	}
+	vectorService := NewMemoryVectorService(s.vectorRepo)
+	similarMemory, err := vectorService.FindSimilarMemory(ctx, chatId, embeddings)
+	if err != nil {
+		return nil, err
+	}
+	if similarMemory != nil {
+		slog.Info("Similar memory found", "memory", request.Memory)
+		result := &core.MemoryStoreResult{
+			MemoryId:   similarMemory.Payload.MemoryId,
+			MemoryType: similarMemory.Payload.MemoryType,
+		}
+		if request.MemoryType == core.ShortTerm &&
+			similarMemory.Payload.MemoryType == core.ShortTerm {
+			merged, err := mergeShortTerm(
+				ctx,
+				s.uow,
+				chatId,
+				similarMemory.Payload.MemoryId,
+				request.Memory,
+				request.RelatedContext,
+				similarMemory.Score,
+			)
+			if err != nil {
+				return nil, err
+			}
+			result.Merged = true
+			s.eventService.Emit(ctx, chatId, core.MemoryEvent{
+				Type:       core.MemoryMergedEvent,
+				MemoryId:   merged.Id,
+				MemoryType: core.ShortTerm,
+				Memory:     merged.Memory,
+			})
+		}
+		return result, nil
+	}
+
+	if err := s.tenantService.CheckMemoryQuota(ctx); err != nil {
+		return nil, err
+	}
+	var memoryId string
+	switch request.MemoryType {
+	case core.ShortTerm:
+		memory, err := s.shortTermRepo.Create(ctx, &core.NewShortTermMemory{
+			Memory:         request.Memory,
+			ChatId:         chatId,
+			CreatedAt:      time.Now(),
+			Active:         true,
+			RelatedContext: request.RelatedContext,
+		})
+		if err != nil {
+			return nil, err
+		}
+		memoryId = memory.Id
+	case core.LongTerm:
+		memory, err := s.longTermRepo.Create(ctx, &core.NewLongTermMemory{
+			Memory:         request.Memory,
+			ChatId:         chatId,
+			CreatedAt:      time.Now(),
+			Active:         true,
+			RelatedContext: request.RelatedContext,
+		})
+		if err != nil {
+			return nil, err
+		}
+		memoryId = memory.Id
+	}
+	if err := vectorService.CreateMemoryVector(
+		ctx, chatId, embeddings, request.MemoryType, memoryId,
+	); err != nil {
+		slog.Error("Error creating memory vector", "error", err)
+		return nil, err
+	}
+	s.eventService.Emit(ctx, chatId, core.MemoryEvent{
+		Type:       core.MemoryCreatedEvent,
+		MemoryId:   memoryId,
+		MemoryType: request.MemoryType,
+		Memory:     request.Memory,
+	})
+	return &core.MemoryStoreResult{
+		MemoryId:   memoryId,
+		MemoryType: request.MemoryType,
+		Created:    true,
+	}, nil
 }
 
 func (s *MemoryService) DeactivateAll(ctx context.Context, chatId string) error {
-	return s.vectorRepo.DeactivateAll(ctx, chatId)
+	if err := s.vectorRepo.DeactivateAll(ctx, chatId); err != nil {
+		return err
+	}
+	s.eventService.Emit(ctx, chatId, core.MemoryEvent{Type: core.MemoryDeactivatedEvent})
+	return nil
+}
+
+// Embeds the new text of an update, returns nil embeddings when
+// the text is not being changed
+func (s *MemoryService) embedUpdate(request *core.MemoryUpdateRequest) ([]float32, error) {
+	if request.Memory == "" && request.RelatedContext == nil {
+		return nil, core.EmptyMemoryUpdate
+	}
+	if request.Memory == "" {
+		return nil, nil
+	}
+	return protos.Embed(request.Memory)
+}
+
+// Runs the update of a memory with its new vector. The vector goes first
+// so a memory without one is not changed, and the previous vector is put
+// back when the update fails, keeping the text and the vector in sync
+func (s *MemoryService) updateWithVector(
+	ctx context.Context,
+	chatId string,
+	memoryId string,
+	embeddings []float32,
+	update func() error,
+) error {
+	if embeddings == nil {
+		return update()
+	}
+	previous, err := s.vectorRepo.GetByMemoryIds(ctx, []string{memoryId})
+	if err != nil {
+		return err
+	}
+	if err := s.vectorRepo.Update(ctx, chatId, embeddings, memoryId); err != nil {
+		slog.Error("Error updating memory vector", "error", err)
+		return err
+	}
+	if err := update(); err != nil {
+		if vector, ok := previous[memoryId]; ok {
+			if err := s.vectorRepo.Update(ctx, chatId, vector, memoryId); err != nil {
+				slog.Error("Error restoring memory vector", "memory_id", memoryId, "error", err)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// Updates a short term memory and its vector
+func (s *MemoryService) UpdateShortTerm(
+	ctx context.Context,
+	chatId string,
+	memoryId string,
+	request *core.MemoryUpdateRequest,
+) (*core.ShortTermMemory, error) {
+	embeddings, err := s.embedUpdate(request)
+	if err != nil {
+		return nil, err
+	}
+	var memory *core.ShortTermMemory
+	err = s.updateWithVector(ctx, chatId, memoryId, embeddings, func() (err error) {
+		memory, err = s.shortTermRepo.Update(
+			ctx, chatId, memoryId, request.Memory, request.RelatedContext,
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return memory, nil
+}
+
+// Updates a long term memory and its vector
+func (s *MemoryService) UpdateLongTerm(
+	ctx context.Context,
+	chatId string,
+	memoryId string,
+	request *core.MemoryUpdateRequest,
+) (*core.LongTermMemory, error) {
+	embeddings, err := s.embedUpdate(request)
+	if err != nil {
+		return nil, err
+	}
+	var memory *core.LongTermMemory
+	err = s.updateWithVector(ctx, chatId, memoryId, embeddings, func() (err error) {
+		memory, err = s.longTermRepo.Update(
+			ctx, chatId, memoryId, request.Memory, request.RelatedContext,
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return memory, nil
+}
+
+// Returns every merge of a short term memory
+func (s *MemoryService) GetMergeHistory(
+	ctx context.Context, chatId string, memoryId string,
+) (*core.MemoryMergeHistory, error) {
+	if _, err := s.shortTermRepo.GetById(ctx, chatId, memoryId); err != nil {
+		return nil, err
+	}
+	merges, err := s.mergeRepo.GetByMemoryId(ctx, memoryId)
+	if err != nil {
+		return nil, err
+	}
+	return &core.MemoryMergeHistory{MemoryId: memoryId, Merges: merges}, nil
+}
+
+// Restores a short term memory to what it was before the given merge
+func (s *MemoryService) RevertMerge(
+	ctx context.Context, chatId string, memoryId string, mergeId string,
+) (*core.ShortTermMemory, error) {
+	if _, err := s.shortTermRepo.GetById(ctx, chatId, memoryId); err != nil {
+		return nil, err
+	}
+	merge, err := s.mergeRepo.GetById(ctx, memoryId, mergeId)
+	if err != nil {
+		return nil, err
+	}
+	return s.UpdateShortTerm(ctx, chatId, memoryId, &core.MemoryUpdateRequest{
+		Memory:         merge.PreviousMemory,
+		RelatedContext: merge.PreviousRelatedContext,
+	})
+}
+
+// Deactivates a single memory and its vector
+func (s *MemoryService) Deactivate(
+	ctx context.Context,
+	chatId string,
+	memoryType core.MemoryTypeEnum,
+	memoryId string,
+) error {
+	switch memoryType {
+	case core.ShortTerm:
+		if _, err := s.shortTermRepo.GetById(ctx, chatId, memoryId); err != nil {
+			return err
+		}
+		if err := s.shortTermRepo.Deactivate(ctx, chatId, memoryId); err != nil {
+			return err
+		}
+	case core.LongTerm:
+		if _, err := s.longTermRepo.GetById(ctx, chatId, memoryId); err != nil {
+			return err
+		}
+		if err := s.longTermRepo.Deactivate(ctx, chatId, memoryId); err != nil {
+			return err
+		}
+	}
+	if err := s.vectorRepo.Deactivate(ctx, chatId, memoryId); err != nil {
+		return err
+	}
+	s.eventService.Emit(ctx, chatId, core.MemoryEvent{
+		Type:       core.MemoryDeactivatedEvent,
+		MemoryId:   memoryId,
+		MemoryType: memoryType,
+	})
+	return nil
 }
@@ -3,7 +3,9 @@ package service
 import (
 	"fmt"
 	"github.com/Mateus-Lacerda/better-mem/internal/llm"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
 	"log/slog"
+	"strings"
 )
 
 type MemoryEnhancementService struct {
@@ -28,3 +30,19 @@ func (m MemoryEnhancementService) EnhanceMemory(memory string) string {
 	slog.Info("Memory enhancement completed", "enhancedMemory", enhancedMemory)
 	return enhancedMemory
 }
+
+// Writes a memory that would answer the query, it reads like the stored
+// memories so it embeds closer to them than a short question does
+func (m MemoryEnhancementService) HypotheticalMemory(query string) (string, error) {
+	prompt := fmt.Sprintf(llm.HypotheticalMemoryPrompt, query)
+	memory, err := m.llmProvider.GetCompletion(prompt)
+	if err != nil {
+		return "", err
+	}
+	memory = strings.TrimSpace(memory)
+	if memory == "" {
+		return "", core.EmptyHypotheticalMemory
+	}
+	slog.Info("Hypothetical memory completed", "query", query, "memory", memory)
+	return memory, nil
+}
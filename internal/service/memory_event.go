@@ -0,0 +1,96 @@
+package service
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
+	"github.com/Mateus-Lacerda/better-mem/internal/pubsub"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"github.com/Mateus-Lacerda/better-mem/internal/task"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Publishes what happens to the memories: the events are appended to the
+// event log read by the streams and the worker delivers them to the webhooks
+type MemoryEventService struct {
+	repo repository.MemoryEventRepository
+}
+
+func NewMemoryEventService(repo repository.MemoryEventRepository) *MemoryEventService {
+	return &MemoryEventService{repo: repo}
+}
+
+// Emits an event about a memory of the chat, failing to emit is only
+// logged since the memory itself was already changed
+func (s *MemoryEventService) Emit(ctx context.Context, chatId string, event core.MemoryEvent) {
+	event.Id = uuid.New().String()
+	event.TenantId = core.TenantFromContext(ctx)
+	event.OccurredAt = time.Now()
+	if err := s.repo.Create(ctx, chatId, &event); err != nil {
+		slog.Error("error storing memory event", "type", event.Type, "memory_id", event.MemoryId, "error", err)
+	} else if err := pubsub.Publish(ctx, event.TenantId, chatId, event.Cursor); err != nil {
+		// The streams still find the event on their next read
+		slog.Warn("error publishing memory event", "type", event.Type, "memory_id", event.MemoryId, "error", err)
+	}
+	if err := task.NewDispatchWebhookTask(task.DispatchWebhookPayload{
+		Event:  event,
+		ChatId: chatId,
+	}); err != nil {
+		slog.Error(
+			"error emitting memory event",
+			"type", event.Type,
+			"memory_id", event.MemoryId,
+			"error", err,
+		)
+	}
+}
+
+// Returns the cursor of the latest event of the chat, streaming from it
+// skips everything that already happened
+func (s *MemoryEventService) LastCursor(ctx context.Context, chatId string) (int64, error) {
+	return s.repo.LastCursor(ctx, chatId)
+}
+
+// Streams the events of the chat that come after the cursor, first the
+// ones already in the log and then the new ones as they are emitted.
+// The channel is closed once ctx is done
+func (s *MemoryEventService) Stream(
+	ctx context.Context, chatId string, cursor int64,
+) (<-chan core.MemoryEvent, error) {
+	// Subscribing before reading the log leaves no gap between both
+	wake, err := pubsub.Subscribe(ctx, core.TenantFromContext(ctx), chatId)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan core.MemoryEvent)
+	go func() {
+		defer close(events)
+		for {
+			page, err := s.repo.GetSince(ctx, chatId, cursor, config.Events.PageSize)
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.Error("error reading memory events", "chat_id", chatId, "error", err)
+				}
+				return
+			}
+			for _, event := range page {
+				select {
+				case events <- *event:
+					cursor = event.Cursor
+				case <-ctx.Done():
+					return
+				}
+			}
+			if len(page) == config.Events.PageSize {
+				continue
+			}
+			if _, ok := <-wake; !ok {
+				return
+			}
+		}
+	}()
+	return events, nil
+}
@@ -9,11 +9,27 @@ import (
 )
 
 type MemoryManagementService struct {
-	uow uow.UnitOfWork[int, any]
+	uow          uow.UnitOfWork[int, any]
+	eventService *MemoryEventService
 }
 
-func NewMemoryManagementService(uow uow.UnitOfWork[int, any]) *MemoryManagementService {
-	return &MemoryManagementService{uow: uow}
+func NewMemoryManagementService(
+	uow uow.UnitOfWork[int, any],
+	eventService *MemoryEventService,
+) *MemoryManagementService {
+	return &MemoryManagementService{uow: uow, eventService: eventService}
+}
+
+// Emits the events once the unit of work went through
+func (s *MemoryManagementService) emitAll(
+	ctx context.Context, chatId string, events []core.MemoryEvent, err error,
+) {
+	if err != nil {
+		return
+	}
+	for _, event := range events {
+		s.eventService.Emit(ctx, chatId, event)
+	}
 }
 
 func (s *MemoryManagementService) FindAndDeactivate(
@@ -22,7 +38,10 @@ func (s *MemoryManagementService) FindAndDeactivate(
 	ageLimitHours int,
 	minimalRelevance int,
 ) (int, error) {
-	return s.uow.Do(ctx, func(repos repository.AllRepositories) (int, error) {
+	var events []core.MemoryEvent
+	deactivated, err := s.uow.Do(ctx, func(ctx context.Context, repos repository.AllRepositories) (int, error) {
+		// The unit of work may run the function again
+		events = nil
 		endTimeWindow := time.Until(time.Now().Add(time.Duration(ageLimitHours) * time.Hour))
 		memories, err := repos.ShortTermMemory.GetElligibleForDeactivation(
 			ctx, chatId, endTimeWindow, minimalRelevance,
@@ -35,10 +54,18 @@ func (s *MemoryManagementService) FindAndDeactivate(
 			if err := repos.ShortTermMemory.Deactivate(ctx, chatId, memory.Id); err != nil {
 				return deactivated, err
 			}
+			events = append(events, core.MemoryEvent{
+				Type:       core.MemoryDiscardedEvent,
+				MemoryId:   memory.Id,
+				MemoryType: core.ShortTerm,
+				Memory:     memory.Memory,
+			})
 			deactivated++
 		}
 		return deactivated, nil
 	})
+	s.emitAll(ctx, chatId, events, err)
+	return deactivated, err
 }
 
 func (s *MemoryManagementService) FindAndPromote(
@@ -47,7 +74,9 @@ func (s *MemoryManagementService) FindAndPromote(
 	minimalRelevance int,
 	longTermThreshold float32,
 ) (int, error) {
-	return s.uow.Do(ctx, func(repos repository.AllRepositories) (int, error) {
+	var events []core.MemoryEvent
+	promoted, err := s.uow.Do(ctx, func(ctx context.Context, repos repository.AllRepositories) (int, error) {
+		events = nil
 		memories, err := repos.ShortTermMemory.GetElligibleForPromotion(
 			ctx, chatId, minimalRelevance,
 		)
@@ -55,7 +84,7 @@ func (s *MemoryManagementService) FindAndPromote(
 			return 0, err
 		}
 		for _, memory := range memories {
-			memory, err := repos.LongTermMemory.Create(
+			longTermMemory, err := repos.LongTermMemory.Create(
 				ctx,
 				&core.NewLongTermMemory{
 					Memory:      memory.Memory,
@@ -71,7 +100,20 @@ func (s *MemoryManagementService) FindAndPromote(
 			if err := repos.ShortTermMemory.Deactivate(ctx, chatId, memory.Id); err != nil {
 				return 0, err
 			}
+			// Keep the provenance of the memory
+			if err := repos.Message.ReassignMemory(ctx, memory.Id, longTermMemory.Id); err != nil {
+				return 0, err
+			}
+			events = append(events, core.MemoryEvent{
+				Type:         core.MemoryPromotedEvent,
+				MemoryId:     longTermMemory.Id,
+				MemoryType:   core.LongTerm,
+				Memory:       longTermMemory.Memory,
+				PromotedFrom: memory.Id,
+			})
 		}
 		return len(memories), nil
 	})
+	s.emitAll(ctx, chatId, events, err)
+	return promoted, err
 }
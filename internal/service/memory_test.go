@@ -0,0 +1,249 @@
+package service
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/tokens"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"math"
+	"testing"
+)
+
+func scoredVector(memoryType core.MemoryTypeEnum, id string, vectors ...float32) core.ScoredMemoryVector {
+	return core.ScoredMemoryVector{
+		Vectors: vectors,
+		Payload: core.MemoryPayload{MemoryType: memoryType, MemoryId: id},
+	}
+}
+
+func TestReciprocalRankFusion(t *testing.T) {
+	rank := func(position int) float32 { return 1 / float32(rrfK+position) }
+	tests := []struct {
+		name     string
+		rankings [][]core.ScoredMemoryVector
+		want     []memoryKey
+		scores   []float32
+	}{
+		{
+			name: "no rankings",
+		},
+		{
+			name: "one ranking keeps its order",
+			rankings: [][]core.ScoredMemoryVector{{
+				scoredVector(core.ShortTerm, "a"),
+				scoredVector(core.ShortTerm, "b"),
+			}},
+			want:   []memoryKey{{core.ShortTerm, "a"}, {core.ShortTerm, "b"}},
+			scores: []float32{rank(1), rank(2)},
+		},
+		{
+			name: "memories in both rankings add up",
+			rankings: [][]core.ScoredMemoryVector{
+				{scoredVector(core.ShortTerm, "a"), scoredVector(core.ShortTerm, "b")},
+				{scoredVector(core.ShortTerm, "c"), scoredVector(core.ShortTerm, "b")},
+			},
+			want: []memoryKey{
+				{core.ShortTerm, "b"}, {core.ShortTerm, "a"}, {core.ShortTerm, "c"},
+			},
+			scores: []float32{rank(2) + rank(2), rank(1), rank(1)},
+		},
+		{
+			name: "same id of another memory type is another memory",
+			rankings: [][]core.ScoredMemoryVector{
+				{scoredVector(core.ShortTerm, "a")},
+				{scoredVector(core.LongTerm, "a")},
+			},
+			want:   []memoryKey{{core.ShortTerm, "a"}, {core.LongTerm, "a"}},
+			scores: []float32{rank(1), rank(1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fused := reciprocalRankFusion(tt.rankings...)
+			if len(fused) != len(tt.want) {
+				t.Fatalf("got %d memories, want %d", len(fused), len(tt.want))
+			}
+			for i, memory := range fused {
+				if key := keyOf(memory.Payload); key != tt.want[i] {
+					t.Errorf("memory %d is %v, want %v", i, key, tt.want[i])
+				}
+				if math.Abs(float64(memory.Score-tt.scores[i])) > 1e-6 {
+					t.Errorf("memory %d scored %v, want %v", i, memory.Score, tt.scores[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReciprocalRankFusionKeepsVectors(t *testing.T) {
+	fused := reciprocalRankFusion(
+		[]core.ScoredMemoryVector{scoredVector(core.ShortTerm, "a")},
+		[]core.ScoredMemoryVector{scoredVector(core.ShortTerm, "a", 1, 0)},
+	)
+	if len(fused) != 1 || len(fused[0].Vectors) != 2 {
+		t.Fatalf("got %+v, want one memory with the vectors of the second ranking", fused)
+	}
+}
+
+func TestMaximalMarginalRelevance(t *testing.T) {
+	memories := func() []*core.ScoredMemory {
+		return []*core.ScoredMemory{
+			{Id: "a", MemoryType: core.ShortTerm, Score: 0.9},
+			{Id: "b", MemoryType: core.ShortTerm, Score: 0.85},
+			{Id: "c", MemoryType: core.ShortTerm, Score: 0.5},
+		}
+	}
+	// b is a near duplicate of a, c points elsewhere
+	candidates := []core.ScoredMemoryVector{
+		scoredVector(core.ShortTerm, "a", 1, 0),
+		scoredVector(core.ShortTerm, "b", 0.99, 0.1),
+		scoredVector(core.ShortTerm, "c", 0, 1),
+	}
+	tests := []struct {
+		name       string
+		candidates []core.ScoredMemoryVector
+		diversity  float32
+		limit      int
+		want       []string
+	}{
+		{
+			name:       "no diversity keeps the score order",
+			candidates: candidates,
+			diversity:  0,
+			limit:      3,
+			want:       []string{"a", "b", "c"},
+		},
+		{
+			name:       "diversity skips the near duplicate",
+			candidates: candidates,
+			diversity:  0.5,
+			limit:      2,
+			want:       []string{"a", "c"},
+		},
+		{
+			name:       "memories without vectors are not penalized",
+			candidates: nil,
+			diversity:  0.5,
+			limit:      2,
+			want:       []string{"a", "b"},
+		},
+		{
+			name:       "limit over the memories returns them all",
+			candidates: candidates,
+			diversity:  0.5,
+			limit:      10,
+			want:       []string{"a", "c", "b"},
+		},
+		{
+			name:       "zero limit",
+			candidates: candidates,
+			diversity:  0.5,
+			limit:      0,
+			want:       []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected := maximalMarginalRelevance(memories(), tt.candidates, tt.diversity, tt.limit)
+			if len(selected) != len(tt.want) {
+				t.Fatalf("got %d memories, want %d", len(selected), len(tt.want))
+			}
+			for i, memory := range selected {
+				if memory.Id != tt.want[i] {
+					t.Errorf("memory %d is %s, want %s", i, memory.Id, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float32
+		want float32
+	}{
+		{name: "same direction", a: []float32{1, 2}, b: []float32{2, 4}, want: 1},
+		{name: "orthogonal", a: []float32{1, 0}, b: []float32{0, 1}, want: 0},
+		{name: "opposite", a: []float32{1, 0}, b: []float32{-1, 0}, want: -1},
+		{name: "missing vector", a: nil, b: []float32{1, 0}, want: 0},
+		{name: "sizes differ", a: []float32{1, 0}, b: []float32{1, 0, 0}, want: 0},
+		{name: "zero vector", a: []float32{0, 0}, b: []float32{1, 0}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cosineSimilarity(tt.a, tt.b)
+			if math.Abs(float64(got-tt.want)) > 1e-6 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackMemories(t *testing.T) {
+	counter, err := tokens.New(tokens.CharsTokenizer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two, four and one tokens of text, the related context adds three more
+	memories := func() []*core.ScoredMemory {
+		return []*core.ScoredMemory{
+			{Id: "a", Text: "abcdefgh"},
+			{Id: "b", Text: "abcdefghijklmnop"},
+			{
+				Id:   "c",
+				Text: "abc",
+				RelatedContext: []core.MessageRelatedContext{
+					{User: "user", Context: "abcdefgh"},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name               string
+		maxTokens          int
+		withRelatedContext bool
+		want               []string
+		used               int
+	}{
+		{name: "everything fits", maxTokens: 100, want: []string{"a", "b", "c"}, used: 7},
+		{name: "skips what is too big for what is left", maxTokens: 4, want: []string{"a", "c"}, used: 3},
+		{name: "nothing fits", maxTokens: 0, want: []string{}, used: 0},
+		{
+			name:               "related context counts against the budget",
+			maxTokens:          7,
+			withRelatedContext: true,
+			want:               []string{"a", "b"},
+			used:               6,
+		},
+		{
+			name:               "related context kept with room for it",
+			maxTokens:          10,
+			withRelatedContext: true,
+			want:               []string{"a", "b", "c"},
+			used:               10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packed, used := packMemories(memories(), counter, tt.maxTokens, tt.withRelatedContext)
+			if used != tt.used {
+				t.Errorf("used %d tokens, want %d", used, tt.used)
+			}
+			if len(packed) != len(tt.want) {
+				t.Fatalf("got %d memories, want %d", len(packed), len(tt.want))
+			}
+			total := 0
+			for i, memory := range packed {
+				if memory.Id != tt.want[i] {
+					t.Errorf("memory %d is %s, want %s", i, memory.Id, tt.want[i])
+				}
+				if !tt.withRelatedContext && memory.RelatedContext != nil {
+					t.Errorf("memory %s kept its related context", memory.Id)
+				}
+				total += memory.Tokens
+			}
+			if total != used {
+				t.Errorf("memory tokens add up to %d, want %d", total, used)
+			}
+		})
+	}
+}
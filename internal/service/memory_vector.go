@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
 	"github.com/Mateus-Lacerda/better-mem/pkg/core"
 	"github.com/Mateus-Lacerda/better-mem/internal/repository/vector"
 )
@@ -34,3 +35,25 @@ func (s *MemoryVectorService) SearchMemoryVector(
 ) (*[]core.ScoredMemoryVector, error) {
 	return s.repo.Search(ctx, chatId, vector, limit, threshold)
 }
+
+// Returns the most similar memory above the merge threshold, if any
+func (s *MemoryVectorService) FindSimilarMemory(
+	ctx context.Context,
+	chatId string,
+	vector []float32,
+) (*core.ScoredMemoryVector, error) {
+	similarMemory, err := s.repo.Search(
+		ctx,
+		chatId,
+		vector,
+		1,
+		config.MemoryManagement.MemorySimilarityThreshold,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if similarMemory == nil || len(*similarMemory) == 0 {
+		return nil, nil
+	}
+	return &(*similarMemory)[0], nil
+}
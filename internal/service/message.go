@@ -2,16 +2,213 @@ package service
 
 import (
 	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
 	"github.com/Mateus-Lacerda/better-mem/internal/task"
+	"context"
 	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
 )
 
-func AddMessage(chatId, message string, relatedContext []core.MessageRelatedContext) error {
-	err := task.NewClassifyMessageTask(chatId, message, relatedContext)
+// How often a waiting request checks on its message
+const messageWaitPollInterval = 100 * time.Millisecond
+
+type MessageService struct {
+	repo     repository.MessageRepository
+	chatRepo repository.ChatRepository
+}
+
+func NewMessageService(
+	repo repository.MessageRepository,
+	chatRepo repository.ChatRepository,
+) *MessageService {
+	return &MessageService{repo: repo, chatRepo: chatRepo}
+}
+
+// Sends the message to the classification queue,
+// returns the id of the task tracking it
+func (s *MessageService) Add(
+	ctx context.Context, chatId, message string, relatedContext []core.MessageRelatedContext,
+) (string, error) {
+	taskId := uuid.New().String()
+	if err := s.repo.Create(ctx, &core.Message{
+		Id:             taskId,
+		ChatId:         chatId,
+		Message:        message,
+		RelatedContext: relatedContext,
+		Status:         core.MessageQueued,
+		CreatedAt:      time.Now(),
+	}); err != nil {
+		return "", err
+	}
+	err := task.NewClassifyMessageTask(
+		taskId, core.TenantFromContext(ctx), chatId, message, relatedContext,
+	)
 	if err != nil {
-		return err
+		s.Finish(ctx, taskId, &core.MessageTaskResult{
+			Status: core.MessageFailed,
+			Error:  err.Error(),
+		})
+		return "", err
 	}
-	slog.Info("message added to queue")
+	slog.Info("message added to queue", "task_id", taskId)
+
+	return taskId, nil
+}
 
+// Tells if a batch with the given number of messages is accepted
+func (s *MessageService) CheckBatchSize(size int) error {
+	if size == 0 {
+		return core.EmptyMessageBatch
+	}
+	if size > config.Worker.MessageBatchMaxSize {
+		return core.MessageBatchTooLarge
+	}
 	return nil
 }
+
+// Sends every message to the classification queue in a single round trip,
+// the chat ids must already be validated. A message whose task could not
+// be created is reported in its item and left out of the batch
+func (s *MessageService) AddBatch(
+	ctx context.Context, messages []core.NewMessage,
+) ([]core.MessageBatchItem, error) {
+	if err := s.CheckBatchSize(len(messages)); err != nil {
+		return nil, err
+	}
+	tenantId := core.TenantFromContext(ctx)
+	items := make([]core.MessageBatchItem, len(messages))
+	payloads := make([]task.ClassifyMessagePayload, 0, len(messages))
+	for i, message := range messages {
+		items[i].ChatId = message.ChatId
+		taskId := uuid.New().String()
+		if err := s.repo.Create(ctx, &core.Message{
+			Id:             taskId,
+			ChatId:         message.ChatId,
+			Message:        message.Message,
+			RelatedContext: message.RelatedContext,
+			Status:         core.MessageQueued,
+			CreatedAt:      time.Now(),
+		}); err != nil {
+			items[i].Error = err.Error()
+			continue
+		}
+		items[i].TaskId = taskId
+		payloads = append(payloads, task.NewClassifyMessagePayload(
+			taskId, tenantId, message.ChatId, message.Message, message.RelatedContext,
+		))
+	}
+	if len(payloads) == 0 {
+		return items, nil
+	}
+	if err := task.NewClassifyMessageBatchTask(payloads); err != nil {
+		for _, payload := range payloads {
+			s.Finish(ctx, payload.TaskId, &core.MessageTaskResult{
+				Status: core.MessageFailed,
+				Error:  err.Error(),
+			})
+		}
+		return nil, err
+	}
+	slog.Info("message batch added to queue", "messages", len(payloads))
+
+	return items, nil
+}
+
+// Blocks until the worker is done with the message or the timeout is
+// reached, in which case the task is returned with [core.MessageWaitTimeout]
+func (s *MessageService) Wait(
+	ctx context.Context, taskId string, timeout time.Duration,
+) (*core.MessageTask, error) {
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(messageWaitPollInterval)
+	defer ticker.Stop()
+	for {
+		message, err := s.repo.GetById(ctx, taskId)
+		if err != nil {
+			return nil, err
+		}
+		messageTask := message.Task()
+		if messageTask.Status.IsFinished() {
+			return messageTask, nil
+		}
+		if time.Now().After(deadline) {
+			return messageTask, core.MessageWaitTimeout
+		}
+		select {
+		case <-ctx.Done():
+			return messageTask, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Returns the current state of a message
+func (s *MessageService) GetTask(ctx context.Context, taskId string) (*core.MessageTask, error) {
+	message, err := s.repo.GetById(ctx, taskId)
+	if err != nil {
+		return nil, err
+	}
+	return message.Task(), nil
+}
+
+// Returns the transcript of a chat, oldest messages first
+func (s *MessageService) GetByChatId(
+	ctx context.Context, chatExternalId string, limit int, offset int,
+) (*core.MessageArray, error) {
+	chatId, err := s.chatRepo.GetByExternalID(ctx, chatExternalId)
+	if err != nil {
+		return nil, err
+	}
+	if chatId == nil {
+		return nil, core.ChatNotFound
+	}
+	return s.repo.GetByChatId(ctx, *chatId, limit, offset)
+}
+
+// Returns the messages a memory came from, the memory
+// must already be known to belong to the caller
+func (s *MessageService) GetProvenance(
+	ctx context.Context, memoryId string,
+) (*core.MemoryProvenance, error) {
+	messages, err := s.repo.GetByMemoryId(ctx, memoryId)
+	if err != nil {
+		return nil, err
+	}
+	sources := []core.MemorySource{}
+	for _, message := range messages {
+		sources = append(sources, core.MemorySource{
+			MessageId:      message.Id,
+			Message:        message.Message,
+			RelatedContext: message.RelatedContext,
+			Status:         message.Status,
+			CreatedAt:      message.CreatedAt,
+		})
+	}
+	return &core.MemoryProvenance{MemoryId: memoryId, Sources: sources}, nil
+}
+
+// Records that the worker picked the message up, untracked messages are skipped
+func (s *MessageService) Start(ctx context.Context, taskId string) {
+	if taskId == "" {
+		return
+	}
+	if err := s.repo.StartAttempt(ctx, taskId); err != nil {
+		slog.Error("error recording message attempt", "task_id", taskId, "error", err)
+	}
+}
+
+// Records the outcome of a message, messages queued
+// before they were tracked have no task id and are skipped
+func (s *MessageService) Finish(
+	ctx context.Context, taskId string, result *core.MessageTaskResult,
+) {
+	if taskId == "" {
+		return
+	}
+	if err := s.repo.SetResult(ctx, taskId, result); err != nil {
+		slog.Error("error recording message result", "task_id", taskId, "error", err)
+	}
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"fmt"
+	"github.com/Mateus-Lacerda/better-mem/internal/llm"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"log/slog"
+	"strings"
+)
+
+type QueryRewriteService struct {
+	llmProvider llm.LLMProvider
+}
+
+func NewQueryRewriteService(llmProvider llm.LLMProvider) *QueryRewriteService {
+	return &QueryRewriteService{llmProvider: llmProvider}
+}
+
+func (q QueryRewriteService) IsWorking() bool {
+	return q.llmProvider != nil
+}
+
+// Rewrites the text as a standalone query using the previous turns
+func (q QueryRewriteService) Rewrite(text string, turns []core.ConversationTurn) (string, error) {
+	var conversation strings.Builder
+	for _, turn := range turns {
+		fmt.Fprintf(&conversation, "%s: %s\n", turn.Role, turn.Content)
+	}
+	prompt := fmt.Sprintf(llm.QueryRewritePrompt, conversation.String(), text)
+	query, err := q.llmProvider.GetCompletion(prompt)
+	if err != nil {
+		return "", err
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return "", core.EmptyRewrittenQuery
+	}
+	slog.Info("Query rewrite completed", "text", text, "query", query)
+	return query, nil
+}
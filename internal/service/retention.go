@@ -0,0 +1,81 @@
+package service
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository/vector"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"context"
+	"time"
+)
+
+// Permanently deletes the memories that stayed inactive past the
+// retention period and keeps the log of the purges
+type RetentionService struct {
+	repo       repository.MemoryRetentionRepository
+	runRepo    repository.RetentionRunRepository
+	vectorRepo vector.MemoryVectorRepository
+}
+
+func NewRetentionService(
+	repo repository.MemoryRetentionRepository,
+	runRepo repository.RetentionRunRepository,
+	vectorRepo vector.MemoryVectorRepository,
+) *RetentionService {
+	return &RetentionService{
+		repo:       repo,
+		runRepo:    runRepo,
+		vectorRepo: vectorRepo,
+	}
+}
+
+// Deletes the memories of the tenant in ctx deactivated before the
+// cutoff, with their vectors and merges. The vectors of a batch go
+// first, so a purge that fails midway leaves no vector without a memory
+func (s *RetentionService) PurgeInactive(ctx context.Context, cutoff time.Time) (*core.RetentionPurgeStats, error) {
+	var stats core.RetentionPurgeStats
+	if _, err := s.repo.MarkDeactivated(ctx, time.Now()); err != nil {
+		return &stats, err
+	}
+	for _, memoryType := range []core.MemoryTypeEnum{core.ShortTerm, core.LongTerm} {
+		for {
+			ids, err := s.repo.GetExpired(ctx, memoryType, cutoff, config.Retention.PurgeBatchSize)
+			if err != nil {
+				return &stats, err
+			}
+			if len(ids) == 0 {
+				break
+			}
+			vectors, err := s.vectorRepo.DeleteByMemoryIds(ctx, ids)
+			if err != nil {
+				return &stats, err
+			}
+			deleted, err := s.repo.Delete(ctx, memoryType, ids)
+			if err != nil {
+				return &stats, err
+			}
+			deleted.Vectors = vectors
+			stats.Add(*deleted)
+			// A memory reactivated meanwhile is kept and not returned again
+			if len(ids) < config.Retention.PurgeBatchSize {
+				break
+			}
+		}
+	}
+	return &stats, nil
+}
+
+// Deletes the related context no longer attached to anything, for
+// every tenant at once
+func (s *RetentionService) PurgeOrphanedRelatedContext(ctx context.Context) (int, error) {
+	return s.repo.DeleteOrphanedRelatedContext(ctx)
+}
+
+func (s *RetentionService) SaveRun(ctx context.Context, run *core.RetentionRun) error {
+	return s.runRepo.Create(ctx, run)
+}
+
+// Returns the latest purges first
+func (s *RetentionService) GetRuns(ctx context.Context, limit, offset int) ([]*core.RetentionRun, error) {
+	return s.runRepo.GetAll(ctx, limit, offset)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/Mateus-Lacerda/better-mem/pkg/core"
 	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"github.com/Mateus-Lacerda/better-mem/internal/uow"
 	"context"
 	"log/slog"
 	"time"
@@ -11,13 +12,15 @@ import (
 type ShortTermMemoryService struct {
 	repo     repository.ShortTermMemoryRepository
 	chatRepo repository.ChatRepository
+	uow      uow.UnitOfWork[int, any]
 }
 
 func NewShortTermMemoryService(
 	repo repository.ShortTermMemoryRepository,
 	chatRepo repository.ChatRepository,
+	uow uow.UnitOfWork[int, any],
 ) *ShortTermMemoryService {
-	return &ShortTermMemoryService{repo: repo, chatRepo: chatRepo}
+	return &ShortTermMemoryService{repo: repo, chatRepo: chatRepo, uow: uow}
 }
 
 func (s *ShortTermMemoryService) Create(
@@ -62,12 +65,6 @@ func (s *ShortTermMemoryService) GetById(
 	return s.repo.GetById(ctx, chatId, memoryId)
 }
 
-func (s *ShortTermMemoryService) GetScored(
-	ctx context.Context, chatId string, memoriesIds []string,
-) ([]*core.ScoredMemory, error) {
-	return s.repo.GetScored(ctx, chatId, memoriesIds)
-}
-
 func (s *ShortTermMemoryService) RegisterUsage(
 	ctx context.Context, chatId string, memoryId string,
 ) error {
@@ -80,8 +77,55 @@ func (s *ShortTermMemoryService) Merge(
 	memoryId string,
 	otherMemory string,
 	otherMemoryRelatedContext []core.MessageRelatedContext,
+	score float32,
 ) (*core.ShortTermMemory, error) {
-	return s.repo.Merge(ctx, chatId, memoryId, otherMemory, otherMemoryRelatedContext)
+	return mergeShortTerm(
+		ctx, s.uow,
+		chatId, memoryId, otherMemory, otherMemoryRelatedContext, score,
+	)
+}
+
+// Merges the text into the memory and records the merge in its history,
+// in the same unit of work so the previous text is never lost
+func mergeShortTerm(
+	ctx context.Context,
+	unitOfWork uow.UnitOfWork[int, any],
+	chatId string,
+	memoryId string,
+	otherMemory string,
+	otherMemoryRelatedContext []core.MessageRelatedContext,
+	score float32,
+) (*core.ShortTermMemory, error) {
+	var memory *core.ShortTermMemory
+	_, err := unitOfWork.Do(ctx, func(ctx context.Context, repos repository.AllRepositories) (int, error) {
+		previous, err := repos.ShortTermMemory.GetById(ctx, chatId, memoryId)
+		if err != nil {
+			return 0, err
+		}
+		// The history goes first, no text is overwritten without it
+		if _, err := repos.MemoryMerge.Create(ctx, &core.MemoryMerge{
+			MemoryId:               memoryId,
+			PreviousMemory:         previous.Memory,
+			PreviousRelatedContext: previous.RelatedContext,
+			IncomingMemory:         otherMemory,
+			Score:                  score,
+			CreatedAt:              time.Now(),
+		}); err != nil {
+			return 0, err
+		}
+		memory, err = repos.ShortTermMemory.Merge(
+			ctx, chatId, memoryId, otherMemory, otherMemoryRelatedContext,
+		)
+		if err != nil {
+			return 0, err
+		}
+		return 1, nil
+	})
+	if err != nil {
+		slog.Error("error merging memory", "memory_id", memoryId, "error", err)
+		return nil, err
+	}
+	return memory, nil
 }
 
 func (s *ShortTermMemoryService) Deactivate(
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+type TenantService struct {
+	repo          repository.TenantRepository
+	chatRepo      repository.ChatRepository
+	shortTermRepo repository.ShortTermMemoryRepository
+	longTermRepo  repository.LongTermMemoryRepository
+}
+
+func NewTenantService(
+	repo repository.TenantRepository,
+	chatRepo repository.ChatRepository,
+	shortTermRepo repository.ShortTermMemoryRepository,
+	longTermRepo repository.LongTermMemoryRepository,
+) *TenantService {
+	return &TenantService{
+		repo:          repo,
+		chatRepo:      chatRepo,
+		shortTermRepo: shortTermRepo,
+		longTermRepo:  longTermRepo,
+	}
+}
+
+func defaultTenantQuota() core.TenantQuota {
+	return core.TenantQuota{
+		MaxChats:    config.Tenant.DefaultMaxChats,
+		MaxMemories: config.Tenant.DefaultMaxMemories,
+	}
+}
+
+// The default tenant always exists, it is only stored once its quota is changed
+func defaultTenant() *core.Tenant {
+	return &core.Tenant{
+		Id:    core.DefaultTenantId,
+		Name:  core.DefaultTenantId,
+		Quota: defaultTenantQuota(),
+	}
+}
+
+func (s *TenantService) Create(
+	ctx context.Context, request *core.NewTenant,
+) (*core.Tenant, error) {
+	if request.Id == "" {
+		return nil, core.EmptyTenantId
+	}
+	if request.Id == core.DefaultTenantId {
+		return nil, core.TenantAlreadyExists
+	}
+	quota := defaultTenantQuota()
+	if request.Quota != nil {
+		quota = *request.Quota
+	}
+	tenant, err := s.repo.Create(ctx, &core.Tenant{
+		Id:        request.Id,
+		Name:      request.Name,
+		Quota:     quota,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		slog.Error("error creating tenant", "error", err)
+		return nil, err
+	}
+	slog.Info("tenant created", "id", tenant.Id)
+	return tenant, nil
+}
+
+func (s *TenantService) Get(ctx context.Context, id string) (*core.Tenant, error) {
+	tenant, err := s.repo.GetById(ctx, id)
+	if errors.Is(err, core.TenantNotFound) && id == core.DefaultTenantId {
+		return defaultTenant(), nil
+	}
+	return tenant, err
+}
+
+func (s *TenantService) GetAll(ctx context.Context) ([]*core.Tenant, error) {
+	tenants, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, tenant := range tenants {
+		if tenant.Id == core.DefaultTenantId {
+			return tenants, nil
+		}
+	}
+	return append([]*core.Tenant{defaultTenant()}, tenants...), nil
+}
+
+func (s *TenantService) UpdateQuota(
+	ctx context.Context, id string, quota core.TenantQuota,
+) (*core.Tenant, error) {
+	tenant, err := s.repo.UpdateQuota(ctx, id, quota)
+	if errors.Is(err, core.TenantNotFound) && id == core.DefaultTenantId {
+		tenant := defaultTenant()
+		tenant.Quota = quota
+		tenant.CreatedAt = time.Now()
+		return s.repo.Create(ctx, tenant)
+	}
+	return tenant, err
+}
+
+// Returns how much of its quota the tenant is using
+func (s *TenantService) Usage(ctx context.Context, id string) (*core.TenantUsage, error) {
+	tenant, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ctx = core.WithTenant(ctx, id)
+	chats, err := s.chatRepo.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	memories, err := s.countMemories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &core.TenantUsage{
+		TenantId: id,
+		Chats:    chats,
+		Memories: memories,
+		Quota:    tenant.Quota,
+	}, nil
+}
+
+func (s *TenantService) countMemories(ctx context.Context) (int, error) {
+	shortTerm, err := s.shortTermRepo.CountActive(ctx)
+	if err != nil {
+		return 0, err
+	}
+	longTerm, err := s.longTermRepo.CountActive(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return shortTerm + longTerm, nil
+}
+
+// Fails with [core.TenantQuotaExceeded] when the tenant
+// carried by ctx can not create another chat
+func (s *TenantService) CheckChatQuota(ctx context.Context) error {
+	tenant, err := s.Get(ctx, core.TenantFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	if tenant.Quota.MaxChats <= 0 {
+		return nil
+	}
+	chats, err := s.chatRepo.Count(ctx)
+	if err != nil {
+		return err
+	}
+	if chats >= tenant.Quota.MaxChats {
+		return core.TenantQuotaExceeded
+	}
+	return nil
+}
+
+// Fails with [core.TenantQuotaExceeded] when the tenant
+// carried by ctx can not store another memory
+func (s *TenantService) CheckMemoryQuota(ctx context.Context) error {
+	return s.CheckMemoryQuotaFor(ctx, 1)
+}
+
+// Fails with [core.TenantQuotaExceeded] when the tenant
+// carried by ctx can not store that many more active memories
+func (s *TenantService) CheckMemoryQuotaFor(ctx context.Context, count int) error {
+	tenant, err := s.Get(ctx, core.TenantFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	if tenant.Quota.MaxMemories <= 0 {
+		return nil
+	}
+	memories, err := s.countMemories(ctx)
+	if err != nil {
+		return err
+	}
+	if memories+count > tenant.Quota.MaxMemories {
+		return core.TenantQuotaExceeded
+	}
+	return nil
+}
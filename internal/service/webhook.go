@@ -0,0 +1,208 @@
+package service
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
+	"github.com/Mateus-Lacerda/better-mem/internal/repository"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const webhookSecretPrefix = "whsec_"
+
+// Most bytes of a webhook response that are read before closing it
+const webhookResponseLimit = 64 << 10
+
+type WebhookService struct {
+	repo         repository.WebhookRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+	chatRepo     repository.ChatRepository
+	client       *http.Client
+}
+
+func NewWebhookService(
+	repo repository.WebhookRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	chatRepo repository.ChatRepository,
+) *WebhookService {
+	return &WebhookService{
+		repo:         repo,
+		deliveryRepo: deliveryRepo,
+		chatRepo:     chatRepo,
+		client: &http.Client{
+			Timeout: time.Duration(config.Webhook.Timeout) * time.Second,
+		},
+	}
+}
+
+// SignWebhook returns the signature of a delivery, the hex encoded
+// HMAC-SHA256 of "<timestamp>.<body>" keyed by the webhook secret
+func SignWebhook(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func isValidWebhookUrl(rawUrl string) bool {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// Registers a webhook, the secret is only returned here
+func (s *WebhookService) Register(
+	ctx context.Context, request *core.NewWebhook,
+) (*core.RegisteredWebhook, error) {
+	if !isValidWebhookUrl(request.Url) {
+		return nil, core.InvalidWebhookUrl
+	}
+	for _, event := range request.Events {
+		if !core.IsValidMemoryEventType(event) {
+			return nil, core.InvalidWebhookEvent
+		}
+	}
+	var chatId string
+	if request.ChatId != "" {
+		id, err := s.chatRepo.GetByExternalID(ctx, request.ChatId)
+		if err != nil {
+			return nil, err
+		}
+		chatId = *id
+	}
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	webhook, err := s.repo.Create(ctx, &core.Webhook{
+		ChatId:    chatId,
+		Url:       request.Url,
+		Events:    request.Events,
+		CreatedAt: time.Now(),
+		Secret:    webhookSecretPrefix + hex.EncodeToString(secret),
+	})
+	if err != nil {
+		slog.Error("error registering webhook", "error", err)
+		return nil, err
+	}
+	slog.Info(
+		"webhook registered",
+		"id", webhook.Id,
+		"chat_id", webhook.ChatId,
+		"tenant_id", webhook.TenantId,
+	)
+	return &core.RegisteredWebhook{Webhook: *webhook, Secret: webhook.Secret}, nil
+}
+
+func (s *WebhookService) GetAll(ctx context.Context) ([]*core.Webhook, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *WebhookService) GetById(ctx context.Context, id string) (*core.Webhook, error) {
+	return s.repo.GetById(ctx, id)
+}
+
+func (s *WebhookService) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Returns the delivery log of a webhook, the latest attempts first
+func (s *WebhookService) GetDeliveries(
+	ctx context.Context, webhookId string, limit int, offset int,
+) ([]*core.WebhookDelivery, error) {
+	if _, err := s.repo.GetById(ctx, webhookId); err != nil {
+		return nil, err
+	}
+	return s.deliveryRepo.GetByWebhookId(ctx, webhookId, limit, offset)
+}
+
+// Returns the webhooks listening to the event type on the chat
+func (s *WebhookService) Subscribed(
+	ctx context.Context, chatId string, eventType core.MemoryEventType,
+) ([]*core.Webhook, error) {
+	webhooks, err := s.repo.GetByChatId(ctx, chatId)
+	if err != nil {
+		return nil, err
+	}
+	var subscribed []*core.Webhook
+	for _, webhook := range webhooks {
+		if webhook.ListensTo(eventType) {
+			subscribed = append(subscribed, webhook)
+		}
+	}
+	return subscribed, nil
+}
+
+// Posts the signed event to the webhook and records the attempt,
+// any response other than 2xx is a failed delivery
+func (s *WebhookService) Deliver(
+	ctx context.Context, webhook *core.Webhook, event *core.MemoryEvent, attempt int,
+) (*core.WebhookDelivery, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	delivery := &core.WebhookDelivery{
+		WebhookId: webhook.Id,
+		EventId:   event.Id,
+		EventType: event.Type,
+		Attempt:   attempt,
+		CreatedAt: time.Now(),
+	}
+	statusCode, err := s.post(ctx, webhook, event, attempt, body)
+	delivery.Duration = time.Since(delivery.CreatedAt).Milliseconds()
+	delivery.StatusCode = statusCode
+	switch {
+	case err != nil:
+		delivery.Error = err.Error()
+	case statusCode < 200 || statusCode > 299:
+		delivery.Error = fmt.Sprintf("unexpected status code %d", statusCode)
+	default:
+		delivery.Success = true
+	}
+	if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+		// The attempt was made, only the log is missing
+		slog.Error("error recording webhook delivery", "webhook_id", webhook.Id, "error", err)
+	}
+	return delivery, nil
+}
+
+// Sends the request, returns the status code of the response
+func (s *WebhookService) post(
+	ctx context.Context, webhook *core.Webhook, event *core.MemoryEvent, attempt int, body []byte,
+) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "better-mem-webhook")
+	request.Header.Set("X-BetterMem-Event", string(event.Type))
+	request.Header.Set("X-BetterMem-Event-Id", event.Id)
+	request.Header.Set("X-BetterMem-Attempt", strconv.Itoa(attempt))
+	request.Header.Set("X-BetterMem-Timestamp", timestamp)
+	request.Header.Set("X-BetterMem-Signature", "sha256="+SignWebhook(webhook.Secret, timestamp, body))
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, webhookResponseLimit))
+	return response.StatusCode, nil
+}
@@ -5,24 +5,65 @@ package task
 import (
 	"github.com/Mateus-Lacerda/better-mem/internal/config"
 	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/hibiken/asynq"
 )
 
 func NewClassifyMessageTask(
-	chatId, message string, relatedContext []core.MessageRelatedContext,
-) (*asynq.Task, error) {
-	payload, err := getClassifiyMessageTaskPayload(chatId, message, relatedContext)
+	taskId, tenantId, chatId, message string, relatedContext []core.MessageRelatedContext,
+) error {
+	payload, err := getClassifiyMessageTaskPayload(taskId, tenantId, chatId, message, relatedContext)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return asynq.NewTask(
+	client := asynq.NewClient(asynq.RedisClientOpt{Addr: config.Database.RedisAddress})
+	defer client.Close()
+	_, err = client.Enqueue(asynq.NewTask(
 		ClassifyMessageTaskName,
 		payload,
 		asynq.MaxRetry(config.Worker.MaxRetry),
 		asynq.Timeout(time.Duration(config.Worker.Timeout)*time.Second),
-	), nil
+	))
+	return err
+}
+
+// Queues the whole batch as a single task, the worker splits it
+func NewClassifyMessageBatchTask(messages []ClassifyMessagePayload) error {
+	payload, err := json.Marshal(ClassifyMessageBatchPayload{Messages: messages})
+	if err != nil {
+		return err
+	}
+	client := asynq.NewClient(asynq.RedisClientOpt{Addr: config.Database.RedisAddress})
+	defer client.Close()
+	_, err = client.Enqueue(asynq.NewTask(
+		ClassifyMessageBatchTaskName,
+		payload,
+		asynq.MaxRetry(config.Worker.MaxRetry),
+		asynq.Timeout(time.Duration(config.Worker.Timeout)*time.Second),
+	))
+	return err
+}
+
+// Queues an event to be sent to the webhooks listening to it
+func NewDispatchWebhookTask(event DispatchWebhookPayload) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	client := asynq.NewClient(asynq.RedisClientOpt{Addr: config.Database.RedisAddress})
+	defer client.Close()
+	_, err = client.Enqueue(asynq.NewTask(
+		DispatchWebhookTaskName,
+		payload,
+		asynq.MaxRetry(config.Worker.MaxRetry),
+		asynq.Timeout(time.Duration(config.Worker.Timeout)*time.Second),
+	))
+	return err
 }
 
 func NewManageShortTermMemoryTask() *asynq.Task {
@@ -33,3 +74,112 @@ func NewManageShortTermMemoryTask() *asynq.Task {
 		asynq.Timeout(time.Duration(config.Worker.Timeout)*time.Second),
 	)
 }
+
+func NewPurgeInactiveMemoriesTask() *asynq.Task {
+	return asynq.NewTask(
+		PurgeInactiveMemoriesTaskName,
+		nil,
+		asynq.MaxRetry(config.Worker.MaxRetry),
+		asynq.Timeout(time.Duration(config.Worker.Timeout)*time.Second),
+	)
+}
+
+// Page size used when going through the queues
+const inspectPageSize = 100
+
+// A task of the chat found in one of the queues
+type chatTask struct {
+	info      *asynq.TaskInfo
+	drop      bool
+	rewritten []byte
+}
+
+// Goes through the tasks of the chat in every queue, active ones are
+// left to the worker since they can not be deleted
+func listChatTasks(inspector *asynq.Inspector, chat purgedChat) ([]chatTask, error) {
+	queues, err := inspector.Queues()
+	if err != nil {
+		return nil, err
+	}
+	markers := chat.markers()
+	var found []chatTask
+	for _, queue := range queues {
+		for _, list := range []func(string, ...asynq.ListOption) ([]*asynq.TaskInfo, error){
+			inspector.ListPendingTasks,
+			inspector.ListScheduledTasks,
+			inspector.ListRetryTasks,
+			inspector.ListArchivedTasks,
+			inspector.ListCompletedTasks,
+		} {
+			for page := 1; ; page++ {
+				infos, err := list(queue, asynq.PageSize(inspectPageSize), asynq.Page(page))
+				if err != nil {
+					return nil, err
+				}
+				for _, info := range infos {
+					if !bytes.Contains(info.Payload, []byte(markers[0])) &&
+						!bytes.Contains(info.Payload, []byte(markers[1])) {
+						continue
+					}
+					drop, rewritten, err := purgeChatPayload(info.Type, info.Payload, chat)
+					if err != nil {
+						return nil, err
+					}
+					if drop || rewritten != nil {
+						found = append(found, chatTask{info: info, drop: drop, rewritten: rewritten})
+					}
+				}
+				if len(infos) < inspectPageSize {
+					break
+				}
+			}
+		}
+	}
+	return found, nil
+}
+
+// Removes the tasks of the chat from the queues, archived and completed
+// ones included since they still hold the messages. A task the worker
+// is running is left to it. Returns how many tasks were removed or
+// rewritten
+func PurgeChatTasks(ctx context.Context, tenantId, chatId, externalId string) (int, error) {
+	inspector := asynq.NewInspector(asynq.RedisClientOpt{Addr: config.Database.RedisAddress})
+	defer inspector.Close()
+	tasks, err := listChatTasks(inspector, purgedChat{tenantId: tenantId, chatId: chatId, externalId: externalId})
+	if err != nil {
+		return 0, err
+	}
+	client := asynq.NewClient(asynq.RedisClientOpt{Addr: config.Database.RedisAddress})
+	defer client.Close()
+	purged := 0
+	for _, t := range tasks {
+		if err := inspector.DeleteTask(t.info.Queue, t.info.ID); err != nil && !errors.Is(err, asynq.ErrTaskNotFound) {
+			return purged, err
+		}
+		purged++
+		// A dead batch is not brought back, the other chats already
+		// had their messages failed
+		if t.drop || t.info.State == asynq.TaskStateArchived || t.info.State == asynq.TaskStateCompleted {
+			continue
+		}
+		if _, err := client.EnqueueContext(ctx, asynq.NewTask(
+			t.info.Type,
+			t.rewritten,
+			asynq.Queue(t.info.Queue),
+			asynq.MaxRetry(t.info.MaxRetry),
+			asynq.Timeout(t.info.Timeout),
+			asynq.ProcessAt(t.info.NextProcessAt),
+		)); err != nil {
+			return purged, err
+		}
+	}
+	return purged, nil
+}
+
+// Counts the tasks PurgeChatTasks would remove or rewrite
+func CountChatTasks(ctx context.Context, tenantId, chatId, externalId string) (int, error) {
+	inspector := asynq.NewInspector(asynq.RedisClientOpt{Addr: config.Database.RedisAddress})
+	defer inspector.Close()
+	tasks, err := listChatTasks(inspector, purgedChat{tenantId: tenantId, chatId: chatId, externalId: externalId})
+	return len(tasks), err
+}
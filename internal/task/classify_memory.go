@@ -9,6 +9,8 @@ import (
 const (
 	// ClassifyMessageTaskName is the name of the classify memory task.
 	ClassifyMessageTaskName = "message:classify"
+	// ClassifyMessageBatchTaskName is the name of the task that splits a batch into classify tasks.
+	ClassifyMessageBatchTaskName = "message:classify-batch"
 	// StoreLongTermMemoryTaskName is the name of the store long term memory task.
 	StoreLongTermMemoryTaskName = "long-term-memory:store"
 	// StoreShortTermMemoryTaskName is the name of the store short term memory task.
@@ -17,18 +19,38 @@ const (
 
 type ClassifyMessagePayload struct {
 	core.NewMessage `json:"embedded"`
+	// The tenant the chat belongs to
+	TenantId string `json:"tenant_id"`
+	// The message task tracking the outcome
+	TaskId string `json:"task_id"`
+}
+
+type ClassifyMessageBatchPayload struct {
+	Messages []ClassifyMessagePayload `json:"messages"`
 }
 
 type StoreMemoryPayload struct {
 	core.LabeledMessage `json:"embedded"`
+	// The tenant the chat belongs to
+	TenantId string `json:"tenant_id"`
+	// The message task tracking the outcome
+	TaskId string `json:"task_id"`
 }
 
 func getClassifiyMessageTaskPayload(
-	chatId, message string, relatedContext []core.MessageRelatedContext,
+	taskId, tenantId, chatId, message string, relatedContext []core.MessageRelatedContext,
 ) ([]byte, error) {
 	return json.Marshal(
-		ClassifyMessagePayload{
-			core.NewMessage{ChatId: chatId, Message: message, RelatedContext: relatedContext},
-		},
+		NewClassifyMessagePayload(taskId, tenantId, chatId, message, relatedContext),
 	)
 }
+
+func NewClassifyMessagePayload(
+	taskId, tenantId, chatId, message string, relatedContext []core.MessageRelatedContext,
+) ClassifyMessagePayload {
+	return ClassifyMessagePayload{
+		NewMessage: core.NewMessage{ChatId: chatId, Message: message, RelatedContext: relatedContext},
+		TenantId:   tenantId,
+		TaskId:     taskId,
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/Mateus-Lacerda/better-mem/internal/task"
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/hibiken/asynq"
 )
@@ -20,12 +21,42 @@ func enqueueFunc(taskName string, payloadBytes []byte) error {
 	return nil
 }
 
+// Queues the task to run once the delay has passed
+func enqueueAfterFunc(taskName string, payloadBytes []byte, delay time.Duration) error {
+	client := asynq.NewClient(asynq.RedisClientOpt{Addr: config.Database.RedisAddress})
+	defer client.Close()
+	if _, err := client.Enqueue(asynq.NewTask(taskName, payloadBytes), asynq.ProcessIn(delay)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Reads the attempt from the retry counts asynq puts in the context
+func taskAttempt(ctx context.Context) attempt {
+	retried, ok := asynq.GetRetryCount(ctx)
+	maxRetry, hasMax := asynq.GetMaxRetry(ctx)
+	if !ok || !hasMax {
+		return attempt{last: true}
+	}
+	return attempt{retry: retried > 0, last: retried >= maxRetry}
+}
+
 func (h *MessageTaskHandler) HandleClassifyMemoryTask(ctx context.Context, t *asynq.Task) error {
 	var payload task.ClassifyMessagePayload
 	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
 		return err
 	}
-	return h.handleClassifyMemoryTask(ctx, payload, enqueueFunc)
+	return h.handleClassifyMemoryTask(withAttempt(ctx, taskAttempt(ctx)), payload, enqueueFunc)
+}
+
+func (h *MessageTaskHandler) HandleClassifyMessageBatchTask(
+	ctx context.Context, t *asynq.Task,
+) error {
+	var payload task.ClassifyMessageBatchPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return err
+	}
+	return h.handleClassifyMessageBatchTask(ctx, payload, enqueueFunc)
 }
 
 func (h *MessageTaskHandler) HandleStoreLongTermMemoryTask(
@@ -35,7 +66,7 @@ func (h *MessageTaskHandler) HandleStoreLongTermMemoryTask(
 	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
 		return err
 	}
-	return h.handleStoreLongTermMemoryTask(ctx, payload)
+	return h.handleStoreLongTermMemoryTask(withAttempt(ctx, taskAttempt(ctx)), payload)
 }
 
 func (h *MessageTaskHandler) HandleStoreShortTermMemoryTask(
@@ -45,7 +76,7 @@ func (h *MessageTaskHandler) HandleStoreShortTermMemoryTask(
 	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
 		return err
 	}
-	return h.handleStoreLongTermMemoryTask(ctx, payload)
+	return h.handleStoreShortTermMemoryTask(withAttempt(ctx, taskAttempt(ctx)), payload)
 }
 
 func (m *MemoryManagementHandler) HandleManageMemory(
@@ -53,3 +84,29 @@ func (m *MemoryManagementHandler) HandleManageMemory(
 ) error {
 	return m.handleManageMemory(ctx)
 }
+
+func (h *WebhookTaskHandler) HandleDispatchWebhookTask(
+	ctx context.Context, t *asynq.Task,
+) error {
+	var payload task.DispatchWebhookPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return err
+	}
+	return h.handleDispatchWebhookTask(ctx, payload, enqueueFunc)
+}
+
+func (h *WebhookTaskHandler) HandleDeliverWebhookTask(
+	ctx context.Context, t *asynq.Task,
+) error {
+	var payload task.DeliverWebhookPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return err
+	}
+	return h.handleDeliverWebhookTask(ctx, payload, enqueueAfterFunc)
+}
+
+func (h *RetentionTaskHandler) HandlePurgeInactiveMemories(
+	ctx context.Context, t *asynq.Task,
+) error {
+	return h.handlePurgeInactiveMemories(ctx)
+}
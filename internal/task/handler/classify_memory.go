@@ -1,13 +1,13 @@
 package handler
 
 import (
-	"github.com/Mateus-Lacerda/better-mem/internal/config"
 	"github.com/Mateus-Lacerda/better-mem/pkg/core"
 	protos "github.com/Mateus-Lacerda/better-mem/internal/grpc_client"
 	"github.com/Mateus-Lacerda/better-mem/internal/service"
 	"github.com/Mateus-Lacerda/better-mem/internal/task"
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 )
 
@@ -16,6 +16,9 @@ type MessageTaskHandler struct {
 	shortTermMemoryService   *service.ShortTermMemoryService
 	memoryVectorService      *service.MemoryVectorService
 	memoryEnhancementService *service.MemoryEnhancementService
+	tenantService            *service.TenantService
+	messageService           *service.MessageService
+	eventService             *service.MemoryEventService
 }
 
 func NewMessageTaskHandler(
@@ -23,36 +26,109 @@ func NewMessageTaskHandler(
 	shortTermMemoryService *service.ShortTermMemoryService,
 	memoryVectorService *service.MemoryVectorService,
 	memoryEnhancementService *service.MemoryEnhancementService,
+	tenantService *service.TenantService,
+	messageService *service.MessageService,
+	eventService *service.MemoryEventService,
 ) *MessageTaskHandler {
 	return &MessageTaskHandler{
 		longTermMemoryService:    longTermMemoryService,
 		shortTermMemoryService:   shortTermMemoryService,
 		memoryVectorService:      memoryVectorService,
 		memoryEnhancementService: memoryEnhancementService,
+		tenantService:            tenantService,
+		messageService:           messageService,
+		eventService:             eventService,
 	}
 }
 
-func (h *MessageTaskHandler) checkForSimilarMemory(
-	ctx context.Context,
-	chatId string,
-	tokens []float32,
-) (*core.ScoredMemoryVector, error) {
+// Where a task stands among the attempts its queue allows
+type attempt struct {
+	// Whether the task failed before and is being tried again
+	retry bool
+	// Whether the queue gives up on the task once this attempt fails
+	last bool
+}
 
-	similarMemory, err := h.memoryVectorService.SearchMemoryVector(
-		ctx,
-		chatId,
-		tokens,
-		1,
-		config.MemoryManagement.MemorySimilarityThreshold,
-	)
-	if similarMemory == nil {
-		return nil, nil
+type attemptKey struct{}
+
+func withAttempt(ctx context.Context, a attempt) context.Context {
+	return context.WithValue(ctx, attemptKey{}, a)
+}
+
+// Returns the attempt set by the queue, a task run
+// outside of one is only tried once
+func attemptFromContext(ctx context.Context) attempt {
+	if a, ok := ctx.Value(attemptKey{}).(attempt); ok {
+		return a
+	}
+	return attempt{last: true}
+}
+
+// Records the error and returns it so the queue tries the task again.
+// The message is only marked as failed once no attempt is left, until
+// then it is retrying and a request waiting on it keeps waiting
+func (h *MessageTaskHandler) fail(ctx context.Context, taskId string, err error) error {
+	status := core.MessageRetrying
+	if attemptFromContext(ctx).last {
+		status = core.MessageFailed
 	}
-	if len(*similarMemory) > 0 {
-		return &(*similarMemory)[0], nil
+	h.messageService.Finish(ctx, taskId, &core.MessageTaskResult{
+		Status: status,
+		Error:  err.Error(),
+	})
+	return err
+}
+
+// Records the attempt of a store task that failed before, the message
+// is classified already so only retries bring it back from retrying
+func (h *MessageTaskHandler) startRetry(ctx context.Context, taskId string) {
+	if attemptFromContext(ctx).retry {
+		h.messageService.Start(ctx, taskId)
+	}
+}
+
+// Marks the message as failed for good, for errors retrying would not fix
+func (h *MessageTaskHandler) drop(ctx context.Context, taskId string, err error) {
+	h.messageService.Finish(ctx, taskId, &core.MessageTaskResult{
+		Status: core.MessageFailed,
+		Error:  err.Error(),
+	})
+}
+
+// Tells if the chat of the message was purged after the task was
+// queued, the task is dropped so the chat is not brought back
+func (h *MessageTaskHandler) purged(ctx context.Context, taskId string) bool {
+	if taskId == "" {
+		return false
+	}
+	_, err := h.messageService.GetTask(ctx, taskId)
+	if errors.Is(err, core.MessageNotFound) {
+		slog.Warn("dropping task of a purged chat", "task_id", taskId)
+		return true
 	}
+	return false
+}
 
-	return nil, err
+// Splits a batch into classify tasks, a message that can not be
+// queued is marked as failed instead of retrying the whole batch
+func (h *MessageTaskHandler) handleClassifyMessageBatchTask(
+	ctx context.Context,
+	payload task.ClassifyMessageBatchPayload,
+	enqueueFunc func(string, []byte) error,
+) error {
+	for _, message := range payload.Messages {
+		messageCtx := core.WithTenant(ctx, message.TenantId)
+		payloadBytes, err := json.Marshal(message)
+		if err != nil {
+			h.drop(messageCtx, message.TaskId, err)
+			continue
+		}
+		if err := enqueueFunc(task.ClassifyMessageTaskName, payloadBytes); err != nil {
+			slog.Error("Error queueing batch message", "task_id", message.TaskId, "error", err)
+			h.drop(messageCtx, message.TaskId, err)
+		}
+	}
+	return nil
 }
 
 // ClassifyMemoryTaskHandler handles the heaviest task:
@@ -64,15 +140,24 @@ func (h *MessageTaskHandler) handleClassifyMemoryTask(
 	enqueueFunc func(string, []byte) error,
 ) error {
 	slog.Info("handleClassifyMemoryTask", "payload", payload)
+	ctx = core.WithTenant(ctx, payload.TenantId)
+	if h.purged(ctx, payload.TaskId) {
+		return nil
+	}
+	h.messageService.Start(ctx, payload.TaskId)
 	hasEnhancementCapabilites := h.memoryEnhancementService.IsWorking()
 
 	labeledMessage, err := protos.Predict(payload.Message, payload.ChatId, !hasEnhancementCapabilites)
 	if err != nil {
 		slog.Error("Error predicting message", "error", err)
-		return err
+		return h.fail(ctx, payload.TaskId, err)
 	}
 
 	if labeledMessage.Label == core.NoMemory {
+		h.messageService.Finish(ctx, payload.TaskId, &core.MessageTaskResult{
+			Status: core.MessageDiscarded,
+			Label:  core.NoMemory,
+		})
 		return nil
 	}
 
@@ -87,44 +172,63 @@ func (h *MessageTaskHandler) handleClassifyMemoryTask(
 			labeledMessage.MessageEmbedding = embeddings
 		}
 
-		labeledMessage.RelatedContext = append(
-			labeledMessage.RelatedContext,
-			core.MessageRelatedContext{
-				Context: originalMessage,
-				User:    "user",
-			},
-		)
+		// The original text is kept in the message provenance
 	}
 	slog.Info("final", "labeledMessage", labeledMessage)
-	similarMemory, err := h.checkForSimilarMemory(
+	similarMemory, err := h.memoryVectorService.FindSimilarMemory(
 		ctx, payload.ChatId, labeledMessage.MessageEmbedding,
 	)
 	if err != nil {
-		return err
+		return h.fail(ctx, payload.TaskId, err)
 	}
 	if similarMemory != nil {
 		slog.Info("Similar memory found", "message", payload.Message)
-		if labeledMessage.Label == core.ShortTerm {
+		if labeledMessage.Label == core.ShortTerm &&
+			similarMemory.Payload.MemoryType == core.ShortTerm {
 			// Merge the memories
-			h.shortTermMemoryService.Merge(
+			merged, err := h.shortTermMemoryService.Merge(
 				ctx,
 				payload.ChatId,
 				similarMemory.Payload.MemoryId,
 				payload.Message,
 				payload.RelatedContext,
+				similarMemory.Score,
 			)
+			if err != nil {
+				return h.fail(ctx, payload.TaskId, err)
+			}
+			h.eventService.Emit(ctx, payload.ChatId, core.MemoryEvent{
+				Type:       core.MemoryMergedEvent,
+				MemoryId:   merged.Id,
+				MemoryType: core.ShortTerm,
+				Memory:     merged.Memory,
+			})
+			h.messageService.Finish(ctx, payload.TaskId, &core.MessageTaskResult{
+				Status:   core.MessageMerged,
+				Label:    labeledMessage.Label,
+				MemoryId: merged.Id,
+			})
+			return nil
 		}
+		// Otherwise the similar memory already covers the message, which
+		// is not part of it and so is left out of its provenance
+		h.messageService.Finish(ctx, payload.TaskId, &core.MessageTaskResult{
+			Status: core.MessageCovered,
+			Label:  labeledMessage.Label,
+		})
 		return nil
 	}
 	labeledMessage.RelatedContext = payload.RelatedContext
 
 	storeMemoryPayload := task.StoreMemoryPayload{
 		LabeledMessage: *labeledMessage,
+		TenantId:       payload.TenantId,
+		TaskId:         payload.TaskId,
 	}
 	slog.Info("store", "storeMemoryPayload", storeMemoryPayload)
 	payloadBytes, err := json.Marshal(storeMemoryPayload)
 	if err != nil {
-		return err
+		return h.fail(ctx, payload.TaskId, err)
 	}
 
 	storeTaskName, err := func() (string, error) {
@@ -139,11 +243,11 @@ func (h *MessageTaskHandler) handleClassifyMemoryTask(
 	}()
 
 	if err != nil {
-		return err
+		return h.fail(ctx, payload.TaskId, err)
 	}
 
 	if err = enqueueFunc(storeTaskName, payloadBytes); err != nil {
-		return err
+		return h.fail(ctx, payload.TaskId, err)
 	}
 
 	return nil
@@ -154,6 +258,21 @@ func (h *MessageTaskHandler) handleStoreLongTermMemoryTask(
 	ctx context.Context,
 	payload task.StoreMemoryPayload,
 ) error {
+	ctx = core.WithTenant(ctx, payload.TenantId)
+	if h.purged(ctx, payload.TaskId) {
+		return nil
+	}
+	h.startRetry(ctx, payload.TaskId)
+	err := h.tenantService.CheckMemoryQuota(ctx)
+	if errors.Is(err, core.TenantQuotaExceeded) {
+		// Retrying would not help, the memory is dropped
+		slog.Warn("HandleStoreLongTermMemoryTask", "tenant_id", payload.TenantId, "error", err)
+		h.drop(ctx, payload.TaskId, err)
+		return nil
+	}
+	if err != nil {
+		return h.fail(ctx, payload.TaskId, err)
+	}
 	createdMemory, err := h.longTermMemoryService.Create(
 		ctx,
 		payload.Message,
@@ -162,7 +281,7 @@ func (h *MessageTaskHandler) handleStoreLongTermMemoryTask(
 	)
 	if err != nil {
 		slog.Error("HandleStoreLongTermMemoryTask", "error", err)
-		return err
+		return h.fail(ctx, payload.TaskId, err)
 	}
 	if err := h.memoryVectorService.CreateMemoryVector(
 		ctx,
@@ -172,8 +291,19 @@ func (h *MessageTaskHandler) handleStoreLongTermMemoryTask(
 		createdMemory.Id,
 	); err != nil {
 		slog.Error("HandleStoreLongTermMemoryTask", "error", err)
-		return err
+		return h.fail(ctx, payload.TaskId, err)
 	}
+	h.eventService.Emit(ctx, payload.ChatId, core.MemoryEvent{
+		Type:       core.MemoryCreatedEvent,
+		MemoryId:   createdMemory.Id,
+		MemoryType: core.LongTerm,
+		Memory:     createdMemory.Memory,
+	})
+	h.messageService.Finish(ctx, payload.TaskId, &core.MessageTaskResult{
+		Status:   core.MessageStored,
+		Label:    core.LongTerm,
+		MemoryId: createdMemory.Id,
+	})
 	return nil
 }
 
@@ -182,6 +312,21 @@ func (h *MessageTaskHandler) handleStoreShortTermMemoryTask(
 	ctx context.Context,
 	payload task.StoreMemoryPayload,
 ) error {
+	ctx = core.WithTenant(ctx, payload.TenantId)
+	if h.purged(ctx, payload.TaskId) {
+		return nil
+	}
+	h.startRetry(ctx, payload.TaskId)
+	err := h.tenantService.CheckMemoryQuota(ctx)
+	if errors.Is(err, core.TenantQuotaExceeded) {
+		// Retrying would not help, the memory is dropped
+		slog.Warn("HandleStoreShortTermMemoryTask", "tenant_id", payload.TenantId, "error", err)
+		h.drop(ctx, payload.TaskId, err)
+		return nil
+	}
+	if err != nil {
+		return h.fail(ctx, payload.TaskId, err)
+	}
 	createdMemory, err := h.shortTermMemoryService.Create(
 		ctx,
 		payload.Message,
@@ -190,7 +335,7 @@ func (h *MessageTaskHandler) handleStoreShortTermMemoryTask(
 	)
 	if err != nil {
 		slog.Error("HandleStoreShortTermMemoryTask", "error", err)
-		return err
+		return h.fail(ctx, payload.TaskId, err)
 	}
 	if err := h.memoryVectorService.CreateMemoryVector(
 		ctx,
@@ -200,7 +345,18 @@ func (h *MessageTaskHandler) handleStoreShortTermMemoryTask(
 		createdMemory.Id,
 	); err != nil {
 		slog.Error("HandleStoreShortTermMemoryTask", "error", err)
-		return err
+		return h.fail(ctx, payload.TaskId, err)
 	}
+	h.eventService.Emit(ctx, payload.ChatId, core.MemoryEvent{
+		Type:       core.MemoryCreatedEvent,
+		MemoryId:   createdMemory.Id,
+		MemoryType: core.ShortTerm,
+		Memory:     createdMemory.Memory,
+	})
+	h.messageService.Finish(ctx, payload.TaskId, &core.MessageTaskResult{
+		Status:   core.MessageStored,
+		Label:    core.ShortTerm,
+		MemoryId: createdMemory.Id,
+	})
 	return nil
 }
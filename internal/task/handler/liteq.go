@@ -7,6 +7,7 @@ import (
 	"github.com/Mateus-Lacerda/better-mem/internal/task"
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/khepin/liteq"
 )
@@ -27,6 +28,29 @@ func enqueueFunc(taskName string, payloadBytes []byte) error {
 	)
 }
 
+// Queues the job to run once the delay has passed
+func enqueueAfterFunc(taskName string, payloadBytes []byte, delay time.Duration) error {
+	db, err := sqlite.GetDb().DB()
+	if err != nil {
+		return err
+	}
+	jqueue := liteq.New(db)
+	return jqueue.QueueJob(
+		context.Background(),
+		liteq.QueueJobParams{
+			Queue:        taskName,
+			Job:          string(payloadBytes),
+			ExecuteAfter: time.Now().Add(delay).Unix(),
+		},
+	)
+}
+
+// liteq counts down the attempts left, the job fails for good once
+// the one it is on fails, and keeps the error of every failed one
+func jobAttempt(job *liteq.Job) attempt {
+	return attempt{retry: len(job.Errors) > 0, last: job.RemainingAttempts <= 1}
+}
+
 func (h *MessageTaskHandler) HandleClassifyMemoryTask(
 	ctx context.Context, job *liteq.Job,
 ) error {
@@ -34,7 +58,17 @@ func (h *MessageTaskHandler) HandleClassifyMemoryTask(
 	if err := json.Unmarshal([]byte(job.Job), &payload); err != nil {
 		return err
 	}
-	return h.handleClassifyMemoryTask(ctx, payload, enqueueFunc)
+	return h.handleClassifyMemoryTask(withAttempt(ctx, jobAttempt(job)), payload, enqueueFunc)
+}
+
+func (h *MessageTaskHandler) HandleClassifyMessageBatchTask(
+	ctx context.Context, job *liteq.Job,
+) error {
+	var payload task.ClassifyMessageBatchPayload
+	if err := json.Unmarshal([]byte(job.Job), &payload); err != nil {
+		return err
+	}
+	return h.handleClassifyMessageBatchTask(ctx, payload, enqueueFunc)
 }
 
 func (h *MessageTaskHandler) HandleStoreLongTermMemoryTask(
@@ -44,7 +78,7 @@ func (h *MessageTaskHandler) HandleStoreLongTermMemoryTask(
 	if err := json.Unmarshal([]byte(job.Job), &payload); err != nil {
 		return err
 	}
-	return h.handleStoreLongTermMemoryTask(ctx, payload)
+	return h.handleStoreLongTermMemoryTask(withAttempt(ctx, jobAttempt(job)), payload)
 }
 
 func (h *MessageTaskHandler) HandleStoreShortTermMemoryTask(
@@ -54,7 +88,7 @@ func (h *MessageTaskHandler) HandleStoreShortTermMemoryTask(
 	if err := json.Unmarshal([]byte(job.Job), &payload); err != nil {
 		return err
 	}
-	return h.handleStoreLongTermMemoryTask(ctx, payload)
+	return h.handleStoreShortTermMemoryTask(withAttempt(ctx, jobAttempt(job)), payload)
 }
 
 func (m *MemoryManagementHandler) HandleManageMemory(
@@ -62,3 +96,29 @@ func (m *MemoryManagementHandler) HandleManageMemory(
 ) error {
 	return m.handleManageMemory(ctx)
 }
+
+func (h *WebhookTaskHandler) HandleDispatchWebhookTask(
+	ctx context.Context, job *liteq.Job,
+) error {
+	var payload task.DispatchWebhookPayload
+	if err := json.Unmarshal([]byte(job.Job), &payload); err != nil {
+		return err
+	}
+	return h.handleDispatchWebhookTask(ctx, payload, enqueueFunc)
+}
+
+func (h *WebhookTaskHandler) HandleDeliverWebhookTask(
+	ctx context.Context, job *liteq.Job,
+) error {
+	var payload task.DeliverWebhookPayload
+	if err := json.Unmarshal([]byte(job.Job), &payload); err != nil {
+		return err
+	}
+	return h.handleDeliverWebhookTask(ctx, payload, enqueueAfterFunc)
+}
+
+func (h *RetentionTaskHandler) HandlePurgeInactiveMemories(
+	ctx context.Context, job *liteq.Job,
+) error {
+	return h.handlePurgeInactiveMemories(ctx)
+}
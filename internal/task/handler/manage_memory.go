@@ -11,6 +11,7 @@ import (
 
 type MemoryManagementHandler struct {
 	chatService             *service.ChatService
+	tenantService           *service.TenantService
 	memoryManagementService *service.MemoryManagementService
 	completed               chan core.MemoryManagementResult
 	wgCompleted             sync.WaitGroup
@@ -18,11 +19,13 @@ type MemoryManagementHandler struct {
 
 func NewMemoryManagementHandler(
 	chatService *service.ChatService,
+	tenantService *service.TenantService,
 	memoryManagementService *service.MemoryManagementService,
 ) *MemoryManagementHandler {
 
 	handler := &MemoryManagementHandler{
 		chatService:             chatService,
+		tenantService:           tenantService,
 		memoryManagementService: memoryManagementService,
 		completed: make(
 			chan core.MemoryManagementResult,
@@ -64,31 +67,49 @@ func (m *MemoryManagementHandler) ManageShortTermMemory(
 	}
 }
 
+// A chat to be managed, with the context scoped to its tenant
+type manageMemoryJob struct {
+	ctx    context.Context
+	chatId string
+}
+
 func (m *MemoryManagementHandler) handleManageMemory(
 	ctx context.Context,
 ) error {
-	chats, err := m.chatService.GetAll(ctx)
+	tenants, err := m.tenantService.GetAll(ctx)
 	if err != nil {
-		slog.Error("error getting chats", "error", err)
+		slog.Error("error getting tenants", "error", err)
 		return err
 	}
-	slog.Info("managing memory", "chats", len(chats))
-	if len(chats) == 0 {
+	var jobsToRun []manageMemoryJob
+	for _, tenant := range tenants {
+		tenantCtx := core.WithTenant(ctx, tenant.Id)
+		chats, err := m.chatService.GetAll(tenantCtx)
+		if err != nil {
+			slog.Error("error getting chats", "tenant_id", tenant.Id, "error", err)
+			return err
+		}
+		for _, chat := range chats {
+			jobsToRun = append(jobsToRun, manageMemoryJob{ctx: tenantCtx, chatId: chat.ID})
+		}
+	}
+	slog.Info("managing memory", "tenants", len(tenants), "chats", len(jobsToRun))
+	if len(jobsToRun) == 0 {
 		return nil
 	}
-	jobs := make(chan string, len(chats))
+	jobs := make(chan manageMemoryJob, len(jobsToRun))
 
 	var wgWorkers sync.WaitGroup
 
 	for range config.MemoryManagement.MaxSimultaneousTasks {
 		wgWorkers.Go(func() {
-			for chatId := range jobs {
-				m.ManageShortTermMemory(ctx, chatId)
+			for job := range jobs {
+				m.ManageShortTermMemory(job.ctx, job.chatId)
 			}
 		})
 	}
-	for _, chat := range chats {
-		jobs <- chat.ExternalId
+	for _, job := range jobsToRun {
+		jobs <- job
 	}
 	close(jobs)
 
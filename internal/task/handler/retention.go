@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
+	"github.com/Mateus-Lacerda/better-mem/internal/service"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"context"
+	"log/slog"
+	"time"
+)
+
+type RetentionTaskHandler struct {
+	tenantService    *service.TenantService
+	retentionService *service.RetentionService
+}
+
+func NewRetentionTaskHandler(
+	tenantService *service.TenantService,
+	retentionService *service.RetentionService,
+) *RetentionTaskHandler {
+	return &RetentionTaskHandler{
+		tenantService:    tenantService,
+		retentionService: retentionService,
+	}
+}
+
+// Deletes the memories of every tenant that stayed inactive past the
+// retention period, the run is recorded even when it fails midway
+func (h *RetentionTaskHandler) handlePurgeInactiveMemories(ctx context.Context) error {
+	if config.Retention.InactiveMemoryDays <= 0 {
+		return nil
+	}
+	startedAt := time.Now()
+	run := &core.RetentionRun{
+		RetentionDays: config.Retention.InactiveMemoryDays,
+		Cutoff:        startedAt.AddDate(0, 0, -config.Retention.InactiveMemoryDays),
+		StartedAt:     startedAt,
+	}
+	err := h.purge(ctx, run)
+	if err != nil {
+		run.Error = err.Error()
+	}
+	run.CompletedAt = time.Now()
+	if err := h.retentionService.SaveRun(ctx, run); err != nil {
+		slog.Error("error saving retention run", "error", err)
+	}
+	slog.Info(
+		"inactive memories purged",
+		"tenants", run.Tenants,
+		"cutoff", run.Cutoff,
+		"deleted", run.Deleted,
+		"error", run.Error,
+	)
+	return err
+}
+
+func (h *RetentionTaskHandler) purge(ctx context.Context, run *core.RetentionRun) error {
+	tenants, err := h.tenantService.GetAll(ctx)
+	if err != nil {
+		slog.Error("error getting tenants", "error", err)
+		return err
+	}
+	for _, tenant := range tenants {
+		deleted, err := h.retentionService.PurgeInactive(core.WithTenant(ctx, tenant.Id), run.Cutoff)
+		run.Deleted.Add(*deleted)
+		if err != nil {
+			slog.Error("error purging inactive memories", "tenant_id", tenant.Id, "error", err)
+			return err
+		}
+		run.Tenants++
+	}
+	// Only once every memory is gone is its related context orphaned
+	orphaned, err := h.retentionService.PurgeOrphanedRelatedContext(ctx)
+	if err != nil {
+		slog.Error("error purging orphaned related context", "error", err)
+		return err
+	}
+	run.Deleted.OrphanedRelatedContexts = orphaned
+	return nil
+}
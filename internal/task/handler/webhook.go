@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/internal/config"
+	"github.com/Mateus-Lacerda/better-mem/internal/service"
+	"github.com/Mateus-Lacerda/better-mem/internal/task"
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+type WebhookTaskHandler struct {
+	webhookService *service.WebhookService
+	chatService    *service.ChatService
+}
+
+func NewWebhookTaskHandler(
+	webhookService *service.WebhookService,
+	chatService *service.ChatService,
+) *WebhookTaskHandler {
+	return &WebhookTaskHandler{
+		webhookService: webhookService,
+		chatService:    chatService,
+	}
+}
+
+// Queues a delivery of the event for every webhook listening to it
+func (h *WebhookTaskHandler) handleDispatchWebhookTask(
+	ctx context.Context,
+	payload task.DispatchWebhookPayload,
+	enqueueFunc func(string, []byte) error,
+) error {
+	ctx = core.WithTenant(ctx, payload.Event.TenantId)
+	webhooks, err := h.webhookService.Subscribed(ctx, payload.ChatId, payload.Event.Type)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+	chat, err := h.chatService.GetById(ctx, payload.ChatId)
+	if errors.Is(err, core.ChatNotFound) {
+		// The chat is gone, nobody could act on the event
+		slog.Warn("dropping memory event", "event_id", payload.Event.Id, "chat_id", payload.ChatId)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	event := payload.Event
+	event.ChatId = chat.ExternalId
+	for _, webhook := range webhooks {
+		payloadBytes, err := json.Marshal(task.DeliverWebhookPayload{
+			Event:     event,
+			WebhookId: webhook.Id,
+			Attempt:   1,
+		})
+		if err != nil {
+			return err
+		}
+		if err := enqueueFunc(task.DeliverWebhookTaskName, payloadBytes); err != nil {
+			slog.Error("Error queueing webhook delivery", "webhook_id", webhook.Id, "error", err)
+		}
+	}
+	return nil
+}
+
+// Delivers the event to the webhook, a failed delivery is queued again
+// with an exponential backoff until the attempts run out
+func (h *WebhookTaskHandler) handleDeliverWebhookTask(
+	ctx context.Context,
+	payload task.DeliverWebhookPayload,
+	enqueueAfterFunc func(string, []byte, time.Duration) error,
+) error {
+	ctx = core.WithTenant(ctx, payload.Event.TenantId)
+	webhook, err := h.webhookService.GetById(ctx, payload.WebhookId)
+	if errors.Is(err, core.WebhookNotFound) {
+		// Removed after the event was dispatched
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	delivery, err := h.webhookService.Deliver(ctx, webhook, &payload.Event, payload.Attempt)
+	if err != nil {
+		return err
+	}
+	if delivery.Success {
+		return nil
+	}
+	if payload.Attempt >= config.Webhook.MaxAttempts {
+		slog.Warn(
+			"giving up webhook delivery",
+			"webhook_id", webhook.Id,
+			"event_id", payload.Event.Id,
+			"attempts", payload.Attempt,
+			"error", delivery.Error,
+		)
+		return nil
+	}
+	backoff := time.Duration(config.Webhook.RetryBackoff) * time.Second << (payload.Attempt - 1)
+	payload.Attempt++
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return enqueueAfterFunc(task.DeliverWebhookTaskName, payloadBytes, backoff)
+}
@@ -6,19 +6,24 @@ import (
 	"github.com/Mateus-Lacerda/better-mem/pkg/core"
 	"github.com/Mateus-Lacerda/better-mem/internal/database/sqlite"
 	"context"
+	"encoding/json"
 
 	"github.com/khepin/liteq"
+	"gorm.io/gorm"
 )
 
 func NewClassifyMessageTask(
-	chatId, message string, relatedContext []core.MessageRelatedContext,
+	taskId, tenantId, chatId, message string, relatedContext []core.MessageRelatedContext,
 ) error {
 	db, err := sqlite.GetDb().DB()
 	if err != nil {
 		return err
 	}
 	jqueue := liteq.New(db)
-	payload, err := getClassifiyMessageTaskPayload(chatId, message, relatedContext)
+	payload, err := getClassifiyMessageTaskPayload(taskId, tenantId, chatId, message, relatedContext)
+	if err != nil {
+		return err
+	}
 	return jqueue.QueueJob(
 		context.Background(),
 		liteq.QueueJobParams{
@@ -27,3 +32,103 @@ func NewClassifyMessageTask(
 		},
 	)
 }
+
+// Queues the whole batch as a single job, the worker splits it
+func NewClassifyMessageBatchTask(messages []ClassifyMessagePayload) error {
+	db, err := sqlite.GetDb().DB()
+	if err != nil {
+		return err
+	}
+	jqueue := liteq.New(db)
+	payload, err := json.Marshal(ClassifyMessageBatchPayload{Messages: messages})
+	if err != nil {
+		return err
+	}
+	return jqueue.QueueJob(
+		context.Background(),
+		liteq.QueueJobParams{
+			Queue: ClassifyMessageBatchTaskName,
+			Job:   string(payload),
+		},
+	)
+}
+
+// Queues an event to be sent to the webhooks listening to it
+func NewDispatchWebhookTask(event DispatchWebhookPayload) error {
+	db, err := sqlite.GetDb().DB()
+	if err != nil {
+		return err
+	}
+	jqueue := liteq.New(db)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return jqueue.QueueJob(
+		context.Background(),
+		liteq.QueueJobParams{
+			Queue: DispatchWebhookTaskName,
+			Job:   string(payload),
+		},
+	)
+}
+
+// Goes through the jobs of the chat, skipping the ones the worker is
+// running, handle gets what purging each job takes
+func forEachChatJob(
+	ctx context.Context,
+	chat purgedChat,
+	handle func(db *gorm.DB, id int64, drop bool, rewritten []byte) error,
+) (int, error) {
+	db := sqlite.GetDb().WithContext(ctx)
+	markers := chat.markers()
+	var jobs []struct {
+		Id    int64
+		Queue string
+		Job   string
+	}
+	err := db.Table("jobs").
+		Select("id", "queue", "job").
+		Where("job_status != ?", "fetched").
+		Where("instr(job, ?) > 0 OR instr(job, ?) > 0", markers[0], markers[1]).
+		Find(&jobs).Error
+	if err != nil {
+		return 0, err
+	}
+	found := 0
+	for _, job := range jobs {
+		drop, rewritten, err := purgeChatPayload(job.Queue, []byte(job.Job), chat)
+		if err != nil {
+			return found, err
+		}
+		if !drop && rewritten == nil {
+			continue
+		}
+		if err := handle(db, job.Id, drop, rewritten); err != nil {
+			return found, err
+		}
+		found++
+	}
+	return found, nil
+}
+
+// Removes the jobs of the chat from the queue, finished ones included
+// since they still hold the messages. A job the worker is running is
+// left to it. Returns how many jobs were removed or rewritten
+func PurgeChatTasks(ctx context.Context, tenantId, chatId, externalId string) (int, error) {
+	chat := purgedChat{tenantId: tenantId, chatId: chatId, externalId: externalId}
+	return forEachChatJob(ctx, chat, func(db *gorm.DB, id int64, drop bool, rewritten []byte) error {
+		if drop {
+			return db.Exec("DELETE FROM jobs WHERE id = ?", id).Error
+		}
+		return db.Exec("UPDATE jobs SET job = ? WHERE id = ?", string(rewritten), id).Error
+	})
+}
+
+// Counts the jobs PurgeChatTasks would remove or rewrite
+func CountChatTasks(ctx context.Context, tenantId, chatId, externalId string) (int, error) {
+	chat := purgedChat{tenantId: tenantId, chatId: chatId, externalId: externalId}
+	return forEachChatJob(ctx, chat, func(*gorm.DB, int64, bool, []byte) error {
+		return nil
+	})
+}
@@ -0,0 +1,73 @@
+package task
+
+import (
+	"encoding/json"
+)
+
+// The chat whose jobs are being purged
+type purgedChat struct {
+	tenantId   string
+	chatId     string
+	externalId string
+}
+
+// The chat ids as they appear in a raw payload, every job of the chat
+// holds one of them, so the jobs of other chats are not decoded
+func (c purgedChat) markers() []string {
+	internal, _ := json.Marshal(c.chatId)
+	external, _ := json.Marshal(c.externalId)
+	return []string{string(internal), string(external)}
+}
+
+// Tells what to do with a job when its chat is purged. A job of the
+// chat is dropped, a batch that also has messages of other chats is
+// kept with only those, any other job is kept as is
+func purgeChatPayload(taskName string, payload []byte, chat purgedChat) (drop bool, rewritten []byte, err error) {
+	switch taskName {
+	case ClassifyMessageTaskName:
+		var p ClassifyMessagePayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return false, nil, err
+		}
+		return p.TenantId == chat.tenantId && p.ChatId == chat.chatId, nil, nil
+	case ClassifyMessageBatchTaskName:
+		var p ClassifyMessageBatchPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return false, nil, err
+		}
+		kept := make([]ClassifyMessagePayload, 0, len(p.Messages))
+		for _, message := range p.Messages {
+			if message.TenantId != chat.tenantId || message.ChatId != chat.chatId {
+				kept = append(kept, message)
+			}
+		}
+		if len(kept) == len(p.Messages) {
+			return false, nil, nil
+		}
+		if len(kept) == 0 {
+			return true, nil, nil
+		}
+		rewritten, err := json.Marshal(ClassifyMessageBatchPayload{Messages: kept})
+		return false, rewritten, err
+	case StoreLongTermMemoryTaskName, StoreShortTermMemoryTaskName:
+		var p StoreMemoryPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return false, nil, err
+		}
+		return p.TenantId == chat.tenantId && p.ChatId == chat.chatId, nil, nil
+	case DispatchWebhookTaskName:
+		var p DispatchWebhookPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return false, nil, err
+		}
+		return p.Event.TenantId == chat.tenantId && p.ChatId == chat.chatId, nil, nil
+	case DeliverWebhookTaskName:
+		// The event already carries the external id
+		var p DeliverWebhookPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return false, nil, err
+		}
+		return p.Event.TenantId == chat.tenantId && p.Event.ChatId == chat.externalId, nil, nil
+	}
+	return false, nil, nil
+}
@@ -0,0 +1,6 @@
+package task
+
+// Definitions
+const (
+	PurgeInactiveMemoriesTaskName = "memory:purge-inactive"
+)
@@ -0,0 +1,26 @@
+package task
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+)
+
+// Definitions
+const (
+	// DispatchWebhookTaskName is the name of the task that finds the webhooks of an event.
+	DispatchWebhookTaskName = "webhook:dispatch"
+	// DeliverWebhookTaskName is the name of the task that posts an event to a webhook.
+	DeliverWebhookTaskName = "webhook:deliver"
+)
+
+type DispatchWebhookPayload struct {
+	Event core.MemoryEvent `json:"event"`
+	// The internal id of the chat, the event carries the external one
+	ChatId string `json:"chat_id"`
+}
+
+type DeliverWebhookPayload struct {
+	Event     core.MemoryEvent `json:"event"`
+	WebhookId string           `json:"webhook_id"`
+	// The attempt being made, starting at 1
+	Attempt int `json:"attempt"`
+}
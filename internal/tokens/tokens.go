@@ -0,0 +1,53 @@
+package tokens
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// About four characters per token, close to the BPE tokenizers of most LLMs on English text
+	CharsTokenizer = "chars"
+	// About three tokens every four words, steadier on text with long words or numbers
+	WordsTokenizer = "words"
+)
+
+// Estimates how many tokens a text takes in a prompt
+type Counter interface {
+	Count(text string) int
+}
+
+type chars struct{}
+
+func (chars) Count(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+type words struct{}
+
+func (words) Count(text string) int {
+	return (len(strings.Fields(text))*4 + 2) / 3
+}
+
+// Returns the named counter, an empty name picks the chars one
+func New(name string) (Counter, error) {
+	switch name {
+	case "", CharsTokenizer:
+		return chars{}, nil
+	case WordsTokenizer:
+		return words{}, nil
+	}
+	return nil, core.InvalidTokenizer
+}
+
+// Tokens of a memory, with its related context when asked to
+func CountMemory(counter Counter, memory *core.ScoredMemory, withRelatedContext bool) int {
+	count := counter.Count(memory.Text)
+	if withRelatedContext {
+		for _, context := range memory.RelatedContext {
+			count += counter.Count(context.User) + counter.Count(context.Context)
+		}
+	}
+	return count
+}
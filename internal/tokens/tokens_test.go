@@ -0,0 +1,66 @@
+package tokens
+
+import (
+	"github.com/Mateus-Lacerda/better-mem/pkg/core"
+	"errors"
+	"testing"
+)
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenizer string
+		text      string
+		want      int
+	}{
+		{name: "chars empty", tokenizer: CharsTokenizer, text: "", want: 0},
+		{name: "chars rounds up", tokenizer: CharsTokenizer, text: "hello", want: 2},
+		{name: "chars four per token", tokenizer: CharsTokenizer, text: "abcdefgh", want: 2},
+		{name: "chars counts runes", tokenizer: CharsTokenizer, text: "ãçéü", want: 1},
+		{name: "words empty", tokenizer: WordsTokenizer, text: "", want: 0},
+		{name: "words one word", tokenizer: WordsTokenizer, text: "hello", want: 2},
+		{name: "words three words", tokenizer: WordsTokenizer, text: "I love  LLMs", want: 4},
+		{name: "default is chars", tokenizer: "", text: "hello", want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter, err := New(tt.tokenizer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := counter.Count(tt.text); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewUnknown(t *testing.T) {
+	if _, err := New("tiktoken"); !errors.Is(err, core.InvalidTokenizer) {
+		t.Errorf("got error %v, want %v", err, core.InvalidTokenizer)
+	}
+}
+
+func TestCountMemory(t *testing.T) {
+	memory := &core.ScoredMemory{
+		Text: "abcdefgh",
+		RelatedContext: []core.MessageRelatedContext{
+			{User: "user", Context: "abcdefghijkl"},
+		},
+	}
+	tests := []struct {
+		name               string
+		withRelatedContext bool
+		want               int
+	}{
+		{name: "text only", withRelatedContext: false, want: 2},
+		{name: "with related context", withRelatedContext: true, want: 2 + 1 + 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CountMemory(chars{}, memory, tt.withRelatedContext); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,9 +8,11 @@ import (
 type UnitOfWork[T any, C any] interface {
 	Repositories(tx C) repository.AllRepositories
 
+	// Runs fn in a transaction, the repositories must be called
+	// with the context fn gets for their calls to be part of it
 	Do(
 		ctx context.Context, fn func(
-			repos repository.AllRepositories,
+			ctx context.Context, repos repository.AllRepositories,
 		) (T, error),
 	) (T, error)
 }
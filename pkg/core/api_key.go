@@ -0,0 +1,86 @@
+package core
+
+import "time"
+
+type ApiKeyScope string
+
+const (
+	// Allows reading and fetching memories
+	MemoryReadScope ApiKeyScope = "memory:read"
+	// Allows sending messages and creating, updating or deleting memories
+	MemoryWriteScope ApiKeyScope = "memory:write"
+	// Allows creating and listing chats
+	ChatAdminScope ApiKeyScope = "chat:admin"
+	// Allows everything, including managing api keys
+	AdminScope ApiKeyScope = "admin"
+)
+
+// All the scopes an api key can be issued with
+var ApiKeyScopes = []ApiKeyScope{
+	MemoryReadScope,
+	MemoryWriteScope,
+	ChatAdminScope,
+	AdminScope,
+}
+
+// Request schema for issuing an api key
+type NewApiKey struct {
+	// A name to identify the key
+	Name string `json:"name" example:"support-dashboard"`
+	// The scopes granted to the key
+	Scopes []ApiKeyScope `json:"scopes" example:"memory:read,memory:write"`
+	// The tenant the key acts on behalf of (Default: default)
+	TenantId string `json:"tenant_id" example:"acme"`
+}
+
+type ApiKey struct {
+	Id string `json:"id"`
+	// A name to identify the key
+	Name string `json:"name"`
+	// The first characters of the key, to tell keys apart
+	Prefix string `json:"prefix"`
+	// The scopes granted to the key
+	Scopes []ApiKeyScope `json:"scopes"`
+	// The tenant the key acts on behalf of
+	TenantId  string    `json:"tenant_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Revoked   bool      `json:"revoked"`
+}
+
+// An api key as stored, the key itself is never stored
+type ApiKeyModel struct {
+	Name      string
+	Prefix    string
+	Hash      string
+	Scopes    []ApiKeyScope
+	TenantId  string
+	CreatedAt time.Time
+}
+
+// A newly issued api key, the only moment the key is visible
+type IssuedApiKey struct {
+	ApiKey
+	// The key to be sent as "Authorization: Bearer <key>"
+	Key string `json:"key"`
+}
+
+// HasScope tells if the key was granted the scope,
+// the admin scope grants every scope
+func (k *ApiKey) HasScope(scope ApiKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == AdminScope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidApiKeyScope tells if the scope is one of the known scopes
+func IsValidApiKeyScope(scope ApiKeyScope) bool {
+	for _, s := range ApiKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -1,5 +1,7 @@
 package core
 
+import "time"
+
 type NewChat struct {
 	ExternalId string `json:"external_id"`
 }
@@ -7,4 +9,41 @@ type NewChat struct {
 type Chat struct {
 	ExternalId string `json:"external_id"`
 	ID         string `json:"id"`
+	TenantId   string `json:"tenant_id"`
+}
+
+// How much data of a chat there is in each store
+type ChatDataCounts struct {
+	Chats             int `json:"chats"`
+	ShortTermMemories int `json:"short_term_memories"`
+	LongTermMemories  int `json:"long_term_memories"`
+	// Related context attached to memories, messages and merges
+	RelatedContexts   int `json:"related_contexts"`
+	Messages          int `json:"messages"`
+	MemoryMerges      int `json:"memory_merges"`
+	MemoryEvents      int `json:"memory_events"`
+	Webhooks          int `json:"webhooks"`
+	WebhookDeliveries int `json:"webhook_deliveries"`
+	Vectors           int `json:"vectors"`
+	// Jobs waiting in the queue with data of the chat
+	QueuedJobs int `json:"queued_jobs"`
+}
+
+// IsEmpty tells if no data is left
+func (c *ChatDataCounts) IsEmpty() bool {
+	return *c == ChatDataCounts{}
+}
+
+// What was removed when a chat was purged
+type ChatDeletionReport struct {
+	// The external id of the chat
+	ChatId   string         `json:"chat_id"`
+	TenantId string         `json:"tenant_id"`
+	Deleted  ChatDataCounts `json:"deleted"`
+	// Counted again after the purge, every store should be empty
+	Remaining ChatDataCounts `json:"remaining"`
+	// True when nothing of the chat was found after the purge
+	Verified    bool      `json:"verified"`
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
 }
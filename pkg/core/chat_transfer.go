@@ -0,0 +1,50 @@
+package core
+
+import "time"
+
+// Version of the chat export format, bumped whenever a line changes
+// in a way older versions can not read
+const ChatExportVersion = 1
+
+type ChatExportLineType string
+
+const (
+	// The first line, describes the export
+	ChatExportHeaderLine ChatExportLineType = "header"
+	// The second line, the chat itself
+	ChatExportChatLine            ChatExportLineType = "chat"
+	ChatExportShortTermMemoryLine ChatExportLineType = "short_term_memory"
+	ChatExportLongTermMemoryLine  ChatExportLineType = "long_term_memory"
+)
+
+// A line of a chat export, only the field of its type is set
+type ChatExportLine struct {
+	Type            ChatExportLineType `json:"type"`
+	Header          *ChatExportHeader  `json:"header,omitempty"`
+	Chat            *NewChat           `json:"chat,omitempty"`
+	ShortTermMemory *ShortTermMemory   `json:"short_term_memory,omitempty"`
+	LongTermMemory  *LongTermMemory    `json:"long_term_memory,omitempty"`
+	// Embedding of the memory, when exported with embeddings
+	Embedding []float32 `json:"embedding,omitempty"`
+}
+
+// Describes a chat export
+type ChatExportHeader struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	// Whether the memories carry their embeddings
+	Embeddings bool `json:"embeddings"`
+	// Size of the embeddings, zero without them
+	Dimension int `json:"dimension"`
+}
+
+// What an import created
+type ChatImportReport struct {
+	// The external id of the imported chat
+	ChatId            string `json:"chat_id"`
+	ShortTermMemories int    `json:"short_term_memories"`
+	LongTermMemories  int    `json:"long_term_memories"`
+	// Memories embedded again since their embedding was missing
+	// or of another size than the vector store's
+	Reembedded int `json:"reembedded"`
+}
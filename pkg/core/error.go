@@ -11,4 +11,68 @@ var (
 	ChatExternalIdAlreadyExists = errors.New("This external id is already taken")
 	// Returned when there is an unexpected error on message classification
 	UnexpectedClassificationError = errors.New("Unexpected Classification Error")
+	// Returned when an operation references an unexistent memory
+	MemoryNotFound = errors.New("Memory not found")
+	// Returned when a memory update does not change anything
+	EmptyMemoryUpdate = errors.New("Nothing to update")
+	// Returned when trying to store a memory without text
+	EmptyMemory = errors.New("Memory text can not be empty")
+	// Returned when a memory type is neither short term nor long term
+	InvalidMemoryType = errors.New("Invalid memory type")
+	// Returned when a request has no api key or an unknown or revoked one
+	InvalidApiKey = errors.New("Invalid api key")
+	// Returned when an operation references an unexistent api key
+	ApiKeyNotFound = errors.New("Api key not found")
+	// Returned when issuing an api key with an unknown scope or without scopes
+	InvalidApiKeyScope = errors.New("Invalid api key scope")
+	// Returned when an operation references an unexistent tenant
+	TenantNotFound = errors.New("Tenant not found")
+	// Returned when trying to create a tenant with an existing id
+	TenantAlreadyExists = errors.New("This tenant id is already taken")
+	// Returned when creating a tenant without an id
+	EmptyTenantId = errors.New("Tenant id can not be empty")
+	// Returned when an operation would exceed the tenant quota
+	TenantQuotaExceeded = errors.New("Tenant quota exceeded")
+	// Returned when an operation references an unexistent message
+	MessageNotFound = errors.New("Message not found")
+	// Returned when a message is not processed in time
+	MessageWaitTimeout = errors.New("Timed out waiting for the message to be processed")
+	// Returned when an operation references an unexistent memory merge
+	MemoryMergeNotFound = errors.New("Memory merge not found")
+	// Returned when a fetch asks for an unknown search mode
+	InvalidFetchMode = errors.New("Invalid fetch mode, use vector, keyword or hybrid")
+	// Returned when the fetch diversity is outside of [0, 1]
+	InvalidDiversity = errors.New("Invalid diversity, it must be between 0 and 1")
+	// Returned when a fetch asks for an unknown scorer
+	InvalidScorer = errors.New("Invalid scorer, use relevancy, half_life or weighted")
+	// Returned when scoring weights are negative, all zero or the half life is not positive
+	InvalidScoringWeights = errors.New("Invalid scoring weights, they must not be negative nor all zero and the half life must be positive")
+	// Returned when a fetch asks for an unknown tokenizer
+	InvalidTokenizer = errors.New("Invalid tokenizer, use chars or words")
+	// Returned when the token budget of a fetch is negative
+	InvalidMaxTokens = errors.New("Invalid max tokens, it must not be negative")
+	// Returned when a context asks for a template that was not registered
+	TemplateNotFound = errors.New("Context template not found, use bullet, xml, json or a registered one")
+	// Returned when a fetch asks for an unknown query strategy
+	InvalidQueryStrategy = errors.New("Invalid query strategy, use embedding or rewrite")
+	// Returned when the llm rewrites a query to nothing
+	EmptyRewrittenQuery = errors.New("The rewritten query is empty")
+	// Returned when the llm writes an empty hypothetical memory
+	EmptyHypotheticalMemory = errors.New("The hypothetical memory is empty")
+	// Returned when a fetch batch has no fetches
+	EmptyFetchBatch = errors.New("The batch has no fetches")
+	// Returned when a fetch batch has more fetches than allowed
+	FetchBatchTooLarge = errors.New("The batch has too many fetches")
+	// Returned when a webhook does not exist or belongs to another tenant
+	WebhookNotFound = errors.New("Webhook not found")
+	// Returned when a webhook url is not an absolute http or https url
+	InvalidWebhookUrl = errors.New("Invalid webhook url, it must be an absolute http or https url")
+	// Returned when a webhook listens to an unknown event
+	InvalidWebhookEvent = errors.New("Invalid webhook event, use memory.created, memory.merged, memory.promoted, memory.deactivated or memory.discarded")
+	// Returned when a batch has no messages
+	EmptyMessageBatch = errors.New("The batch has no messages")
+	// Returned when a batch has more messages than allowed
+	MessageBatchTooLarge = errors.New("The batch has too many messages")
+	// Returned when an import is not a chat export this version can read
+	InvalidChatExport = errors.New("Invalid chat export")
 )
@@ -24,10 +24,92 @@ type ScoredMemory struct {
 	MemoryType MemoryTypeEnum `json:"memory_type"`
 	// Context that might be related to the memory
 	RelatedContext []MessageRelatedContext `json:"related_context"`
+	// How the score was reached, only set in explain mode
+	Explanation *MemoryScoreExplanation `json:"explanation,omitempty"`
+	// Tokens the memory takes, only set when fetching with a token budget
+	Tokens int `json:"tokens,omitempty"`
+}
+
+// Breakdown of the signals behind a fetched memory
+type MemoryScoreExplanation struct {
+	// Cosine similarity to the query, when found by the vector search
+	VectorSimilarity *float32 `json:"vector_similarity,omitempty"`
+	// Share of the query words in the memory, when found by the keyword search
+	KeywordScore *float32 `json:"keyword_score,omitempty"`
+	// Score given by the scorer of the fetch
+	ScorerScore float32 `json:"scorer_score"`
+	// Score given by the cross-encoder, when reranked
+	RerankScore *float32 `json:"rerank_score,omitempty"`
+	// Times the memory was fetched
+	AccessCount int `json:"access_count"`
+	// Times a message was merged into the memory (short term only)
+	MergeCount int `json:"merge_count"`
+	// Recency part of the score
+	RecencyScore float32 `json:"recency_score"`
+	// Usage part of the score, from the access and merge counts
+	UsageScore float32 `json:"usage_score"`
+	// The vector similarity is under the vector search threshold
+	BelowVectorSearchThreshold bool `json:"below_vector_search_threshold"`
+	// The long term memory was filtered out by the long term threshold
+	FilteredByLongTermThreshold bool `json:"filtered_by_long_term_threshold"`
+}
+
+// Result of a fetch, the whole of it is the response of the detailed fetch
+type MemoryFetchResult struct {
+	// The fetched memories, as returned by the plain fetch
+	Memories []*ScoredMemory `json:"memories"`
+	// Candidates dropped by the vector search or long term thresholds
+	Rejected []*ScoredMemory `json:"rejected"`
+	// Tokens taken by the memories when fetching with a token budget
+	TokensUsed int `json:"tokens_used"`
+	// Query the search ran with, when it was rewritten from the conversation turns
+	Query string `json:"query,omitempty"`
+	// Hypothetical memory the search also ran with, when fetching with hyde
+	HypotheticalMemory string `json:"hypothetical_memory,omitempty"`
+}
+
+// Several fetches run at once, possibly across chats
+type MemoryFetchBatch struct {
+	Fetches []MemoryFetchBatchEntry `json:"fetches"`
+}
+
+// One fetch of a batch
+type MemoryFetchBatchEntry struct {
+	// The chat to fetch from
+	ChatId string `json:"chat_id" example:"chat-1"`
+	MemoryFetchRequest
+}
+
+// The outcome of one fetch of a batch
+type MemoryFetchBatchItem struct {
+	ChatId string `json:"chat_id"`
+	// The result of the fetch, absent if it failed
+	*MemoryFetchResult
+	Error string `json:"error,omitempty"`
+}
+
+// Request to fetch memories already formatted to go in a prompt
+type MemoryContextRequest struct {
+	MemoryFetchRequest
+	// Template the memories are rendered with: bullet, xml, json or a registered one (Default: CONTEXT_DEFAULT_TEMPLATE)
+	Template string `json:"template" example:"bullet"`
+	// Who the memories are about, used by the templates in their header
+	Subject string `json:"subject" example:"Mateus"`
+}
+
+// Fetched memories and the text they were rendered to
+type MemoryContext struct {
+	// The rendered text, empty when no memory was found
+	Context string `json:"context"`
+	// The memories that were rendered
+	Memories []*ScoredMemory `json:"memories"`
+	// Tokens taken by the memories when fetching with a token budget
+	TokensUsed int `json:"tokens_used"`
 }
 
 // Payload for the memory that is stored in the vector database
 type MemoryPayload struct {
+	TenantId   string         `json:"tenant_id"`
 	ChatId     string         `json:"chat_id"`
 	MemoryType MemoryTypeEnum `json:"memory_type"`
 	MemoryId   string         `json:"memory_id"`
@@ -38,6 +120,7 @@ type MemoryPayload struct {
 // be used to store in the vector database
 func (m *MemoryPayload) ToMap() map[string]any {
 	return map[string]any{
+		"tenant_id":   m.TenantId,
 		"chat_id":     m.ChatId,
 		"memory_type": int(m.MemoryType),
 		"memory_id":   m.MemoryId,
@@ -72,6 +155,105 @@ type MemoryFetchRequest struct {
 	VectorSearchThreshold float32 `json:"vector_search_threshold" example:"0.4"`
 	// Specific threshold for long term memories (Default: 0.8)
 	LongTermThreshold float32 `json:"long_term_threshold" example:"0.6"`
+	// How candidates are searched: vector, keyword or hybrid (Default: vector)
+	Mode FetchMode `json:"mode" example:"hybrid"`
+	// Trade-off between relevance and novelty when picking the memories, from 0 to 1.
+	// Zero disables the maximal marginal relevance re-ranking (Default: 0)
+	Diversity float32 `json:"diversity" example:"0.3"`
+	// Rescores the candidates with the cross-encoder of the inference service before
+	// truncating to the limit, the score becomes the reranker score (Default: false)
+	Rerank bool `json:"rerank" example:"true"`
+	// Returns the score breakdown of each memory and the rejected candidates (Default: false)
+	Explain bool `json:"explain" example:"false"`
+	// How the memories are scored: relevancy, half_life or weighted (Default: SCORING_DEFAULT_SCORER)
+	Scorer string `json:"scorer" example:"weighted"`
+	// Overrides the configured scorer parameters for this fetch
+	Weights *ScoringWeights `json:"weights,omitempty"`
+	// Packs the best memories until this many tokens instead of taking Limit of them,
+	// the response then carries the tokens used (Default: 0, disabled)
+	MaxTokens int `json:"max_tokens" example:"500"`
+	// How tokens are counted for MaxTokens: chars or words (Default: chars)
+	Tokenizer string `json:"tokenizer" example:"chars"`
+	// Counts the related context in the budget and returns it, it is left out otherwise (Default: false)
+	IncludeRelatedContext bool `json:"include_related_context" example:"true"`
+	// Previous messages of the conversation, oldest first and without Text. They are used to
+	// resolve follow ups such as "what about the other one?" into a useful query
+	Turns []ConversationTurn `json:"turns,omitempty"`
+	// How the query is built from Turns: embedding or rewrite (Default: CONVERSATION_QUERY_STRATEGY)
+	QueryStrategy QueryStrategy `json:"query_strategy" example:"embedding"`
+	// Also searches with a hypothetical memory written by the llm to answer the text, keeping
+	// the best similarity of each memory. Ignored by keyword searches and without an llm (Default: false)
+	Hyde bool `json:"hyde" example:"true"`
+}
+
+// A previous message of the conversation a fetch is part of
+type ConversationTurn struct {
+	// Who sent the message, e.g. user or assistant
+	Role string `json:"role" example:"user"`
+	// The message
+	Content string `json:"content" example:"I have a cat and a dog"`
+}
+
+type QueryStrategy string
+
+const (
+	// Blends the embeddings of the text and the turns, the most recent weighing more
+	QueryStrategyEmbedding QueryStrategy = "embedding"
+	// Asks the llm to rewrite the text as a standalone query, falls back to embedding without it
+	QueryStrategyRewrite QueryStrategy = "rewrite"
+)
+
+// Scorer parameters, the ones left out keep their configured value
+type ScoringWeights struct {
+	// Weight of the search similarity (weighted scorer)
+	Similarity *float32 `json:"similarity,omitempty" example:"0.6"`
+	// Weight of the recency (weighted scorer)
+	Recency *float32 `json:"recency,omitempty" example:"0.25"`
+	// Weight of the access and merge counts (weighted scorer)
+	Usage *float32 `json:"usage,omitempty" example:"0.15"`
+	// Hours for the recency to halve (half_life and weighted scorers)
+	HalfLifeHours *float32 `json:"half_life_hours,omitempty" example:"72"`
+}
+
+type FetchMode string
+
+const (
+	// Dense search over the embeddings
+	FetchModeVector FetchMode = "vector"
+	// Lexical search over the memory text
+	FetchModeKeyword FetchMode = "keyword"
+	// Both, fused with reciprocal rank fusion
+	FetchModeHybrid FetchMode = "hybrid"
+)
+
+// Request schema for storing a memory without classification
+type NewMemory struct {
+	// Text of the memory
+	Memory string `json:"memory" example:"The user loves smart LLMs"`
+	// Type of the memory, 1 for short term and 2 for long term
+	MemoryType MemoryTypeEnum `json:"memory_type" example:"2"`
+	// Context that might be related to the memory
+	RelatedContext []MessageRelatedContext `json:"related_context"`
+}
+
+// Result of storing a memory
+type MemoryStoreResult struct {
+	// The id of the resulting memory
+	MemoryId string `json:"memory_id"`
+	// The type of the resulting memory
+	MemoryType MemoryTypeEnum `json:"memory_type"`
+	// Whether a new memory was created
+	Created bool `json:"created"`
+	// Whether the memory was merged into a similar one
+	Merged bool `json:"merged"`
+}
+
+// Request schema for updating a single memory
+type MemoryUpdateRequest struct {
+	// New text of the memory, the memory is re-embedded when it changes
+	Memory string `json:"memory" example:"The user loves smart LLMs"`
+	// Replaces the related context when present
+	RelatedContext []MessageRelatedContext `json:"related_context"`
 }
 
 // Result of the memory management
@@ -0,0 +1,23 @@
+package core
+
+import "time"
+
+// A message merged into a short term memory
+type MemoryMerge struct {
+	Id       string `json:"id"`
+	MemoryId string `json:"memory_id"`
+	// The memory before the merge, restored by a revert
+	PreviousMemory         string                  `json:"previous_memory"`
+	PreviousRelatedContext []MessageRelatedContext `json:"previous_related_context"`
+	// The text that replaced it
+	IncomingMemory string `json:"incoming_memory"`
+	// Similarity between the memory and the incoming text
+	Score     float32   `json:"score"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Every merge of a memory, oldest first
+type MemoryMergeHistory struct {
+	MemoryId string         `json:"memory_id"`
+	Merges   []*MemoryMerge `json:"merges"`
+}
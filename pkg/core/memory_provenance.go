@@ -0,0 +1,22 @@
+package core
+
+import "time"
+
+// A message a memory came from
+type MemorySource struct {
+	MessageId string `json:"message_id"`
+	// The text as it was sent, before any enhancement
+	Message        string                  `json:"message"`
+	RelatedContext []MessageRelatedContext `json:"related_context"`
+	// stored when the message created the memory,
+	// merged when it was merged into it
+	Status MessageTaskStatus `json:"status"`
+	// When the message was sent
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// The messages behind a memory, oldest first
+type MemoryProvenance struct {
+	MemoryId string         `json:"memory_id"`
+	Sources  []MemorySource `json:"sources"`
+}
@@ -1,5 +1,7 @@
 package core
 
+import "time"
+
 // Additional textual context that might be related to the memory
 type MessageRelatedContext struct {
 	// The text that was used to generate the memory
@@ -19,8 +21,114 @@ type NewMessage struct {
 	RelatedContext []MessageRelatedContext `json:"related_context"`
 }
 
+// Several messages sent at once, possibly across chats
+type NewMessageBatch struct {
+	Messages []NewMessage `json:"messages"`
+}
+
+// The outcome of queueing one message of a batch
+type MessageBatchItem struct {
+	ChatId string `json:"chat_id"`
+	// The task tracking the message, empty if it was not queued
+	TaskId string `json:"task_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
 type LabeledMessage struct {
 	NewMessage       `json:"new_message"`
 	Label            MemoryTypeEnum `json:"label"`
 	MessageEmbedding []float32      `json:"message_embedding"`
 }
+
+type MessageTaskStatus string
+
+const (
+	// Waiting for the worker
+	MessageQueued MessageTaskStatus = "queued"
+	// Picked up by the worker, being classified and stored
+	MessageClassifying MessageTaskStatus = "classifying"
+	// Stored as a new memory
+	MessageStored MessageTaskStatus = "stored"
+	// Absorbed by a similar existing memory
+	MessageMerged MessageTaskStatus = "merged"
+	// Left as is, a similar existing memory already covers it
+	MessageCovered MessageTaskStatus = "covered"
+	// Classified as NoMemory, not worth remembering
+	MessageDiscarded MessageTaskStatus = "discarded"
+	// The last attempt failed and the worker will try again, see the error
+	MessageRetrying MessageTaskStatus = "retrying"
+	// Processing failed and no attempt is left, see the error
+	MessageFailed MessageTaskStatus = "failed"
+)
+
+// IsFinished tells if the worker is done with the message
+func (s MessageTaskStatus) IsFinished() bool {
+	return s != MessageQueued && s != MessageClassifying && s != MessageRetrying
+}
+
+// What happened to a message sent for processing
+type MessageTask struct {
+	Id     string            `json:"id"`
+	ChatId string            `json:"chat_id"`
+	Status MessageTaskStatus `json:"status"`
+	// The classification of the message
+	Label MemoryTypeEnum `json:"label"`
+	// Whether the message was merged into an existing memory
+	Merged bool `json:"merged"`
+	// The memory the message was stored as or merged into
+	MemoryId string `json:"memory_id,omitempty"`
+	// The error of the last attempt
+	Error string `json:"error,omitempty"`
+	// How many times the worker picked the message up
+	Attempts  int       `json:"attempts"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// An ingested message as kept in the chat transcript
+type Message struct {
+	Id             string                  `json:"id"`
+	ChatId         string                  `json:"chat_id"`
+	Message        string                  `json:"message"`
+	RelatedContext []MessageRelatedContext `json:"related_context"`
+	Status         MessageTaskStatus       `json:"status"`
+	// The predicted label
+	Label MemoryTypeEnum `json:"label"`
+	// Whether the message was merged into an existing memory
+	Merged bool `json:"merged"`
+	// The memory the message produced or was merged into
+	MemoryId  string    `json:"memory_id,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Attempts  int       `json:"attempts"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Task returns the processing state of the message
+func (m *Message) Task() *MessageTask {
+	return &MessageTask{
+		Id:        m.Id,
+		ChatId:    m.ChatId,
+		Status:    m.Status,
+		Label:     m.Label,
+		Merged:    m.Merged,
+		MemoryId:  m.MemoryId,
+		Error:     m.Error,
+		Attempts:  m.Attempts,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+type MessageArray struct {
+	Messages []*Message `json:"messages"`
+	Total    int        `json:"total"`
+}
+
+// The outcome of processing a message
+type MessageTaskResult struct {
+	Status   MessageTaskStatus
+	Label    MemoryTypeEnum
+	MemoryId string
+	Error    string
+}
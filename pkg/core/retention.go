@@ -0,0 +1,37 @@
+package core
+
+import "time"
+
+// What the retention purge deleted
+type RetentionPurgeStats struct {
+	ShortTermMemories int `json:"short_term_memories"`
+	LongTermMemories  int `json:"long_term_memories"`
+	Vectors           int `json:"vectors"`
+	MemoryMerges      int `json:"memory_merges"`
+	// Related context no longer attached to any memory, message or merge
+	OrphanedRelatedContexts int `json:"orphaned_related_contexts"`
+}
+
+// Add sums the other stats into these
+func (s *RetentionPurgeStats) Add(other RetentionPurgeStats) {
+	s.ShortTermMemories += other.ShortTermMemories
+	s.LongTermMemories += other.LongTermMemories
+	s.Vectors += other.Vectors
+	s.MemoryMerges += other.MemoryMerges
+	s.OrphanedRelatedContexts += other.OrphanedRelatedContexts
+}
+
+// A run of the retention purge over every tenant
+type RetentionRun struct {
+	Id string `json:"id"`
+	// How many days a memory stays inactive before being deleted
+	RetentionDays int `json:"retention_days"`
+	// Memories deactivated before this were deleted
+	Cutoff  time.Time           `json:"cutoff"`
+	Tenants int                 `json:"tenants"`
+	Deleted RetentionPurgeStats `json:"deleted"`
+	// Why the run stopped early, what was deleted until then is kept
+	Error       string    `json:"error,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+}
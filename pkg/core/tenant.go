@@ -0,0 +1,59 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+// Tenant that owns everything created without an explicit tenant,
+// such as requests made with the root key or with auth disabled
+const DefaultTenantId = "default"
+
+// Limits applied to a tenant, zero means unlimited
+type TenantQuota struct {
+	// Max number of chats the tenant can create
+	MaxChats int `json:"max_chats" example:"100"`
+	// Max number of active memories, short and long term, the tenant can hold
+	MaxMemories int `json:"max_memories" example:"10000"`
+}
+
+// Request schema for creating a tenant
+type NewTenant struct {
+	// Unique identifier of the tenant
+	Id string `json:"id" example:"acme"`
+	// A name to identify the tenant
+	Name string `json:"name" example:"Acme Inc."`
+	// Limits applied to the tenant, the configured defaults are used when omitted
+	Quota *TenantQuota `json:"quota"`
+}
+
+type Tenant struct {
+	Id        string      `json:"id"`
+	Name      string      `json:"name"`
+	Quota     TenantQuota `json:"quota"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// Current consumption of a tenant, to be compared against its quota
+type TenantUsage struct {
+	TenantId string      `json:"tenant_id"`
+	Chats    int         `json:"chats"`
+	Memories int         `json:"memories"`
+	Quota    TenantQuota `json:"quota"`
+}
+
+type tenantContextKey struct{}
+
+// WithTenant returns a copy of ctx that scopes repository calls to the tenant
+func WithTenant(ctx context.Context, tenantId string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantId)
+}
+
+// TenantFromContext returns the tenant ctx is scoped to,
+// falling back to the default tenant
+func TenantFromContext(ctx context.Context) string {
+	if tenantId, ok := ctx.Value(tenantContextKey{}).(string); ok && tenantId != "" {
+		return tenantId
+	}
+	return DefaultTenantId
+}
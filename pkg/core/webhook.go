@@ -0,0 +1,116 @@
+package core
+
+import "time"
+
+type MemoryEventType string
+
+const (
+	// A memory was stored
+	MemoryCreatedEvent MemoryEventType = "memory.created"
+	// A message was merged into a short term memory
+	MemoryMergedEvent MemoryEventType = "memory.merged"
+	// A short term memory became a long term memory
+	MemoryPromotedEvent MemoryEventType = "memory.promoted"
+	// A memory was deactivated through the api
+	MemoryDeactivatedEvent MemoryEventType = "memory.deactivated"
+	// A short term memory was discarded by the memory management
+	MemoryDiscardedEvent MemoryEventType = "memory.discarded"
+)
+
+// All the events a webhook can listen to
+var MemoryEventTypes = []MemoryEventType{
+	MemoryCreatedEvent,
+	MemoryMergedEvent,
+	MemoryPromotedEvent,
+	MemoryDeactivatedEvent,
+	MemoryDiscardedEvent,
+}
+
+// IsValidMemoryEventType tells if the event type is one of the known types
+func IsValidMemoryEventType(eventType MemoryEventType) bool {
+	for _, t := range MemoryEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Something that happened to a memory, sent to the webhooks and event streams
+type MemoryEvent struct {
+	Id string `json:"id"`
+	// Position of the event in the event log, streams resume after it
+	Cursor   int64           `json:"cursor"`
+	Type     MemoryEventType `json:"type"`
+	TenantId string          `json:"tenant_id"`
+	// The external id of the chat
+	ChatId string `json:"chat_id"`
+	// The memory the event is about, empty when every memory
+	// of the chat was deactivated at once
+	MemoryId   string         `json:"memory_id,omitempty"`
+	MemoryType MemoryTypeEnum `json:"memory_type,omitempty"`
+	// The memory text after the event
+	Memory string `json:"memory,omitempty"`
+	// The short term memory a promoted memory came from
+	PromotedFrom string    `json:"promoted_from,omitempty"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+// Request schema for registering a webhook
+type NewWebhook struct {
+	// Where the events are posted to
+	Url string `json:"url" example:"https://example.com/better-mem"`
+	// The external id of the chat to listen to, every chat when empty
+	ChatId string `json:"chat_id" example:"1234567890"`
+	// The events to listen to, every event when empty
+	Events []MemoryEventType `json:"events" example:"memory.created,memory.promoted"`
+}
+
+type Webhook struct {
+	Id       string `json:"id"`
+	TenantId string `json:"tenant_id"`
+	// The chat listened to, every chat when empty
+	ChatId string `json:"chat_id"`
+	Url    string `json:"url"`
+	// The events listened to, every event when empty
+	Events    []MemoryEventType `json:"events"`
+	CreatedAt time.Time         `json:"created_at"`
+	// Signs the deliveries, only visible when registered
+	Secret string `json:"-"`
+}
+
+// ListensTo tells if the webhook should receive the event type
+func (w *Webhook) ListensTo(eventType MemoryEventType) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// A newly registered webhook, the only moment the secret is visible
+type RegisteredWebhook struct {
+	Webhook
+	// Key of the HMAC-SHA256 sent in the X-BetterMem-Signature header
+	Secret string `json:"secret"`
+}
+
+// A single attempt at delivering an event to a webhook
+type WebhookDelivery struct {
+	Id        string          `json:"id"`
+	WebhookId string          `json:"webhook_id"`
+	EventId   string          `json:"event_id"`
+	EventType MemoryEventType `json:"event_type"`
+	Attempt   int             `json:"attempt"`
+	// Status code of the response, 0 when there was no response
+	StatusCode int    `json:"status_code"`
+	Success    bool   `json:"success"`
+	Error      string `json:"error,omitempty"`
+	// How long the request took, in milliseconds
+	Duration  int64     `json:"duration_ms"`
+	CreatedAt time.Time `json:"created_at"`
+}
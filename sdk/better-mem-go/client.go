@@ -10,17 +10,55 @@ import (
 	"github.com/Mateus-Lacerda/better-mem/pkg/core"
 )
 
-// TODO: Add oauth
 type BetterMemClient struct {
 	baseUrl    string
+	apiKey     string
 	httpClient *http.Client
 }
 
-func NewBetterMemClient(baseUrl string) *BetterMemClient {
-	return &BetterMemClient{
+type Option func(*BetterMemClient)
+
+// WithApiKey authenticates every request with the given api key
+func WithApiKey(apiKey string) Option {
+	return func(c *BetterMemClient) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithHttpClient replaces the default http client
+func WithHttpClient(httpClient *http.Client) Option {
+	return func(c *BetterMemClient) {
+		c.httpClient = httpClient
+	}
+}
+
+func NewBetterMemClient(baseUrl string, options ...Option) *BetterMemClient {
+	client := &BetterMemClient{
 		baseUrl:    baseUrl,
 		httpClient: &http.Client{},
 	}
+	for _, option := range options {
+		option(client)
+	}
+	return client
+}
+
+func (c *BetterMemClient) do(
+	method string, path string, body []byte,
+) (*http.Response, error) {
+	req, err := http.NewRequest(
+		method,
+		fmt.Sprintf("%s%s", c.baseUrl, path),
+		bytes.NewBuffer(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	return c.httpClient.Do(req)
 }
 
 func (c *BetterMemClient) CreateChat(externalId string) error {
@@ -30,11 +68,7 @@ func (c *BetterMemClient) CreateChat(externalId string) error {
 		return err
 	}
 
-	resp, err := c.httpClient.Post(
-		fmt.Sprintf("%s/chat", c.baseUrl),
-		"application/json",
-		bytes.NewBuffer(body),
-	)
+	resp, err := c.do(http.MethodPost, "/chat", body)
 	if err != nil {
 		return err
 	}
@@ -67,11 +101,7 @@ func (c *BetterMemClient) SendMessage(
 	}
 
 	send := func() (*http.Response, error) {
-		return c.httpClient.Post(
-			fmt.Sprintf("%s/message", c.baseUrl),
-			"application/json",
-			bytes.NewBuffer(body),
-		)
+		return c.do(http.MethodPost, "/message", body)
 	}
 
 	resp, err := send()
@@ -97,6 +127,42 @@ func (c *BetterMemClient) SendMessage(
 	return nil
 }
 
+// SendMessages sends several messages at once, returning
+// what happened to each of them in the same order
+func (c *BetterMemClient) SendMessages(
+	messages []core.NewMessage,
+) ([]core.MessageBatchItem, error) {
+	req := core.NewMessageBatch{Messages: messages}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(http.MethodPost, "/message/batch", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf(
+			"error sending messages: %s - %s",
+			resp.Status,
+			string(bodyBytes),
+		)
+	}
+
+	var result struct {
+		Items []core.MessageBatchItem `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result.Items, nil
+}
+
 func (c *BetterMemClient) FetchMemories(
 	chatID string, req core.MemoryFetchRequest,
 ) ([]core.ScoredMemory, error) {
@@ -105,10 +171,8 @@ func (c *BetterMemClient) FetchMemories(
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Post(
-		fmt.Sprintf("%s/memory/chat/%s/fetch", c.baseUrl, chatID),
-		"application/json",
-		bytes.NewBuffer(body),
+	resp, err := c.do(
+		http.MethodPost, fmt.Sprintf("/memory/chat/%s/fetch", chatID), body,
 	)
 	if err != nil {
 		return nil, err
@@ -131,3 +195,38 @@ func (c *BetterMemClient) FetchMemories(
 
 	return memories, nil
 }
+
+// FetchMemoriesDetailed runs the same fetch as FetchMemories and returns the
+// whole fetch result instead of only the memories
+func (c *BetterMemClient) FetchMemoriesDetailed(
+	chatID string, req core.MemoryFetchRequest,
+) (*core.MemoryFetchResult, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(
+		http.MethodPost, fmt.Sprintf("/memory/chat/%s/fetch/detailed", chatID), body,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf(
+			"error fetching memories: %s - %s",
+			resp.Status,
+			string(bodyBytes),
+		)
+	}
+
+	var result core.MemoryFetchResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}